@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models/dto"
+	userUsecases "github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/usecases"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/validation"
+)
+
+const createAdminUsage = "usage: main create-admin --email <email> --name <name>; password is read from ADMIN_PASSWORD or stdin"
+
+// runCreateAdmin создаёт первого администратора. Пароль не передаётся флагом,
+// чтобы он не попадал в историю оболочки и список процессов.
+func runCreateAdmin(ctx context.Context, authUseCases *userUsecases.AuthUseCases, args []string) error {
+	flags := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	email := flags.String("email", "", "administrator email")
+	name := flags.String("name", "", "administrator name")
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%w; %s", err, createAdminUsage)
+	}
+
+	password, err := readAdminPassword(os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	request := dto.RegisterUserRequestDTO{
+		Email:    *email,
+		Password: password,
+		Username: *name,
+	}
+	if err := validation.Struct(request); err != nil {
+		return fmt.Errorf("%w; %s", err, createAdminUsage)
+	}
+
+	user, err := authUseCases.CreateAdmin(ctx, userUsecases.NewCreateUserCommand(request.Username, request.Email, request.Password))
+	if err != nil {
+		return fmt.Errorf("failed to create administrator: %w", err)
+	}
+
+	fmt.Printf("created administrator %s <%s> with id %d\n", user.Username, user.Email, user.ID)
+	return nil
+}
+
+func readAdminPassword(stdin io.Reader) (string, error) {
+	if password, ok := os.LookupEnv("ADMIN_PASSWORD"); ok {
+		return password, nil
+	}
+
+	line, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read password from stdin: %w", err)
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadAdminPasswordFromStdin(t *testing.T) {
+	password, err := readAdminPassword(strings.NewReader("secret-password\r\nignored\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "secret-password", password)
+
+	// Последняя строка без перевода строки тоже читается
+	password, err = readAdminPassword(strings.NewReader("secret-password"))
+	assert.NoError(t, err)
+	assert.Equal(t, "secret-password", password)
+}
+
+func TestReadAdminPasswordPrefersEnv(t *testing.T) {
+	t.Setenv("ADMIN_PASSWORD", "from-env")
+
+	password, err := readAdminPassword(strings.NewReader("from-stdin\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "from-env", password)
+}
+
+func TestRunCreateAdminValidatesInput(t *testing.T) {
+	t.Setenv("ADMIN_PASSWORD", "short")
+
+	// Проверка выполняется до обращения к use case, поэтому он не нужен
+	err := runCreateAdmin(context.Background(), nil, []string{"--email", "root@example.com", "--name", "root"})
+	assert.ErrorIs(t, err, common.ErrValidation)
+	assert.ErrorContains(t, err, createAdminUsage)
+
+	t.Setenv("ADMIN_PASSWORD", "secret-password")
+	err = runCreateAdmin(context.Background(), nil, []string{"--name", "root"})
+	assert.ErrorIs(t, err, common.ErrValidation)
+
+	err = runCreateAdmin(context.Background(), nil, []string{"--unknown"})
+	assert.ErrorContains(t, err, createAdminUsage)
+}
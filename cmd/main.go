@@ -1,32 +1,50 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"io/fs"
+	"log/slog"
+	"time"
+
+	accessInfrastructure "github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/access/infrastructure"
+	accessUsecases "github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/access/usecases"
+
+	auditInfrastructure "github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/audit/infrastructure"
+	auditTransport "github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/audit/transport"
+	auditUsecases "github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/audit/usecases"
 
 	userInfrastructure "github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/infrastructure"
 	userTransport "github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/transport"
 	userUsecases "github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/usecases"
 
 	projectInfrastructure "github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/infrastructure"
+	projectModels "github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/models"
 	projectTransport "github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/transport"
 	projectUsecases "github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/usecases"
 
+	"github.com/lunarKettle/task-management-platform-monolith/internal/config"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/database"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/mailer"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/metrics"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/ratelimit"
 	"github.com/lunarKettle/task-management-platform-monolith/internal/server"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/tracing"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/logger"
 
 	"github.com/joho/godotenv"
 
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 
 	_ "github.com/lib/pq"
 )
 
-const (
-	ServerAddressEnv    = "SERVER_ADDRESS"
-	ConnectionStringEnv = "CONNECTION_STRING"
-	SecretKeyEnv        = "SECRET_KEY"
-)
+const purgeInterval = time.Hour
 
 func main() {
 	if err := run(); err != nil {
@@ -35,29 +53,191 @@ func main() {
 }
 
 func run() error {
-	if err := godotenv.Load(); err != nil {
+	// .env необязателен: в контейнере переменные обычно задаются окружением
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
 		return fmt.Errorf("failed to load .env file: %w", err)
 	}
 
-	database, err := sql.Open("postgres", os.Getenv(ConnectionStringEnv))
+	cfg, command, err := config.Load(os.Args[1:], os.LookupEnv)
+	if err != nil {
+		return err
+	}
+
+	if command.PrintConfig {
+		if err := cfg.Print(os.Stdout); err != nil {
+			return err
+		}
+		return cfg.Validate()
+	}
+
+	logLevel, err := cfg.Log.SlogLevel()
+	if err != nil {
+		return err
+	}
+	appLogger := logger.New(os.Stderr, logLevel)
+	slog.SetDefault(appLogger)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx = logger.WithContext(ctx, appLogger)
+
+	db, err := openDatabase(ctx, cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if len(command.Args) > 0 && command.Args[0] == "migrate" {
+		return runMigrate(ctx, db, command.Args[1:])
+	}
+
+	if cfg.Features.MigrateStrict {
+		if err := checkSchema(ctx, db); err != nil {
+			return err
+		}
+	}
+
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing)
 	if err != nil {
-		return fmt.Errorf("failed to open connection: %w", err)
+		return err
 	}
+	defer func() {
+		// ctx к этому моменту уже отменён, а недоотправленные span'ы нужно успеть сбросить
+		flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cfg.Server.ShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			appLogger.Error("failed to shut down tracing", slog.Any("error", err))
+		}
+	}()
+
+	userRepo := userInfrastructure.NewUserRepository(db)
+	sessionRepo := userInfrastructure.NewSessionRepository(db)
+	tokenRepo := userInfrastructure.NewTokenRepository(db)
+	invitationRepo := userInfrastructure.NewInvitationRepository(db)
+	jwtManager := userInfrastructure.NewJWTManager(cfg.Auth.SecretKey, cfg.Auth.AccessTokenTTL)
+	projectRepo := projectInfrastructure.NewProjectRepository(db)
+	accessRepo := accessInfrastructure.NewAccessRepository(db)
+	auditRepo := auditInfrastructure.NewAuditRepository(db)
 
-	userRepo := userInfrastructure.NewUserRepository(database)
-	jwtManager := userInfrastructure.NewJWTManager(os.Getenv(SecretKeyEnv))
-	projectRepo := projectInfrastructure.NewProjectRepository(database)
+	transactor := database.NewTransactor(db)
 
-	authUseCases := userUsecases.NewAuthUseCases(userRepo, jwtManager)
-	projectUseCases := projectUsecases.NewProjectUseCases(projectRepo)
+	appMetrics := metrics.New()
+	appMetrics.RegisterDB(db, "postgres")
+
+	policy := accessUsecases.NewPolicy(accessRepo)
+
+	auditUseCases := auditUsecases.NewAuditUseCases(auditRepo, policy)
+	authUseCases := userUsecases.NewAuthUseCases(userRepo, sessionRepo, tokenRepo, invitationRepo, jwtManager, transactor, newMailer(cfg.Mail), auditUseCases, policy, userUsecases.Settings{
+		RefreshTTL:           cfg.Auth.RefreshTokenTTL,
+		ResetTokenTTL:        cfg.Auth.ResetTokenTTL,
+		VerificationTokenTTL: cfg.Auth.VerificationTokenTTL,
+		InvitationTTL:        cfg.Auth.InvitationTTL,
+		ResetURL:             cfg.Mail.ResetURL,
+		VerifyURL:            cfg.Mail.VerifyURL,
+		InviteURL:            cfg.Mail.InviteURL,
+	})
+
+	if len(command.Args) > 0 && command.Args[0] == "create-admin" {
+		return runCreateAdmin(ctx, authUseCases, command.Args[1:])
+	}
+	projectUseCases := projectUsecases.NewProjectUseCases(projectRepo, transactor, policy, newWorkflow(cfg.Workflow), auditUseCases, appMetrics)
+
+	// Один Store на оба лимита: ключи ограничителей различаются именем
+	limitStore := ratelimit.NewMemoryStore()
+	ipLimiter := ratelimit.New(limitStore, "ip", ratelimit.Limit{Requests: cfg.RateLimit.IPRequests, Per: cfg.RateLimit.IPWindow})
+
+	var accountLimiter userUsecases.RateLimiter
+	if cfg.RateLimit.Enabled {
+		accountLimiter = ratelimit.New(limitStore, "account", ratelimit.Limit{Requests: cfg.RateLimit.AccountRequests, Per: cfg.RateLimit.AccountWindow})
+	}
+	authUseCases.EnableLoginProtection(accountLimiter, userUsecases.Lockout{
+		Threshold: cfg.Auth.LockoutThreshold,
+		Duration:  cfg.Auth.LockoutDuration,
+	})
+
+	if cfg.Features.TrashPurge {
+		go projectUseCases.RunPurgeJob(ctx, cfg.Features.TrashRetention, purgeInterval)
+	}
 
 	authHandlers := userTransport.NewAuthHandlers(authUseCases)
 	projectHandler := projectTransport.NewProjectHandlers(projectUseCases)
+	auditHandlers := auditTransport.NewAuditHandlers(auditUseCases)
+
+	timeouts := server.Timeouts{
+		ReadHeader: cfg.Server.ReadHeaderTimeout,
+		Read:       cfg.Server.ReadTimeout,
+		Write:      cfg.Server.WriteTimeout,
+		Idle:       cfg.Server.IdleTimeout,
+		Shutdown:   cfg.Server.ShutdownTimeout,
+		Request:    cfg.Server.RequestTimeout,
+	}
+
+	server := server.NewServer(cfg.Server.Address, authUseCases.ValidateToken, timeouts, cfg.CORS.AllowedOrigins)
+	server.AddReadinessCheck("database", db.PingContext)
+	server.EnableMetrics(appMetrics)
+	if cfg.RateLimit.Enabled {
+		server.EnableRateLimit(ipLimiter, "/login", "/register", "/password/forgot", "/invitations/accept", "/me/verify-email")
+	}
 
-	server := server.NewServer(os.Getenv(ServerAddressEnv), jwtManager.ValidateToken)
-	fmt.Printf("Starting server at %s\n", server.Address())
-	if err := server.Start(authHandlers, projectHandler); err == nil {
-		fmt.Println("Error")
+	appLogger.Info("starting server", slog.String("address", server.Address()))
+	if err := server.Start(ctx, authHandlers, projectHandler, auditHandlers); err != nil {
+		return err
 	}
+
+	appLogger.Info("server stopped")
 	return nil
 }
+
+// newMailer выбирает реализацию по mail.driver; значение уже проверено config.Validate
+func newMailer(cfg config.MailConfig) userUsecases.Mailer {
+	if cfg.Driver == "smtp" {
+		return mailer.NewSMTPMailer(mailer.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.From,
+		})
+	}
+	return mailer.NewFileMailer(cfg.Dir, cfg.From)
+}
+
+// newWorkflow строит процесс задач из конфигурации, по умолчанию - встроенный
+func newWorkflow(cfg config.WorkflowConfig) *projectModels.Workflow {
+	if len(cfg.Transitions) == 0 {
+		return projectModels.DefaultWorkflow()
+	}
+
+	transitions := make(map[projectModels.TaskStatus][]projectModels.TaskStatus, len(cfg.Transitions))
+	for from, targets := range cfg.Transitions {
+		statuses := make([]projectModels.TaskStatus, len(targets))
+		for i, to := range targets {
+			statuses[i] = projectModels.TaskStatus(to)
+		}
+		transitions[projectModels.TaskStatus(from)] = statuses
+	}
+	return projectModels.NewWorkflow(projectModels.TaskStatus(cfg.Initial), transitions)
+}
+
+// openDatabase открывает пул соединений и проверяет доступность базы
+func openDatabase(ctx context.Context, cfg config.DatabaseConfig) (*sql.DB, error) {
+	database, err := sql.Open("postgres", cfg.ConnectionString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open connection: %w", err)
+	}
+
+	database.SetMaxOpenConns(cfg.MaxOpenConns)
+	database.SetMaxIdleConns(cfg.MaxIdleConns)
+	database.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	database.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	pingCtx, cancel := context.WithTimeout(ctx, cfg.PingTimeout)
+	defer cancel()
+
+	if err := database.PingContext(pingCtx); err != nil {
+		database.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	return database, nil
+}
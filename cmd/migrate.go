@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/migrate"
+	"github.com/lunarKettle/task-management-platform-monolith/migrations"
+)
+
+const migrateUsage = "usage: main migrate up | down [steps] | status | redo | baseline <version>"
+
+func newMigrator(database *sql.DB) (*migrate.Migrator, error) {
+	list, err := migrate.Load(migrations.FS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+	return migrate.NewMigrator(database, list), nil
+}
+
+// runMigrate выполняет подкоманду migrate и печатает результат в stdout
+func runMigrate(ctx context.Context, database *sql.DB, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(migrateUsage)
+	}
+
+	migrator, err := newMigrator(database)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("applied %s_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("schema is up to date")
+		}
+		return err
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid steps %q: %w", args[1], err)
+			}
+		}
+
+		reverted, err := migrator.Down(ctx, steps)
+		for _, migration := range reverted {
+			fmt.Printf("reverted %s_%s\n", migration.Version, migration.Name)
+		}
+		return err
+
+	case "redo":
+		migration, err := migrator.Redo(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("redone %s_%s\n", migration.Version, migration.Name)
+		return nil
+
+	case "baseline":
+		if len(args) < 2 {
+			return fmt.Errorf("missing version, %s", migrateUsage)
+		}
+
+		recorded, err := migrator.Baseline(ctx, args[1])
+		for _, migration := range recorded {
+			fmt.Printf("marked %s_%s as applied\n", migration.Version, migration.Name)
+		}
+		return err
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.Applied {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		return w.Flush()
+
+	default:
+		return fmt.Errorf("unknown migrate command %q, %s", args[0], migrateUsage)
+	}
+}
+
+// checkSchema не даёт запустить сервер на устаревшей схеме
+func checkSchema(ctx context.Context, database *sql.DB) error {
+	migrator, err := newMigrator(database)
+	if err != nil {
+		return err
+	}
+
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(pending) > 0 {
+		return fmt.Errorf("schema is behind by %d migration(s), starting with %s_%s; run \"main migrate up\"",
+			len(pending), pending[0].Version, pending[0].Name)
+	}
+	return nil
+}
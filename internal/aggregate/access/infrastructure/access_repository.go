@@ -0,0 +1,97 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/access/usecases"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/database"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+)
+
+type AccessRepository struct {
+	db *database.DB
+}
+
+func NewAccessRepository(db *sql.DB) *AccessRepository {
+	return &AccessRepository{db: database.New(db)}
+}
+
+// GetRolePermissions получает список прав роли.
+func (r *AccessRepository) GetRolePermissions(ctx context.Context, role string) ([]common.Permission, error) {
+	query := `SELECT permission FROM role_permissions WHERE role = $1`
+
+	rows, err := r.db.QueryContext(ctx, query, role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query role permissions: %w", err)
+	}
+	defer rows.Close()
+
+	var permissions []common.Permission
+	for rows.Next() {
+		var permission string
+		if err := rows.Scan(&permission); err != nil {
+			return nil, fmt.Errorf("failed to scan permission row: %w", err)
+		}
+		permissions = append(permissions, common.Permission(permission))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over permission rows: %w", err)
+	}
+
+	return permissions, nil
+}
+
+// GetMemberships получает команды пользователя и его роли в них.
+func (r *AccessRepository) GetMemberships(ctx context.Context, userID uint32) ([]usecases.Membership, error) {
+	query := `SELECT team_id, role FROM team_memberships WHERE user_id = $1 ORDER BY team_id`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query team memberships: %w", err)
+	}
+	defer rows.Close()
+
+	var memberships []usecases.Membership
+	for rows.Next() {
+		var membership usecases.Membership
+		if err := rows.Scan(&membership.TeamID, &membership.Role); err != nil {
+			return nil, fmt.Errorf("failed to scan membership row: %w", err)
+		}
+		memberships = append(memberships, membership)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over membership rows: %w", err)
+	}
+
+	return memberships, nil
+}
+
+// GetManagedTeamIDs получает ID команд, менеджером которых является пользователь.
+func (r *AccessRepository) GetManagedTeamIDs(ctx context.Context, userID uint32) ([]uint32, error) {
+	query := `SELECT id FROM teams WHERE manager_id = $1 AND deleted_at IS NULL`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query managed teams: %w", err)
+	}
+	defer rows.Close()
+
+	var teamIDs []uint32
+	for rows.Next() {
+		var teamID uint32
+		if err := rows.Scan(&teamID); err != nil {
+			return nil, fmt.Errorf("failed to scan team row: %w", err)
+		}
+		teamIDs = append(teamIDs, teamID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over team rows: %w", err)
+	}
+
+	return teamIDs, nil
+}
@@ -0,0 +1,28 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetManagedTeamIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAccessRepository(db)
+
+	// Удалённые команды не дают прав менеджера
+	mock.ExpectQuery(`SELECT id FROM teams WHERE manager_id = \$1 AND deleted_at IS NULL`).
+		WithArgs(uint32(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(20).AddRow(21))
+
+	teamIDs, err := repo.GetManagedTeamIDs(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{20, 21}, teamIDs)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,19 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+)
+
+// Membership - членство пользователя в команде с ролью, действующей в этой команде
+type Membership struct {
+	TeamID uint32
+	Role   string
+}
+
+type AccessRepository interface {
+	GetRolePermissions(ctx context.Context, role string) ([]common.Permission, error)
+	GetMemberships(ctx context.Context, userID uint32) ([]Membership, error)
+	GetManagedTeamIDs(ctx context.Context, userID uint32) ([]uint32, error)
+}
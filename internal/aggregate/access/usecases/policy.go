@@ -0,0 +1,138 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+)
+
+// Права, которые менеджер команды (Team.ManagerID) получает в своей команде
+// независимо от роли
+var managerPermissions = []common.Permission{
+	common.PermProjectRead,
+	common.PermTeamRead,
+	common.PermTeamManage,
+	common.PermTaskRead,
+	common.PermTaskWrite,
+	common.PermTaskAssign,
+	common.PermMemberRead,
+}
+
+// Policy проверяет права пользователя из контекста запроса.
+// Права роли хранятся в таблице role_permissions. Глобальная роль из токена
+// действует во всех командах, только если у неё есть право scope:all_teams,
+// в остальных случаях в каждой команде пользователя действует роль его членства.
+type Policy struct {
+	repo AccessRepository
+}
+
+func NewPolicy(repo AccessRepository) *Policy {
+	return &Policy{repo: repo}
+}
+
+// Can проверяет, что роль пользователя содержит право permission.
+func (p *Policy) Can(ctx context.Context, permission common.Permission) (bool, error) {
+	claims, err := claimsFromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	permissions, err := p.repo.GetRolePermissions(ctx, claims.Role)
+	if err != nil {
+		return false, fmt.Errorf("failed to get permissions of role %q: %w", claims.Role, err)
+	}
+
+	return slices.Contains(permissions, permission), nil
+}
+
+// Authorize возвращает common.ErrForbidden, если у роли пользователя нет права permission.
+func (p *Policy) Authorize(ctx context.Context, permission common.Permission) error {
+	ok, err := p.Can(ctx, permission)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return common.ErrForbidden
+	}
+
+	return nil
+}
+
+// AuthorizeTeam проверяет право permission в команде teamID.
+func (p *Policy) AuthorizeTeam(ctx context.Context, permission common.Permission, teamID uint32) error {
+	teamIDs, all, err := p.VisibleTeams(ctx, permission)
+	if err != nil {
+		return err
+	}
+
+	if all || (teamID != 0 && slices.Contains(teamIDs, teamID)) {
+		return nil
+	}
+
+	return common.ErrForbidden
+}
+
+// VisibleTeams возвращает команды, в которых у пользователя есть право permission.
+// all == true означает, что право действует во всех командах.
+func (p *Policy) VisibleTeams(ctx context.Context, permission common.Permission) (teamIDs []uint32, all bool, err error) {
+	claims, err := claimsFromContext(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	permissions, err := p.repo.GetRolePermissions(ctx, claims.Role)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get permissions of role %q: %w", claims.Role, err)
+	}
+
+	if slices.Contains(permissions, permission) && slices.Contains(permissions, common.PermAllTeams) {
+		return nil, true, nil
+	}
+
+	memberships, err := p.repo.GetMemberships(ctx, claims.UserID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get memberships of userID %d: %w", claims.UserID, err)
+	}
+
+	rolePermissions := make(map[string][]common.Permission)
+	for _, membership := range memberships {
+		teamPermissions, ok := rolePermissions[membership.Role]
+		if !ok {
+			teamPermissions, err = p.repo.GetRolePermissions(ctx, membership.Role)
+			if err != nil {
+				return nil, false, fmt.Errorf("failed to get permissions of role %q: %w", membership.Role, err)
+			}
+			rolePermissions[membership.Role] = teamPermissions
+		}
+
+		if slices.Contains(teamPermissions, permission) {
+			teamIDs = append(teamIDs, membership.TeamID)
+		}
+	}
+
+	if slices.Contains(managerPermissions, permission) {
+		managed, err := p.repo.GetManagedTeamIDs(ctx, claims.UserID)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get teams managed by userID %d: %w", claims.UserID, err)
+		}
+
+		for _, teamID := range managed {
+			if !slices.Contains(teamIDs, teamID) {
+				teamIDs = append(teamIDs, teamID)
+			}
+		}
+	}
+
+	return teamIDs, false, nil
+}
+
+func claimsFromContext(ctx context.Context) (*common.Claims, error) {
+	claims, ok := ctx.Value(common.ContextKeyClaims).(*common.Claims)
+	if !ok || claims == nil {
+		return nil, common.ErrForbidden
+	}
+	return claims, nil
+}
@@ -0,0 +1,86 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeAccessRepository struct {
+	permissions map[string][]common.Permission
+	memberships map[uint32][]Membership
+	managed     map[uint32][]uint32
+}
+
+func (r *fakeAccessRepository) GetRolePermissions(_ context.Context, role string) ([]common.Permission, error) {
+	return r.permissions[role], nil
+}
+
+func (r *fakeAccessRepository) GetMemberships(_ context.Context, userID uint32) ([]Membership, error) {
+	return r.memberships[userID], nil
+}
+
+func (r *fakeAccessRepository) GetManagedTeamIDs(_ context.Context, userID uint32) ([]uint32, error) {
+	return r.managed[userID], nil
+}
+
+func newTestPolicy() *Policy {
+	return NewPolicy(&fakeAccessRepository{
+		permissions: map[string][]common.Permission{
+			common.RoleAdmin:  {common.PermTaskWrite, common.PermAllTeams},
+			common.RoleMember: {common.PermTaskRead},
+			common.RoleViewer: {common.PermProjectRead},
+		},
+		memberships: map[uint32][]Membership{
+			2: {{TeamID: 10, Role: common.RoleMember}},
+			3: {{TeamID: 20, Role: common.RoleMember}},
+			4: {{TeamID: 10, Role: common.RoleMember}, {TeamID: 20, Role: common.RoleViewer}},
+		},
+		managed: map[uint32][]uint32{3: {20}},
+	})
+}
+
+func contextWithClaims(userID uint32, role string) context.Context {
+	return context.WithValue(context.Background(), common.ContextKeyClaims, &common.Claims{UserID: userID, Role: role})
+}
+
+func TestAuthorizeTeam(t *testing.T) {
+	policy := newTestPolicy()
+
+	// Администратор имеет право во всех командах
+	assert.NoError(t, policy.AuthorizeTeam(contextWithClaims(1, common.RoleAdmin), common.PermTaskWrite, 10))
+
+	// Право роли действует только в своей команде
+	assert.NoError(t, policy.AuthorizeTeam(contextWithClaims(2, common.RoleMember), common.PermTaskRead, 10))
+	assert.ErrorIs(t, policy.AuthorizeTeam(contextWithClaims(2, common.RoleMember), common.PermTaskRead, 20), common.ErrForbidden)
+	assert.ErrorIs(t, policy.AuthorizeTeam(contextWithClaims(2, common.RoleMember), common.PermTaskWrite, 10), common.ErrForbidden)
+
+	// Менеджер команды управляет задачами своей команды без глобальных прав
+	assert.NoError(t, policy.AuthorizeTeam(contextWithClaims(3, common.RoleMember), common.PermTaskWrite, 20))
+	assert.ErrorIs(t, policy.AuthorizeTeam(contextWithClaims(3, common.RoleMember), common.PermTaskWrite, 10), common.ErrForbidden)
+}
+
+func TestVisibleTeamsUsesMembershipRoles(t *testing.T) {
+	policy := newTestPolicy()
+	ctx := contextWithClaims(4, common.RoleMember)
+
+	// В каждой команде действует роль членства, а не глобальная роль
+	teamIDs, all, err := policy.VisibleTeams(ctx, common.PermTaskRead)
+	assert.NoError(t, err)
+	assert.False(t, all)
+	assert.Equal(t, []uint32{10}, teamIDs)
+
+	teamIDs, _, err = policy.VisibleTeams(ctx, common.PermProjectRead)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{20}, teamIDs)
+
+	assert.ErrorIs(t, policy.AuthorizeTeam(ctx, common.PermTaskRead, 20), common.ErrForbidden)
+}
+
+func TestAuthorizeWithoutClaims(t *testing.T) {
+	policy := newTestPolicy()
+
+	assert.ErrorIs(t, policy.Authorize(context.Background(), common.PermTaskRead), common.ErrForbidden)
+}
@@ -0,0 +1,189 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/audit/models"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/audit/usecases"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/database"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+)
+
+type AuditRepository struct {
+	db *database.DB
+}
+
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{db: database.New(db)}
+}
+
+func (r *AuditRepository) CreateEvent(ctx context.Context, event *models.Event) error {
+	query := `
+	INSERT INTO audit_events (actor_id, action, entity_type, entity_id, before, after, request_id)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		nullUint32(event.ActorID),
+		event.Action,
+		event.EntityType,
+		event.EntityID,
+		nullJSON(event.Before),
+		nullJSON(event.After),
+		nullString(event.RequestID),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert audit event: %w", err)
+	}
+
+	return nil
+}
+
+// GetEvents возвращает события от новых к старым. Курсор указывает на ID последнего события страницы.
+func (r *AuditRepository) GetEvents(ctx context.Context, filter usecases.EventFilter, page common.PageRequest) (*common.Page[*models.Event], error) {
+	var whereClauses []string
+	var args []interface{}
+
+	if filter.EntityType != "" {
+		whereClauses = append(whereClauses, fmt.Sprintf("entity_type = $%d", len(args)+1))
+		args = append(args, filter.EntityType)
+	}
+	if filter.EntityID != 0 {
+		whereClauses = append(whereClauses, fmt.Sprintf("entity_id = $%d", len(args)+1))
+		args = append(args, filter.EntityID)
+	}
+	if filter.ActorID != 0 {
+		whereClauses = append(whereClauses, fmt.Sprintf("actor_id = $%d", len(args)+1))
+		args = append(args, filter.ActorID)
+	}
+	if !filter.From.IsZero() {
+		whereClauses = append(whereClauses, fmt.Sprintf("created_at >= $%d", len(args)+1))
+		args = append(args, filter.From)
+	}
+	if !filter.To.IsZero() {
+		whereClauses = append(whereClauses, fmt.Sprintf("created_at < $%d", len(args)+1))
+		args = append(args, filter.To)
+	}
+
+	var totalCount int
+	countQuery := `SELECT COUNT(*) FROM audit_events ` + whereSQL(whereClauses)
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&totalCount); err != nil {
+		return nil, fmt.Errorf("failed to count audit events: %w", err)
+	}
+
+	direction, operator := "ASC", ">"
+	if page.Desc {
+		direction, operator = "DESC", "<"
+	}
+
+	if page.Cursor != nil {
+		whereClauses = append(whereClauses, fmt.Sprintf("id %s $%d", operator, len(args)+1))
+		args = append(args, page.Cursor.ID)
+	}
+
+	query := fmt.Sprintf(`
+	SELECT 
+		id, 
+		actor_id, 
+		action, 
+		entity_type, 
+		entity_id, 
+		before, 
+		after, 
+		request_id, 
+		created_at
+	FROM 
+		audit_events
+	%s
+	ORDER BY id %s`, whereSQL(whereClauses), direction)
+
+	if page.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", page.Limit+1)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*models.Event
+	for rows.Next() {
+		var (
+			event     models.Event
+			actorID   sql.NullInt64
+			before    []byte
+			after     []byte
+			requestID sql.NullString
+		)
+
+		err := rows.Scan(
+			&event.ID,
+			&actorID,
+			&event.Action,
+			&event.EntityType,
+			&event.EntityID,
+			&before,
+			&after,
+			&requestID,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit event row: %w", err)
+		}
+
+		event.ActorID = uint32(actorID.Int64)
+		event.Before = before
+		event.After = after
+		event.RequestID = requestID.String
+
+		events = append(events, &event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over audit event rows: %w", err)
+	}
+
+	result := &common.Page[*models.Event]{
+		Items:      events,
+		TotalCount: totalCount,
+	}
+
+	if page.Limit > 0 && len(events) > page.Limit {
+		result.Items = events[:page.Limit]
+
+		last := result.Items[page.Limit-1]
+		result.NextCursor = common.EncodeCursor(common.Cursor{
+			Sort:  page.SortKey(),
+			Value: strconv.FormatUint(uint64(last.ID), 10),
+			ID:    last.ID,
+		})
+	}
+
+	return result, nil
+}
+
+func whereSQL(whereClauses []string) string {
+	if len(whereClauses) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(whereClauses, " AND ")
+}
+
+func nullUint32(value uint32) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(value), Valid: value != 0}
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+func nullJSON(value []byte) interface{} {
+	if value == nil {
+		return nil
+	}
+	return string(value)
+}
@@ -0,0 +1,71 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/audit/models"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/audit/usecases"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAuditRepository(db)
+
+	event := &models.Event{
+		ActorID:    1,
+		Action:     "task.update",
+		EntityType: "task",
+		EntityID:   5,
+		Before:     json.RawMessage(`{"Status":"todo"}`),
+		After:      json.RawMessage(`{"Status":"done"}`),
+		RequestID:  "req-1",
+	}
+
+	mock.ExpectExec(`INSERT INTO audit_events`).
+		WithArgs(int64(1), "task.update", "task", uint32(5), `{"Status":"todo"}`, `{"Status":"done"}`, "req-1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, repo.CreateEvent(context.Background(), event))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAuditRepository(db)
+
+	from := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+	filter := usecases.EventFilter{EntityType: "task", ActorID: 1, From: from}
+
+	rows := sqlmock.NewRows([]string{"id", "actor_id", "action", "entity_type", "entity_id", "before", "after", "request_id", "created_at"}).
+		AddRow(9, 1, "task.update", "task", 5, []byte(`{"Status":"todo"}`), []byte(`{"Status":"done"}`), "req-2", time.Now()).
+		AddRow(8, nil, "task.create", "task", 5, nil, []byte(`{"Title":"Fix"}`), nil, time.Now())
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM audit_events WHERE entity_type = \$1 AND actor_id = \$2 AND created_at >= \$3`).
+		WithArgs("task", uint32(1), from).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+	mock.ExpectQuery(`SELECT .* FROM audit_events WHERE entity_type = \$1 AND actor_id = \$2 AND created_at >= \$3 AND id < \$4 ORDER BY id DESC LIMIT 2`).
+		WithArgs("task", uint32(1), from, uint32(10)).
+		WillReturnRows(rows)
+
+	page, err := repo.GetEvents(context.Background(), filter, common.PageRequest{Limit: 1, Sort: "id", Desc: true, Cursor: &common.Cursor{Sort: "-id", ID: 10}})
+	assert.NoError(t, err)
+	assert.Equal(t, 12, page.TotalCount)
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, uint32(9), page.Items[0].ID)
+	assert.JSONEq(t, `{"Status":"done"}`, string(page.Items[0].After))
+	assert.NotEmpty(t, page.NextCursor)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
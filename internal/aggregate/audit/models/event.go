@@ -0,0 +1,20 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event - запись журнала аудита об изменении сущности.
+// Before и After содержат только изменившиеся поля.
+type Event struct {
+	ID         uint32
+	ActorID    uint32
+	Action     string
+	EntityType string
+	EntityID   uint32
+	Before     json.RawMessage
+	After      json.RawMessage
+	RequestID  string
+	CreatedAt  time.Time
+}
@@ -0,0 +1,121 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/audit/transport/dto"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/audit/usecases"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+)
+
+type AuditHandlers struct {
+	usecases *usecases.AuditUseCases
+}
+
+func NewAuditHandlers(usecases *usecases.AuditUseCases) *AuditHandlers {
+	return &AuditHandlers{
+		usecases: usecases,
+	}
+}
+
+type handler = func(w http.ResponseWriter, r *http.Request) error
+
+func (h *AuditHandlers) RegisterRoutes(mux *http.ServeMux, errorHandler func(handler) http.Handler) {
+	mux.Handle("GET /audit", errorHandler(h.getEvents))
+}
+
+func (h *AuditHandlers) getEvents(w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+
+	filter, err := parseEventFilter(query)
+	if err != nil {
+		return err
+	}
+
+	page, err := common.ParsePageRequest(query, "-id", usecases.EventSortFields)
+	if err != nil {
+		return err
+	}
+
+	events, err := h.usecases.GetEvents(r.Context(), filter, page)
+	if err != nil {
+		return err
+	}
+
+	responseData := dto.EventsResponseDTO{
+		Items:      make([]dto.EventResponseDTO, len(events.Items)),
+		NextCursor: events.NextCursor,
+		TotalCount: events.TotalCount,
+	}
+	for i, v := range events.Items {
+		responseData.Items[i] = dto.EventResponseDTO{
+			ID:         v.ID,
+			ActorID:    v.ActorID,
+			Action:     v.Action,
+			EntityType: v.EntityType,
+			EntityID:   v.EntityID,
+			Before:     v.Before,
+			After:      v.After,
+			RequestID:  v.RequestID,
+			CreatedAt:  v.CreatedAt,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(responseData); err != nil {
+		return fmt.Errorf("failed to encode audit events to JSON: %w", err)
+	}
+
+	return nil
+}
+
+func parseEventFilter(query url.Values) (usecases.EventFilter, error) {
+	filter := usecases.EventFilter{EntityType: query.Get("entity_type")}
+
+	entityID, err := parseUint32(query.Get("entity_id"))
+	if err != nil {
+		return filter, fmt.Errorf("%w: invalid entity_id: %v", common.ErrInvalidInput, err)
+	}
+	filter.EntityID = entityID
+
+	actorID, err := parseUint32(query.Get("actor_id"))
+	if err != nil {
+		return filter, fmt.Errorf("%w: invalid actor_id: %v", common.ErrInvalidInput, err)
+	}
+	filter.ActorID = actorID
+
+	filter.From, err = parseTime(query.Get("from"))
+	if err != nil {
+		return filter, fmt.Errorf("%w: invalid from: %v", common.ErrInvalidInput, err)
+	}
+
+	filter.To, err = parseTime(query.Get("to"))
+	if err != nil {
+		return filter, fmt.Errorf("%w: invalid to: %v", common.ErrInvalidInput, err)
+	}
+
+	return filter, nil
+}
+
+func parseUint32(value string) (uint32, error) {
+	if value == "" {
+		return 0, nil
+	}
+	parsed, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(parsed), nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -0,0 +1,24 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type EventResponseDTO struct {
+	ID         uint32          `json:"id"`
+	ActorID    uint32          `json:"actor_id,omitempty"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   uint32          `json:"entity_id"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	RequestID  string          `json:"request_id,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type EventsResponseDTO struct {
+	Items      []EventResponseDTO `json:"items"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+	TotalCount int                `json:"total_count"`
+}
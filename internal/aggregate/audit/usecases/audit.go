@@ -0,0 +1,79 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/audit/models"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+)
+
+type AuditUseCases struct {
+	repo   AuditRepository
+	policy Policy
+}
+
+func NewAuditUseCases(repo AuditRepository, policy Policy) *AuditUseCases {
+	return &AuditUseCases{
+		repo:   repo,
+		policy: policy,
+	}
+}
+
+// Record сохраняет событие action над сущностью entityType, в журнал попадает действие вида "task.update".
+// Автор и ID запроса берутся из контекста. Вызывается внутри транзакции изменения: репозиторий
+// использует транзакцию из ctx, поэтому событие фиксируется или откатывается вместе с изменением,
+// а ошибка записи отменяет всю операцию.
+func (uc *AuditUseCases) Record(ctx context.Context, entityType, action string, entityID uint32, before, after any) error {
+	beforeJSON, afterJSON, err := diff(before, after)
+	if err != nil {
+		return fmt.Errorf("failed to build audit diff: %w", err)
+	}
+
+	event := &models.Event{
+		Action:     entityType + "." + action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     beforeJSON,
+		After:      afterJSON,
+	}
+
+	if claims, ok := ctx.Value(common.ContextKeyClaims).(*common.Claims); ok {
+		event.ActorID = claims.UserID
+	}
+
+	if requestID, ok := ctx.Value(common.ContextKeyRequestID).(string); ok {
+		event.RequestID = requestID
+	}
+
+	if err := uc.repo.CreateEvent(ctx, event); err != nil {
+		return fmt.Errorf("failed to save audit event %s: %w", event.Action, err)
+	}
+
+	return nil
+}
+
+// EventSortFields - поля, по которым можно сортировать журнал; по умолчанию новые события идут первыми
+var EventSortFields = []string{"id"}
+
+type EventFilter struct {
+	EntityType string
+	EntityID   uint32
+	ActorID    uint32
+	From       time.Time
+	To         time.Time
+}
+
+func (uc *AuditUseCases) GetEvents(ctx context.Context, filter EventFilter, page common.PageRequest) (*common.Page[*models.Event], error) {
+	if err := uc.policy.Authorize(ctx, common.PermAuditRead); err != nil {
+		return nil, err
+	}
+
+	events, err := uc.repo.GetEvents(ctx, filter, page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit events: %w", err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,13 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/audit/models"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+)
+
+type AuditRepository interface {
+	CreateEvent(ctx context.Context, event *models.Event) error
+	GetEvents(ctx context.Context, filter EventFilter, page common.PageRequest) (*common.Page[*models.Event], error)
+}
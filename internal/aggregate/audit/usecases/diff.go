@@ -0,0 +1,66 @@
+package usecases
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// diff сериализует состояния сущности до и после изменения
+// и оставляет в них только поля, значения которых различаются.
+func diff(before, after any) (json.RawMessage, json.RawMessage, error) {
+	beforeFields, err := toFields(before)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	afterFields, err := toFields(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if beforeFields != nil && afterFields != nil {
+		for key, value := range beforeFields {
+			if other, ok := afterFields[key]; ok && reflect.DeepEqual(value, other) {
+				delete(beforeFields, key)
+				delete(afterFields, key)
+			}
+		}
+	}
+
+	beforeJSON, err := marshalFields(beforeFields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	afterJSON, err := marshalFields(afterFields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return beforeJSON, afterJSON, nil
+}
+
+func toFields(value any) (map[string]any, error) {
+	if value == nil || reflect.ValueOf(value).Kind() == reflect.Pointer && reflect.ValueOf(value).IsNil() {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+func marshalFields(fields map[string]any) (json.RawMessage, error) {
+	if fields == nil {
+		return nil, nil
+	}
+	return json.Marshal(fields)
+}
@@ -0,0 +1,37 @@
+package usecases
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type entity struct {
+	Name   string
+	Status string
+	Tags   []string
+}
+
+func TestDiffKeepsOnlyChangedFields(t *testing.T) {
+	before, after, err := diff(
+		&entity{Name: "Billing", Status: "active", Tags: []string{"a"}},
+		&entity{Name: "Billing", Status: "closed", Tags: []string{"a"}},
+	)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"Status":"active"}`, string(before))
+	assert.JSONEq(t, `{"Status":"closed"}`, string(after))
+}
+
+func TestDiffCreateAndDelete(t *testing.T) {
+	// При создании нет состояния "до", при удалении - состояния "после"
+	before, after, err := diff(nil, &entity{Name: "Billing"})
+	assert.NoError(t, err)
+	assert.Nil(t, before)
+	assert.JSONEq(t, `{"Name":"Billing","Status":"","Tags":null}`, string(after))
+
+	var deleted *entity
+	before, after, err = diff(&entity{Name: "Billing"}, deleted)
+	assert.NoError(t, err)
+	assert.NotNil(t, before)
+	assert.Nil(t, after)
+}
@@ -0,0 +1,11 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+)
+
+type Policy interface {
+	Authorize(ctx context.Context, permission common.Permission) error
+}
@@ -0,0 +1,164 @@
+package infrastructure
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/models"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+)
+
+var projectSortColumns = map[string]string{
+	"id":               "p.id",
+	"name":             "p.name",
+	"start_date":       "p.start_date",
+	"planned_end_date": "p.planned_end_date",
+	"status":           "p.status",
+	"priority":         "p.priority",
+	"budget":           "p.budget",
+}
+
+var teamSortColumns = map[string]string{
+	"id":   "t.id",
+	"name": "t.name",
+}
+
+var memberSortColumns = map[string]string{
+	"id":   "u.id",
+	"name": "u.username",
+}
+
+var taskSortColumns = map[string]string{
+	"id":         "id",
+	"title":      "title",
+	"status":     "status",
+	"priority":   "priority",
+	"due_date":   "COALESCE(due_date, 'infinity'::timestamp)",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// applyKeyset добавляет условие курсора к whereClauses и возвращает ORDER BY и LIMIT.
+// Из базы запрашивается на одну запись больше, чтобы понять, есть ли следующая страница.
+func applyKeyset(
+	page common.PageRequest,
+	columns map[string]string,
+	idColumn string,
+	whereClauses []string,
+	args []interface{}) ([]string, []interface{}, string, error) {
+	sort := page.Sort
+	if sort == "" {
+		sort = "id"
+	}
+
+	column, ok := columns[sort]
+	if !ok {
+		return nil, nil, "", fmt.Errorf("%w: unknown sort field %q", common.ErrInvalidInput, sort)
+	}
+
+	direction, operator := "ASC", ">"
+	if page.Desc {
+		direction, operator = "DESC", "<"
+	}
+
+	if page.Cursor != nil {
+		whereClauses = append(whereClauses, fmt.Sprintf("(%s, %s) %s ($%d, $%d)", column, idColumn, operator, len(args)+1, len(args)+2))
+		args = append(args, page.Cursor.Value, page.Cursor.ID)
+	}
+
+	orderSQL := fmt.Sprintf("ORDER BY %s %s, %s %s", column, direction, idColumn, direction)
+	if page.Limit > 0 {
+		orderSQL += fmt.Sprintf(" LIMIT %d", page.Limit+1)
+	}
+
+	return whereClauses, args, orderSQL, nil
+}
+
+// newPage обрезает лишнюю запись и формирует курсор следующей страницы
+func newPage[T any](items []T, page common.PageRequest, totalCount int, key func(item T, sort string) (string, uint32)) *common.Page[T] {
+	result := &common.Page[T]{
+		Items:      items,
+		TotalCount: totalCount,
+	}
+
+	if page.Limit > 0 && len(items) > page.Limit {
+		result.Items = items[:page.Limit]
+
+		value, id := key(result.Items[page.Limit-1], page.Sort)
+		result.NextCursor = common.EncodeCursor(common.Cursor{
+			Sort:  page.SortKey(),
+			Value: value,
+			ID:    id,
+		})
+	}
+
+	return result
+}
+
+func formatCursorTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+func projectSortValue(project *models.Project, sort string) (string, uint32) {
+	switch sort {
+	case "name":
+		return project.Name, project.Id
+	case "start_date":
+		return formatCursorTime(project.StartDate), project.Id
+	case "planned_end_date":
+		return formatCursorTime(project.PlannedEndDate), project.Id
+	case "status":
+		return project.Status, project.Id
+	case "priority":
+		return strconv.FormatUint(uint64(project.Priority), 10), project.Id
+	case "budget":
+		return strconv.FormatFloat(project.Budget, 'f', -1, 64), project.Id
+	default:
+		return strconv.FormatUint(uint64(project.Id), 10), project.Id
+	}
+}
+
+func teamSortValue(team *models.Team, sort string) (string, uint32) {
+	if sort == "name" {
+		return team.Name, team.ID
+	}
+	return strconv.FormatUint(uint64(team.ID), 10), team.ID
+}
+
+func memberSortValue(member *models.Member, sort string) (string, uint32) {
+	if sort == "name" {
+		return member.Name, member.ID
+	}
+	return strconv.FormatUint(uint64(member.ID), 10), member.ID
+}
+
+func taskSortValue(task *models.Task, sort string) (string, uint32) {
+	switch sort {
+	case "title":
+		return task.Title, task.ID
+	case "status":
+		return string(task.Status), task.ID
+	case "priority":
+		return strconv.FormatUint(uint64(task.Priority), 10), task.ID
+	case "due_date":
+		if task.DueDate == nil {
+			return "infinity", task.ID
+		}
+		return formatCursorTime(*task.DueDate), task.ID
+	case "created_at":
+		return formatCursorTime(task.CreatedAt), task.ID
+	case "updated_at":
+		return formatCursorTime(task.UpdatedAt), task.ID
+	default:
+		return strconv.FormatUint(uint64(task.ID), 10), task.ID
+	}
+}
+
+func whereSQL(whereClauses []string) string {
+	if len(whereClauses) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(whereClauses, " AND ")
+}
@@ -1,30 +1,39 @@
 package infrastructure
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"strings"
+	"time"
 
+	"github.com/lib/pq"
 	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/models"
 	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/usecases"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/database"
 	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
 )
 
+// upsertMembershipQuery добавляет пользователя в команду или меняет его роль в ней
+const upsertMembershipQuery = `
+	INSERT INTO team_memberships (user_id, team_id, role)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (user_id, team_id) DO UPDATE SET role = EXCLUDED.role`
+
 type ProjectRepository struct {
-	db *sql.DB
+	db *database.DB
 }
 
-func NewProjectRepository(database *sql.DB) *ProjectRepository {
-	return &ProjectRepository{db: database}
+func NewProjectRepository(db *sql.DB) *ProjectRepository {
+	return &ProjectRepository{db: database.New(db)}
 }
 
-func (r *ProjectRepository) CreateProject(project *models.Project) (uint32, error) {
+func (r *ProjectRepository) CreateProject(ctx context.Context, project *models.Project) (uint32, error) {
 	query := `INSERT INTO projects (name, description, start_date, planned_end_date, actual_end_date, status, priority, team_id, budget)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id`
 
 	var id uint32
-	err := r.db.QueryRow(query,
+	err := r.db.QueryRowContext(ctx, query,
 		project.Name,
 		project.Description,
 		project.StartDate,
@@ -35,18 +44,20 @@ func (r *ProjectRepository) CreateProject(project *models.Project) (uint32, erro
 		project.Team.ID,
 		project.Budget).Scan(&id)
 	if err != nil {
-		return 0, fmt.Errorf("error inserting project: %v", err)
+		return 0, fmt.Errorf("error inserting project: %w", err)
 	}
 	return id, nil
 }
 
-func (r *ProjectRepository) UpdateProject(project *models.Project) error {
+// UpdateProject обновляет проект и увеличивает его версию.
+// Если project.Version не 0, обновление выполняется только при совпадении версии.
+func (r *ProjectRepository) UpdateProject(ctx context.Context, project *models.Project) error {
 	query := `UPDATE projects
 		SET name = $1, description = $2, start_date = $3, planned_end_date = $4, actual_end_date = $5,
-		    status = $6, priority = $7, team_id = $8, budget = $9
-		WHERE id = $10`
+		    status = $6, priority = $7, team_id = $8, budget = $9, version = version + 1
+		WHERE id = $10 AND deleted_at IS NULL AND ($11 = 0 OR version = $11)`
 
-	_, err := r.db.Exec(query,
+	result, err := r.db.ExecContext(ctx, query,
 		project.Name,
 		project.Description,
 		project.StartDate,
@@ -56,31 +67,70 @@ func (r *ProjectRepository) UpdateProject(project *models.Project) error {
 		project.Priority,
 		project.Team.ID,
 		project.Budget,
-		project.Id)
+		project.Id,
+		project.Version)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return fmt.Errorf("project with id %d not found: %w", project.Id, common.ErrNotFound)
-		}
-		return fmt.Errorf("error updating project: %v", err)
+		return fmt.Errorf("error updating project: %w", err)
 	}
-	return nil
+	return checkVersionedUpdate(result, "project", project.Id, project.Version)
 }
 
-func (r *ProjectRepository) DeleteProject(projectId uint32) error {
-	query := `DELETE FROM projects WHERE id = $1`
+// DeleteProject помечает проект удалённым вместе с его задачами.
+// Задачи получают ту же отметку времени, чтобы восстановить их вместе с проектом.
+func (r *ProjectRepository) DeleteProject(ctx context.Context, projectId uint32) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	query := `UPDATE projects SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL RETURNING deleted_at`
 
-	_, err := r.db.Exec(query, projectId)
+	var deletedAt time.Time
+	err = tx.QueryRowContext(ctx, query, projectId).Scan(&deletedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return fmt.Errorf("project with id %d not found: %w", projectId, common.ErrNotFound)
 		}
-		return fmt.Errorf("error deleting project: %v", err)
+		return fmt.Errorf("error deleting project: %w", err)
+	}
+
+	tasksQuery := `UPDATE tasks SET deleted_at = $1 WHERE project_id = $2 AND deleted_at IS NULL`
+
+	_, err = tx.ExecContext(ctx, tasksQuery, deletedAt, projectId)
+	if err != nil {
+		return fmt.Errorf("error deleting project tasks: %w", err)
 	}
 	return nil
 }
 
-func (r *ProjectRepository) GetAllProjects() ([]*models.Project, error) {
-	query := `
+func (r *ProjectRepository) GetAllProjects(ctx context.Context, filter usecases.ProjectFilter, page common.PageRequest) (*common.Page[*models.Project], error) {
+	whereClauses := []string{"p.deleted_at IS NULL"}
+	var args []interface{}
+
+	if filter.TeamIDs != nil {
+		whereClauses = append(whereClauses, fmt.Sprintf("p.team_id = ANY($%d)", len(args)+1))
+		args = append(args, pq.Array(filter.TeamIDs))
+	}
+
+	pageClauses, pageArgs, orderSQL, err := applyKeyset(page, projectSortColumns, "p.id", whereClauses, args)
+	if err != nil {
+		return nil, err
+	}
+
+	var totalCount int
+	countQuery := `SELECT COUNT(*) FROM projects p ` + whereSQL(whereClauses)
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&totalCount); err != nil {
+		return nil, fmt.Errorf("failed to count projects: %w", err)
+	}
+
+	query := fmt.Sprintf(`
 	SELECT 
     	p.id, 
 		p.name, 
@@ -91,11 +141,20 @@ func (r *ProjectRepository) GetAllProjects() ([]*models.Project, error) {
 		p.status, 
 		p.priority,  
     	p.team_id, 
-		p.budget
+		t.name AS team_name,
+		t.manager_id,
+		p.budget,
+		p.version
 	FROM 
-    	projects p;`
+    	projects p
+	LEFT JOIN
+		teams t
+	ON
+		p.team_id = t.id AND t.deleted_at IS NULL
+	%s
+	%s`, whereSQL(pageClauses), orderSQL)
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query, pageArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query projects: %w", err)
 	}
@@ -104,7 +163,11 @@ func (r *ProjectRepository) GetAllProjects() ([]*models.Project, error) {
 	var projects []*models.Project
 	for rows.Next() {
 		project := &models.Project{}
-		var teamID sql.NullInt64
+		var (
+			teamID    sql.NullInt64
+			teamName  sql.NullString
+			managerID sql.NullInt64
+		)
 
 		err := rows.Scan(
 			&project.Id,
@@ -116,18 +179,21 @@ func (r *ProjectRepository) GetAllProjects() ([]*models.Project, error) {
 			&project.Status,
 			&project.Priority,
 			&teamID,
+			&teamName,
+			&managerID,
 			&project.Budget,
+			&project.Version,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan project row: %w", err)
 		}
 
 		if teamID.Valid {
-			team, err := r.GetTeamById(uint32(teamID.Int64))
-			if err != nil {
-				return nil, fmt.Errorf("failed to get team for project %d: %w", project.Id, err)
+			project.Team = &models.Team{
+				ID:        uint32(teamID.Int64),
+				Name:      teamName.String,
+				ManagerID: uint32(managerID.Int64),
 			}
-			project.Team = team
 		} else {
 			project.Team = nil
 		}
@@ -139,10 +205,10 @@ func (r *ProjectRepository) GetAllProjects() ([]*models.Project, error) {
 		return nil, fmt.Errorf("error occurred during row iteration: %w", err)
 	}
 
-	return projects, nil
+	return newPage(projects, page, totalCount, projectSortValue), nil
 }
 
-func (r *ProjectRepository) GetProjectById(projectId uint32) (*models.Project, error) {
+func (r *ProjectRepository) GetProjectById(ctx context.Context, projectId uint32) (*models.Project, error) {
 	query := `
     SELECT 
         p.id, 
@@ -156,22 +222,23 @@ func (r *ProjectRepository) GetProjectById(projectId uint32) (*models.Project, e
         p.team_id, 
         t.name AS team_name,
         t.manager_id,
-        p.budget
+        p.budget,
+        p.version
     FROM 
         projects p
     LEFT JOIN
         teams t
     ON
-        p.team_id = t.id
+        p.team_id = t.id AND t.deleted_at IS NULL
     WHERE 
-        p.id = $1;`
+        p.id = $1 AND p.deleted_at IS NULL;`
 
 	project := &models.Project{}
 	var teamID sql.NullInt64
 	var teamName sql.NullString
 	var managerID sql.NullInt64
 
-	row := r.db.QueryRow(query, projectId)
+	row := r.db.QueryRowContext(ctx, query, projectId)
 
 	err := row.Scan(
 		&project.Id,
@@ -185,7 +252,8 @@ func (r *ProjectRepository) GetProjectById(projectId uint32) (*models.Project, e
 		&teamID,
 		&teamName,
 		&managerID,
-		&project.Budget)
+		&project.Budget,
+		&project.Version)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -207,8 +275,8 @@ func (r *ProjectRepository) GetProjectById(projectId uint32) (*models.Project, e
 	return project, nil
 }
 
-func (r *ProjectRepository) CreateTeam(team *models.Team) (uint32, error) {
-	tx, err := r.db.Begin()
+func (r *ProjectRepository) CreateTeam(ctx context.Context, team *models.Team) (uint32, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return 0, fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -220,38 +288,35 @@ func (r *ProjectRepository) CreateTeam(team *models.Team) (uint32, error) {
 		}
 	}()
 
-	checkQuery := "SELECT team_id FROM users WHERE id = $1 FOR UPDATE"
-	var existingValue sql.NullString
-	err = tx.QueryRow(checkQuery, team.ManagerID).Scan(&existingValue)
+	checkQuery := "SELECT id FROM users WHERE id = $1 FOR UPDATE"
+	var managerID uint32
+	err = tx.QueryRowContext(ctx, checkQuery, team.ManagerID).Scan(&managerID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return 0, fmt.Errorf("record with id %d not found: %w", team.ManagerID, common.ErrNotFound)
 		}
-		return 0, fmt.Errorf("failed to check column: %w", err)
-	}
-
-	if existingValue.Valid {
-		return 0, fmt.Errorf("team_id is not NULL for id %d", team.ManagerID)
+		return 0, fmt.Errorf("failed to check manager: %w", err)
 	}
 
 	insertTeamQuery := `INSERT INTO teams (name, manager_id) VALUES ($1, $2) RETURNING id`
 	var teamID uint32
-	err = tx.QueryRow(insertTeamQuery, team.Name, team.ManagerID).Scan(&teamID)
+	err = tx.QueryRowContext(ctx, insertTeamQuery, team.Name, team.ManagerID).Scan(&teamID)
 	if err != nil {
 		return 0, fmt.Errorf("error inserting team: %w", err)
 	}
 
-	updateQuery := "UPDATE users SET team_id = $1 WHERE id = $2"
-	_, err = tx.Exec(updateQuery, teamID, team.ManagerID)
-	if err != nil {
-		return 0, fmt.Errorf("failed to set team_id: %w", err)
+	for _, member := range team.Members {
+		_, err = tx.ExecContext(ctx, upsertMembershipQuery, member.ID, teamID, member.Role)
+		if err != nil {
+			return 0, fmt.Errorf("failed to add member (id: %d, role: %s): %w", member.ID, member.Role, err)
+		}
 	}
 
 	return teamID, nil
 }
 
-func (r *ProjectRepository) UpdateTeam(team *models.Team) error {
-	tx, err := r.db.Begin()
+func (r *ProjectRepository) UpdateTeam(ctx context.Context, team *models.Team) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -263,243 +328,231 @@ func (r *ProjectRepository) UpdateTeam(team *models.Team) error {
 		}
 	}()
 
-	checkQuery := "SELECT team_id FROM users WHERE id = $1 FOR UPDATE"
-	var existingTeamID sql.NullInt32
-	err = tx.QueryRow(checkQuery, team.ManagerID).Scan(&existingTeamID)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return fmt.Errorf("record with id %d not found: %w", team.ManagerID, common.ErrNotFound)
-		}
-		return fmt.Errorf("failed to check team_id: %w", err)
-	}
-
-	if !existingTeamID.Valid || uint32(existingTeamID.Int32) != team.ID {
-		return fmt.Errorf("manager with id %d is not associated with team %d", team.ManagerID, team.ID)
-	}
-
 	updateTeamQuery := `
 		UPDATE teams
-		SET name = $1, manager_id = $2
-		WHERE id = $3
+		SET name = $1, manager_id = $2, version = version + 1
+		WHERE id = $3 AND deleted_at IS NULL AND ($4 = 0 OR version = $4)
 	`
-	_, err = tx.Exec(updateTeamQuery, team.Name, team.ManagerID, team.ID)
+	result, err := tx.ExecContext(ctx, updateTeamQuery, team.Name, team.ManagerID, team.ID, team.Version)
 	if err != nil {
 		return fmt.Errorf("failed to update team: %w", err)
 	}
 
-	existingMembersQuery := `
-		SELECT id
-		FROM users
-		WHERE team_id = $1
-	`
-	rows, err := tx.Query(existingMembersQuery, team.ID)
+	err = checkVersionedUpdate(result, "team", team.ID, team.Version)
 	if err != nil {
-		return fmt.Errorf("failed to retrieve existing team members: %w", err)
+		return err
 	}
-	defer rows.Close()
 
-	existingMemberIDs := make(map[uint32]bool)
-	for rows.Next() {
-		var memberID uint32
-		if err := rows.Scan(&memberID); err != nil {
-			return fmt.Errorf("failed to scan existing team member: %w", err)
-		}
-		existingMemberIDs[memberID] = true
+	memberIDs := make([]int64, len(team.Members))
+	for i, member := range team.Members {
+		memberIDs[i] = int64(member.ID)
+	}
+
+	// Членства в других командах не затрагиваются
+	removeQuery := `DELETE FROM team_memberships WHERE team_id = $1 AND NOT (user_id = ANY($2))`
+	_, err = tx.ExecContext(ctx, removeQuery, team.ID, pq.Array(memberIDs))
+	if err != nil {
+		return fmt.Errorf("failed to remove team members: %w", err)
 	}
 
-	updateUserQuery := `
-		UPDATE users
-		SET team_id = $1, role = $2
-		WHERE id = $3
-	`
 	for _, member := range team.Members {
-		_, err = tx.Exec(updateUserQuery, team.ID, member.Role, member.ID)
+		_, err = tx.ExecContext(ctx, upsertMembershipQuery, member.ID, team.ID, member.Role)
 		if err != nil {
 			return fmt.Errorf("failed to update member (id: %d, role: %s): %w", member.ID, member.Role, err)
 		}
-		delete(existingMemberIDs, member.ID)
 	}
 
-	clearUserQuery := `
-		UPDATE users
-		SET team_id = NULL, role = ''
-		WHERE id = $1
-	`
-	for memberID := range existingMemberIDs {
-		_, err = tx.Exec(clearUserQuery, memberID)
-		if err != nil {
-			return fmt.Errorf("failed to clear member (id: %d): %w", memberID, err)
+	// Проверяется после смены состава, чтобы менеджером можно было назначить участника, добавленного этим же запросом
+	checkQuery := "SELECT user_id FROM team_memberships WHERE user_id = $1 AND team_id = $2"
+	var managerID uint32
+	err = tx.QueryRowContext(ctx, checkQuery, team.ManagerID, team.ID).Scan(&managerID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = fmt.Errorf("%w: manager with id %d is not a member of team %d", common.ErrInvalidInput, team.ManagerID, team.ID)
+			return err
 		}
+		return fmt.Errorf("failed to check team membership: %w", err)
 	}
 
 	return nil
 }
 
-func (r *ProjectRepository) DeleteTeam(teamId uint32) error {
-	query := `DELETE FROM teams WHERE id = $1`
+func (r *ProjectRepository) DeleteTeam(ctx context.Context, teamId uint32) error {
+	query := `UPDATE teams SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+
+	return r.softDelete(ctx, query, teamId, "team")
+}
+
+func (r *ProjectRepository) GetAllTeams(ctx context.Context, filter usecases.TeamFilter, page common.PageRequest) (*common.Page[*models.Team], error) {
+	whereClauses := []string{"t.deleted_at IS NULL"}
+	var args []interface{}
+
+	if filter.TeamIDs != nil {
+		whereClauses = append(whereClauses, fmt.Sprintf("t.id = ANY($%d)", len(args)+1))
+		args = append(args, pq.Array(filter.TeamIDs))
+	}
 
-	_, err := r.db.Exec(query, teamId)
+	pageClauses, pageArgs, orderSQL, err := applyKeyset(page, teamSortColumns, "t.id", whereClauses, args)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return fmt.Errorf("team with id %d not found: %w", teamId, common.ErrNotFound)
-		}
-		return fmt.Errorf("error deleting team: %v", err)
+		return nil, err
 	}
-	return nil
-}
 
-func (r *ProjectRepository) GetAllTeams() ([]*models.Team, error) {
-	query := `
+	var totalCount int
+	countQuery := `SELECT COUNT(*) FROM teams t ` + whereSQL(whereClauses)
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&totalCount); err != nil {
+		return nil, fmt.Errorf("failed to count teams: %w", err)
+	}
+
+	query := fmt.Sprintf(`
 	SELECT 
 		t.id, 
 		t.name, 
-		t.manager_id, 
-		u.id AS member_id,
-		u.username, 
-		u.role 
+		t.manager_id,
+		t.version
 	FROM 
 		teams t
-	LEFT JOIN 
-		users u 
-	ON 
-		u.team_id = t.id
-	ORDER BY 
-		t.id, u.id`
+	%s
+	%s`, whereSQL(pageClauses), orderSQL)
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query, pageArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query teams: %w", err)
 	}
 	defer rows.Close()
 
 	var teams []*models.Team
-	var currentTeam *models.Team
-	var lastTeamID uint32
 
 	for rows.Next() {
-		var (
-			teamID     uint32
-			teamName   string
-			managerID  sql.NullInt64
-			memberID   sql.NullInt64
-			memberName string
-			role       sql.NullString
-		)
+		var managerID sql.NullInt64
+		team := &models.Team{Members: []models.Member{}}
 
-		err = rows.Scan(&teamID, &teamName, &managerID, &memberID, &memberName, &role)
-		if err != nil {
+		if err := rows.Scan(&team.ID, &team.Name, &managerID, &team.Version); err != nil {
 			return nil, fmt.Errorf("failed to scan team row: %w", err)
 		}
+		team.ManagerID = uint32(managerID.Int64)
 
-		if currentTeam == nil || teamID != lastTeamID {
-			if currentTeam != nil {
-				teams = append(teams, currentTeam)
-			}
-			currentTeam = &models.Team{
-				ID:      teamID,
-				Name:    teamName,
-				Members: []models.Member{},
-			}
-			if managerID.Valid {
-				currentTeam.ManagerID = uint32(managerID.Int64)
-			}
-			lastTeamID = teamID
-		}
+		teams = append(teams, team)
+	}
 
-		if memberID.Valid {
-			currentTeam.Members = append(currentTeam.Members, models.Member{
-				ID:   uint32(memberID.Int64),
-				Name: memberName,
-				Role: role.String,
-			})
-		}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over team rows: %w", err)
 	}
 
-	if currentTeam != nil {
-		teams = append(teams, currentTeam)
+	result := newPage(teams, page, totalCount, teamSortValue)
+	if len(result.Items) == 0 {
+		return result, nil
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating over team rows: %w", err)
+	if err := r.loadTeamMembers(ctx, result.Items); err != nil {
+		return nil, err
 	}
 
-	return teams, nil
+	return result, nil
 }
 
-func (r *ProjectRepository) GetTeamById(teamId uint32) (*models.Team, error) {
+func (r *ProjectRepository) GetTeamById(ctx context.Context, teamId uint32) (*models.Team, error) {
 	query := `
 	SELECT 
-		id, name, manager_id
+		id, name, manager_id, version
 	FROM 
 		teams
-	WHERE id=$1`
+	WHERE id=$1 AND deleted_at IS NULL`
 
-	team := &models.Team{}
+	team := &models.Team{Members: []models.Member{}}
 
 	var managerID sql.NullInt64
 
-	err := r.db.QueryRow(query, teamId).Scan(&team.ID, &team.Name, &managerID)
-
-	if managerID.Valid {
-		team.ManagerID = uint32(managerID.Int64)
-	} else {
-		team.ManagerID = 0
-	}
-
+	err := r.db.QueryRowContext(ctx, query, teamId).Scan(&team.ID, &team.Name, &managerID, &team.Version)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("team with id %d not found: %w", teamId, common.ErrNotFound)
 		}
 		return nil, err
 	}
+	team.ManagerID = uint32(managerID.Int64)
+
+	if err := r.loadTeamMembers(ctx, []*models.Team{team}); err != nil {
+		return nil, err
+	}
+
 	return team, nil
 }
 
-func (r *ProjectRepository) GetTeamIdByUserID(userID uint32) (uint32, error) {
-	var teamID sql.NullInt32
-	query := "SELECT team_id FROM users WHERE id = $1"
+// loadTeamMembers заполняет Members команд по их членствам
+func (r *ProjectRepository) loadTeamMembers(ctx context.Context, teams []*models.Team) error {
+	teamIDs := make([]int64, len(teams))
+	teamsByID := make(map[uint32]*models.Team, len(teams))
+	for i, team := range teams {
+		teamIDs[i] = int64(team.ID)
+		teamsByID[team.ID] = team
+	}
 
-	err := r.db.QueryRow(query, userID).Scan(&teamID)
+	query := `
+	SELECT 
+		u.id, 
+		u.username, 
+		m.role, 
+		m.team_id 
+	FROM 
+		team_memberships m
+	JOIN 
+		users u ON u.id = m.user_id
+	WHERE 
+		m.team_id = ANY($1)
+	ORDER BY 
+		u.id`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(teamIDs))
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return 0, fmt.Errorf("no user found with id %d: %w", userID, common.ErrNotFound)
+		return fmt.Errorf("failed to query team members: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var member models.Member
+
+		if err := rows.Scan(&member.ID, &member.Name, &member.Role, &member.TeamID); err != nil {
+			return fmt.Errorf("failed to scan team member row: %w", err)
+		}
+
+		if team, ok := teamsByID[member.TeamID]; ok {
+			team.Members = append(team.Members, member)
 		}
-		return 0, err
 	}
 
-	if !teamID.Valid {
-		return 0, fmt.Errorf("team_id is NULL for user id %d: %w", userID, common.ErrNotFound)
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating over team member rows: %w", err)
 	}
 
-	return uint32(teamID.Int32), nil
+	return nil
 }
 
-func (r *ProjectRepository) GetMember(userID uint32) (*models.Member, error) {
+// GetMember получает пользователя с его ролью в команде teamID, в том числе деактивированного.
+// Если пользователь не состоит в команде, Role пуст, а TeamID равен 0.
+func (r *ProjectRepository) GetMember(ctx context.Context, userID uint32, teamID uint32) (*models.Member, error) {
 
 	query := `
 	SELECT 
-		id, 
-		username, 
-		role, 
-		team_id 
+		u.id, 
+		u.username, 
+		COALESCE(m.role, ''), 
+		COALESCE(m.team_id, 0),
+		u.deactivated_at IS NOT NULL
 	FROM 
-		users
+		users u
+	LEFT JOIN 
+		team_memberships m ON m.user_id = u.id AND m.team_id = $2
 	WHERE
-		id = $1`
+		u.id = $1`
 
-	var teamID sql.NullInt32
 	member := &models.Member{}
-	err := r.db.QueryRow(query, userID).Scan(
+	err := r.db.QueryRowContext(ctx, query, userID, teamID).Scan(
 		&member.ID,
 		&member.Name,
 		&member.Role,
-		&teamID,
+		&member.TeamID,
+		&member.Deactivated,
 	)
-	if teamID.Valid {
-		member.TeamID = uint32(teamID.Int32)
-	} else {
-		member.TeamID = 0
-	}
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -511,33 +564,50 @@ func (r *ProjectRepository) GetMember(userID uint32) (*models.Member, error) {
 	return member, nil
 }
 
-func (r *ProjectRepository) GetMembers(filter usecases.MemberFilter) ([]*models.Member, error) {
-	query := `
-	SELECT 
-		id, 
-		username, 
-		role, 
-		team_id 
-	FROM 
-		users`
+// GetMembers получает активных пользователей со списком их команд. С фильтром по команде
+// Role и TeamID относятся к членству в ней, без него Role - глобальная роль пользователя.
+func (r *ProjectRepository) GetMembers(ctx context.Context, filter usecases.MemberFilter, page common.PageRequest) (*common.Page[*models.Member], error) {
+	fromSQL := "FROM users u"
+	roleColumn, teamColumn := "u.role", "0"
 
-	var whereClauses []string
+	whereClauses := []string{"u.deactivated_at IS NULL"}
 	var args []interface{}
 
+	if filter.TeamID != 0 {
+		fromSQL += " JOIN team_memberships m ON m.user_id = u.id"
+		roleColumn, teamColumn = "m.role", "m.team_id"
+
+		whereClauses = append(whereClauses, fmt.Sprintf("m.team_id = $%d", len(args)+1))
+		args = append(args, filter.TeamID)
+	}
 	if filter.Role != "" {
-		whereClauses = append(whereClauses, fmt.Sprintf("role = $%d", len(args)+1))
+		whereClauses = append(whereClauses, fmt.Sprintf("%s = $%d", roleColumn, len(args)+1))
 		args = append(args, filter.Role)
 	}
-	if filter.TeamID != 0 {
-		whereClauses = append(whereClauses, fmt.Sprintf("team_id = $%d", len(args)+1))
-		args = append(args, filter.TeamID)
+
+	pageClauses, pageArgs, orderSQL, err := applyKeyset(page, memberSortColumns, "u.id", whereClauses, args)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(whereClauses) > 0 {
-		query += " WHERE " + strings.Join(whereClauses, " AND ")
+	var totalCount int
+	countQuery := `SELECT COUNT(*) ` + fromSQL + ` ` + whereSQL(whereClauses)
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&totalCount); err != nil {
+		return nil, fmt.Errorf("failed to count members: %w", err)
 	}
 
-	rows, err := r.db.Query(query, args...)
+	query := fmt.Sprintf(`
+	SELECT 
+		u.id, 
+		u.username, 
+		%s, 
+		%s,
+		ARRAY(SELECT tm.team_id FROM team_memberships tm WHERE tm.user_id = u.id ORDER BY tm.team_id)
+	%s
+	%s
+	%s`, roleColumn, teamColumn, fromSQL, whereSQL(pageClauses), orderSQL)
+
+	rows, err := r.db.QueryContext(ctx, query, pageArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query members: %w", err)
 	}
@@ -547,114 +617,107 @@ func (r *ProjectRepository) GetMembers(filter usecases.MemberFilter) ([]*models.
 
 	for rows.Next() {
 		var (
-			memberID uint32
-			name     string
-			role     sql.NullString
-			teamID   sql.NullInt64
+			member  models.Member
+			role    sql.NullString
+			teamIDs []int64
 		)
 
-		err = rows.Scan(&memberID, &name, &role, &teamID)
+		err = rows.Scan(&member.ID, &member.Name, &role, &member.TeamID, pq.Array(&teamIDs))
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan member row: %w", err)
 		}
+		member.Role = role.String
 
-		member := &models.Member{
-			ID:   memberID,
-			Name: name,
-			Role: role.String,
+		member.TeamIDs = make([]uint32, len(teamIDs))
+		for i, teamID := range teamIDs {
+			member.TeamIDs[i] = uint32(teamID)
 		}
 
-		if teamID.Valid {
-			member.TeamID = uint32(teamID.Int64)
-		} else {
-			member.TeamID = 0
-		}
-
-		members = append(members, member)
+		members = append(members, &member)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating over member rows: %w", err)
 	}
 
-	return members, nil
+	return newPage(members, page, totalCount, memberSortValue), nil
 }
 
-func (r *ProjectRepository) CreateTask(task *models.Task) (uint32, error) {
-	query := `INSERT INTO tasks (description, employee_id, project_id, is_completed)
-		VALUES ($1, $2, $3, $4)
+func (r *ProjectRepository) CreateTask(ctx context.Context, task *models.Task) (uint32, error) {
+	query := `INSERT INTO tasks (title, description, status, priority, due_date, estimate, employee_id, project_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id`
 
 	var id uint32
-	err := r.db.QueryRow(query,
+	err := r.db.QueryRowContext(ctx, query,
+		task.Title,
 		task.Description,
+		task.Status,
+		task.Priority,
+		task.DueDate,
+		task.Estimate,
 		task.EmployeeID,
-		task.ProjectID,
-		task.IsCompleted).Scan(&id)
+		task.ProjectID).Scan(&id)
 	if err != nil {
-		return 0, fmt.Errorf("error inserting task: %v", err)
+		return 0, fmt.Errorf("error inserting task: %w", err)
 	}
 	return id, nil
 }
 
-func (r *ProjectRepository) UpdateTask(task *models.Task) error {
+// UpdateTask обновляет задачу и увеличивает её версию.
+// Если task.Version не 0, обновление выполняется только при совпадении версии.
+func (r *ProjectRepository) UpdateTask(ctx context.Context, task *models.Task) error {
 	query := `UPDATE tasks
-		SET description = $1, employee_id = $2, project_id = $3, is_completed = $4
-		WHERE id = $5`
+		SET title = $1, description = $2, status = $3, priority = $4, due_date = $5, estimate = $6,
+		    employee_id = $7, project_id = $8, updated_at = NOW(), version = version + 1
+		WHERE id = $9 AND deleted_at IS NULL AND ($10 = 0 OR version = $10)`
 
-	_, err := r.db.Exec(query,
+	result, err := r.db.ExecContext(ctx, query,
+		task.Title,
 		task.Description,
+		task.Status,
+		task.Priority,
+		task.DueDate,
+		task.Estimate,
 		task.EmployeeID,
 		task.ProjectID,
-		task.IsCompleted,
-		task.ID)
+		task.ID,
+		task.Version)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return fmt.Errorf("task with id %d not found: %w", task.ID, common.ErrNotFound)
-		}
-		return fmt.Errorf("error updating task: %v", err)
+		return fmt.Errorf("error updating task: %w", err)
 	}
-	return nil
+	return checkVersionedUpdate(result, "task", task.ID, task.Version)
 }
 
-func (r *ProjectRepository) DeleteTask(taskID uint32) error {
-	query := `DELETE FROM tasks WHERE id = $1`
+func (r *ProjectRepository) DeleteTask(ctx context.Context, taskID uint32) error {
+	query := `UPDATE tasks SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
 
-	_, err := r.db.Exec(query, taskID)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return fmt.Errorf("task with id %d not found: %w", taskID, common.ErrNotFound)
-		}
-		return fmt.Errorf("error deleting task: %v", err)
-	}
-	return nil
+	return r.softDelete(ctx, query, taskID, "task")
 }
 
-func (r *ProjectRepository) GetTaskById(taskID uint32) (*models.Task, error) {
+func (r *ProjectRepository) GetTaskById(ctx context.Context, taskID uint32) (*models.Task, error) {
 	query := `
 	SELECT 
     	t.id, 
+		t.title,
 		t.description, 
+		t.status,
+		t.priority,
+		t.due_date,
+		t.estimate,
 		t.employee_id, 
 		t.project_id,
-		t.is_completed
+		t.created_at,
+		t.updated_at,
+		t.version
 	FROM 
     	tasks t
 	WHERE 
-    	t.id = $1;`
+    	t.id = $1 AND t.deleted_at IS NULL;`
 
-	task := &models.Task{}
-
-	row := r.db.QueryRow(query, taskID)
-
-	err := row.Scan(
-		&task.ID,
-		&task.Description,
-		&task.EmployeeID,
-		&task.ProjectID,
-		&task.IsCompleted,
-	)
+	row := r.db.QueryRowContext(ctx, query, taskID)
 
+	task, err := scanTask(row)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("task with id %d not found: %w", taskID, common.ErrNotFound)
@@ -665,50 +728,8 @@ func (r *ProjectRepository) GetTaskById(taskID uint32) (*models.Task, error) {
 	return task, nil
 }
 
-func (r *ProjectRepository) GetTasksByEmployeeID(employeeID uint32) ([]*models.Task, error) {
-	query := `
-	SELECT 
-    	t.id, 
-		t.description, 
-		t.employee_id, 
-		t.project_id,
-		t.is_completed
-	FROM 
-    	tasks t
-	WHERE 
-    	t.employee_id = $1;`
-
-	rows, err := r.db.Query(query, employeeID)
-	if err != nil {
-		return nil, fmt.Errorf("error querying tasks for employee_id %d: %v", employeeID, err)
-	}
-	defer rows.Close()
-
-	var tasks []*models.Task
-	for rows.Next() {
-		task := &models.Task{}
-		err := rows.Scan(
-			&task.ID,
-			&task.Description,
-			&task.EmployeeID,
-			&task.ProjectID,
-			&task.IsCompleted,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("error scanning task row: %v", err)
-		}
-		tasks = append(tasks, task)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating over rows: %v", err)
-	}
-
-	return tasks, nil
-}
-
-func (r *ProjectRepository) GetTasks(filter usecases.TaskFilter) ([]*models.Task, error) {
-	var whereClauses []string
+func (r *ProjectRepository) GetTasks(ctx context.Context, filter usecases.TaskFilter, page common.PageRequest) (*common.Page[*models.Task], error) {
+	whereClauses := []string{"deleted_at IS NULL"}
 	var args []interface{}
 
 	if filter.EmployeeID != 0 {
@@ -720,22 +741,52 @@ func (r *ProjectRepository) GetTasks(filter usecases.TaskFilter) ([]*models.Task
 		args = append(args, filter.ProjectID)
 	}
 	if filter.IsCompleted != nil {
-		whereClauses = append(whereClauses, "is_completed = $"+fmt.Sprint(len(args)+1))
-		args = append(args, *filter.IsCompleted)
+		operator := "<>"
+		if *filter.IsCompleted {
+			operator = "="
+		}
+		whereClauses = append(whereClauses, "status "+operator+" $"+fmt.Sprint(len(args)+1))
+		args = append(args, models.TaskStatusDone)
+	}
+	if filter.Status != "" {
+		whereClauses = append(whereClauses, "status = $"+fmt.Sprint(len(args)+1))
+		args = append(args, filter.Status)
+	}
+	if filter.Priority != nil {
+		whereClauses = append(whereClauses, "priority = $"+fmt.Sprint(len(args)+1))
+		args = append(args, *filter.Priority)
+	}
+	if !filter.DueAfter.IsZero() {
+		whereClauses = append(whereClauses, "due_date >= $"+fmt.Sprint(len(args)+1))
+		args = append(args, filter.DueAfter)
+	}
+	if !filter.DueBefore.IsZero() {
+		whereClauses = append(whereClauses, "due_date < $"+fmt.Sprint(len(args)+1))
+		args = append(args, filter.DueBefore)
+	}
+	if filter.TeamIDs != nil {
+		whereClauses = append(whereClauses, "project_id IN (SELECT id FROM projects WHERE deleted_at IS NULL AND team_id = ANY($"+fmt.Sprint(len(args)+1)+"))")
+		args = append(args, pq.Array(filter.TeamIDs))
+	}
+
+	pageClauses, pageArgs, orderSQL, err := applyKeyset(page, taskSortColumns, "id", whereClauses, args)
+	if err != nil {
+		return nil, err
 	}
 
-	whereSQL := ""
-	if len(whereClauses) > 0 {
-		whereSQL = "WHERE " + strings.Join(whereClauses, " AND ")
+	var totalCount int
+	countQuery := `SELECT COUNT(*) FROM tasks ` + whereSQL(whereClauses)
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&totalCount); err != nil {
+		return nil, fmt.Errorf("failed to count tasks: %w", err)
 	}
 
 	query := fmt.Sprintf(`
-		SELECT id, description, employee_id, project_id, is_completed
+		SELECT id, title, description, status, priority, due_date, estimate, employee_id, project_id, created_at, updated_at, version
 		FROM tasks
 		%s
-		ORDER BY id`, whereSQL)
+		%s`, whereSQL(pageClauses), orderSQL)
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, pageArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -743,8 +794,8 @@ func (r *ProjectRepository) GetTasks(filter usecases.TaskFilter) ([]*models.Task
 
 	var tasks []*models.Task
 	for rows.Next() {
-		task := &models.Task{}
-		if err := rows.Scan(&task.ID, &task.Description, &task.EmployeeID, &task.ProjectID, &task.IsCompleted); err != nil {
+		task, err := scanTask(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
 		tasks = append(tasks, task)
@@ -754,5 +805,163 @@ func (r *ProjectRepository) GetTasks(filter usecases.TaskFilter) ([]*models.Task
 		return nil, fmt.Errorf("rows iteration error: %w", err)
 	}
 
-	return tasks, nil
+	return newPage(tasks, page, totalCount, taskSortValue), nil
+}
+
+func (r *ProjectRepository) CreateComment(ctx context.Context, comment *models.Comment) (uint32, error) {
+	query := `INSERT INTO comments (task_id, author_id, body)
+		VALUES ($1, $2, $3)
+		RETURNING id`
+
+	var id uint32
+	err := r.db.QueryRowContext(ctx, query, comment.TaskID, comment.AuthorID, comment.Body).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("error inserting comment: %w", err)
+	}
+	return id, nil
+}
+
+// UpdateComment сохраняет новый текст комментария, а предыдущий - в истории правок
+func (r *ProjectRepository) UpdateComment(ctx context.Context, comment *models.Comment, editorID uint32) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	insertEditQuery := `
+		INSERT INTO comment_edits (comment_id, editor_id, previous_body)
+		SELECT id, $2, body FROM comments WHERE id = $1
+	`
+	result, err := tx.ExecContext(ctx, insertEditQuery, comment.ID, editorID)
+	if err != nil {
+		return fmt.Errorf("failed to save comment edit: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to save comment edit: %w", err)
+	}
+	if rowsAffected == 0 {
+		err = fmt.Errorf("comment with id %d not found: %w", comment.ID, common.ErrNotFound)
+		return err
+	}
+
+	updateQuery := `
+		UPDATE comments
+		SET body = $1, updated_at = NOW(), edit_count = edit_count + 1
+		WHERE id = $2
+	`
+	_, err = tx.ExecContext(ctx, updateQuery, comment.Body, comment.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ProjectRepository) DeleteComment(ctx context.Context, commentID uint32) error {
+	query := `DELETE FROM comments WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, commentID)
+	if err != nil {
+		return fmt.Errorf("error deleting comment: %w", err)
+	}
+	return nil
+}
+
+func (r *ProjectRepository) GetCommentById(ctx context.Context, commentID uint32) (*models.Comment, error) {
+	query := `
+	SELECT 
+		id, task_id, author_id, body, created_at, updated_at, edit_count
+	FROM 
+		comments
+	WHERE 
+		id = $1`
+
+	comment, err := scanComment(r.db.QueryRowContext(ctx, query, commentID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("comment with id %d not found: %w", commentID, common.ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+func (r *ProjectRepository) GetCommentsByTaskID(ctx context.Context, taskID uint32) ([]*models.Comment, error) {
+	query := `
+	SELECT 
+		id, task_id, author_id, body, created_at, updated_at, edit_count
+	FROM 
+		comments
+	WHERE 
+		task_id = $1
+	ORDER BY 
+		created_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comments: %w", err)
+	}
+	defer rows.Close()
+
+	var comments []*models.Comment
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan comment row: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over comment rows: %w", err)
+	}
+
+	return comments, nil
+}
+
+func (r *ProjectRepository) GetCommentEdits(ctx context.Context, commentID uint32) ([]*models.CommentEdit, error) {
+	query := `
+	SELECT 
+		id, comment_id, editor_id, previous_body, edited_at
+	FROM 
+		comment_edits
+	WHERE 
+		comment_id = $1
+	ORDER BY 
+		edited_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comment edits: %w", err)
+	}
+	defer rows.Close()
+
+	var edits []*models.CommentEdit
+	for rows.Next() {
+		edit := &models.CommentEdit{}
+		var editorID sql.NullInt64
+
+		if err := rows.Scan(&edit.ID, &edit.CommentID, &editorID, &edit.PreviousBody, &edit.EditedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan comment edit row: %w", err)
+		}
+		edit.EditorID = uint32(editorID.Int64)
+
+		edits = append(edits, edit)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over comment edit rows: %w", err)
+	}
+
+	return edits, nil
 }
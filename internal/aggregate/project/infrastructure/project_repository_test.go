@@ -1,12 +1,16 @@
 package infrastructure
 
 import (
+	"context"
+	"database/sql"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/models"
 	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/usecases"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -45,7 +49,7 @@ func TestCreateProject(t *testing.T) {
 		).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
-	id, err := repo.CreateProject(project)
+	id, err := repo.CreateProject(context.Background(), project)
 	assert.NoError(t, err)
 	assert.Equal(t, uint32(1), id)
 }
@@ -86,14 +90,41 @@ func TestUpdateProject(t *testing.T) {
 			project.Team.ID,
 			project.Budget,
 			project.Id,
+			project.Version,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	// Выполняем тест
-	err = repo.UpdateProject(project)
+	err = repo.UpdateProject(context.Background(), project)
 	assert.NoError(t, err)
 }
 
+func TestUpdateProjectStaleVersion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewProjectRepository(db)
+
+	project := &models.Project{Id: 1, Name: "Project", Team: &models.Team{ID: 1}, Version: 3}
+
+	// Версия уже изменилась, UPDATE не затрагивает ни одной строки
+	mock.ExpectExec(`UPDATE projects .* version = version \+ 1 WHERE id = \$10 AND deleted_at IS NULL AND \(\$11 = 0 OR version = \$11\)`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.UpdateProject(context.Background(), project)
+	assert.ErrorIs(t, err, common.ErrPreconditionFailed)
+
+	// Без ожидаемой версии отсутствие строки означает, что проект удалён
+	project.Version = 0
+	mock.ExpectExec(`UPDATE projects`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.UpdateProject(context.Background(), project)
+	assert.ErrorIs(t, err, common.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestDeleteProject(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -103,12 +134,106 @@ func TestDeleteProject(t *testing.T) {
 
 	projectID := uint32(1)
 
-	mock.ExpectExec(`DELETE FROM projects WHERE id`).
+	deletedAt := time.Now()
+
+	// Проект помечается удалённым, его задачи получают ту же отметку времени
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE projects SET deleted_at = NOW\(\) WHERE id = \$1 AND deleted_at IS NULL RETURNING deleted_at`).
 		WithArgs(projectID).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+		WillReturnRows(sqlmock.NewRows([]string{"deleted_at"}).AddRow(deletedAt))
+	mock.ExpectExec(`UPDATE tasks SET deleted_at = \$1 WHERE project_id = \$2 AND deleted_at IS NULL`).
+		WithArgs(deletedAt, projectID).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
 
-	err = repo.DeleteProject(projectID)
+	err = repo.DeleteProject(context.Background(), projectID)
 	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRestoreProject(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewProjectRepository(db)
+
+	projectID := uint32(1)
+	deletedAt := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT deleted_at FROM projects WHERE id = \$1 AND deleted_at IS NOT NULL FOR UPDATE`).
+		WithArgs(projectID).
+		WillReturnRows(sqlmock.NewRows([]string{"deleted_at"}).AddRow(deletedAt))
+	mock.ExpectExec(`UPDATE projects SET deleted_at = NULL WHERE id = \$1`).
+		WithArgs(projectID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE tasks SET deleted_at = NULL WHERE project_id = \$1 AND deleted_at = \$2`).
+		WithArgs(projectID, deletedAt).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.RestoreProject(context.Background(), projectID))
+
+	// Проект, который не был удалён, восстановить нельзя
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT deleted_at FROM projects`).
+		WithArgs(uint32(2)).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	assert.ErrorIs(t, repo.RestoreProject(context.Background(), 2), common.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurgeDeleted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewProjectRepository(db)
+
+	before := time.Now()
+
+	expectPurge := func() {
+		mock.ExpectBegin()
+		for _, table := range []string{"tasks", "projects", "teams"} {
+			mock.ExpectExec(`DELETE FROM ` + table + ` WHERE deleted_at < \$1`).
+				WithArgs(before).
+				WillReturnResult(sqlmock.NewResult(0, 2))
+		}
+	}
+
+	expectPurge()
+	mock.ExpectCommit()
+
+	purged, err := repo.PurgeDeleted(context.Background(), before)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), purged)
+
+	// Ошибка фиксации транзакции возвращается вызывающему, а не теряется в defer
+	expectPurge()
+	mock.ExpectCommit().WillReturnError(assert.AnError)
+
+	_, err = repo.PurgeDeleted(context.Background(), before)
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteTaskNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewProjectRepository(db)
+
+	// Повторное удаление не находит задачу
+	mock.ExpectExec(`UPDATE tasks SET deleted_at = NOW\(\) WHERE id = \$1 AND deleted_at IS NULL`).
+		WithArgs(uint32(4)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorIs(t, repo.DeleteTask(context.Background(), 4), common.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestGetAllProjects(t *testing.T) {
@@ -117,43 +242,39 @@ func TestGetAllProjects(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	repo := &ProjectRepository{db: db}
+	repo := NewProjectRepository(db)
 
-	// Данные для проектов
+	// Данные для проектов вместе с командами
 	projectRows := sqlmock.NewRows([]string{
-		"id", "name", "description", "start_date", "planned_end_date", "actual_end_date", "status", "priority", "team_id", "budget",
+		"id", "name", "description", "start_date", "planned_end_date", "actual_end_date", "status", "priority", "team_id", "team_name", "manager_id", "budget", "version",
 	}).
-		AddRow(1, "Project 1", "Description 1", time.Now(), time.Now().AddDate(0, 1, 0), time.Time{}, "Active", 1, 1, 1000.0).
-		AddRow(2, "Project 2", "Description 2", time.Now(), time.Now().AddDate(0, 2, 0), time.Now().AddDate(0, 1, 15), "Completed", 2, 2, 2000.0)
-
-	// Данные для команды с id=1
-	team1Rows := sqlmock.NewRows([]string{"id", "name", "manager_id"}).
-		AddRow(1, "Team 1", 1)
-
-	// Данные для команды с id=2
-	team2Rows := sqlmock.NewRows([]string{"id", "name", "manager_id"}).
-		AddRow(2, "Team 2", 2)
+		AddRow(1, "Project 1", "Description 1", time.Now(), time.Now().AddDate(0, 1, 0), time.Time{}, "Active", 1, 1, "Team 1", 1, 1000.0, 1).
+		AddRow(2, "Project 2", "Description 2", time.Now(), time.Now().AddDate(0, 2, 0), time.Now().AddDate(0, 1, 15), "Completed", 2, 2, "Team 2", 2, 2000.0, 4).
+		AddRow(3, "Project 3", "Description 3", time.Now(), time.Now().AddDate(0, 3, 0), time.Time{}, "Active", 1, nil, nil, nil, 3000.0, 1)
 
 	// Mock запросы
-	mock.ExpectQuery(`SELECT p.id, p.name, p.description, p.start_date, p.planned_end_date, p.actual_end_date, p.status, p.priority, p.team_id, p.budget FROM projects p;`).
-		WillReturnRows(projectRows)
-
-	mock.ExpectQuery(`SELECT id, name, manager_id FROM teams WHERE id=\$1`).
-		WithArgs(1).
-		WillReturnRows(team1Rows)
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM projects p WHERE p.deleted_at IS NULL AND p.team_id = ANY\(\$1\)`).
+		WithArgs(pq.Array([]uint32{1, 2})).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
 
-	mock.ExpectQuery(`SELECT id, name, manager_id FROM teams WHERE id=\$1`).
-		WithArgs(2).
-		WillReturnRows(team2Rows)
+	mock.ExpectQuery(`SELECT .* FROM projects p LEFT JOIN teams t ON p.team_id = t.id AND t.deleted_at IS NULL WHERE p.deleted_at IS NULL AND p.team_id = ANY\(\$1\) ORDER BY p.id ASC, p.id ASC LIMIT 3`).
+		WithArgs(pq.Array([]uint32{1, 2})).
+		WillReturnRows(projectRows)
 
 	// Выполняем тест
-	projects, err := repo.GetAllProjects()
+	page, err := repo.GetAllProjects(context.Background(), usecases.ProjectFilter{TeamIDs: []uint32{1, 2}}, common.PageRequest{Limit: 2, Sort: "id"})
+	assert.NoError(t, err)
+	assert.NotNil(t, page)
+	assert.Equal(t, 3, page.TotalCount)
+	assert.Equal(t, 2, len(page.Items))
+
+	// Лишняя запись отброшена, курсор указывает на последний проект страницы
+	cursor, err := common.DecodeCursor(page.NextCursor)
 	assert.NoError(t, err)
-	assert.NotNil(t, projects)
-	assert.Equal(t, 2, len(projects))
+	assert.Equal(t, common.Cursor{Sort: "id", Value: "2", ID: 2}, *cursor)
 
 	// Проверяем детали проектов
-	project1 := projects[0]
+	project1 := page.Items[0]
 	assert.Equal(t, uint32(1), project1.Id)
 	assert.Equal(t, "Project 1", project1.Name)
 	assert.Equal(t, "Description 1", project1.Description)
@@ -167,13 +288,14 @@ func TestGetAllProjects(t *testing.T) {
 	assert.Equal(t, "Team 1", project1.Team.Name)
 	assert.Equal(t, uint32(1), project1.Team.ManagerID)
 
-	project2 := projects[1]
+	project2 := page.Items[1]
 	assert.Equal(t, uint32(2), project2.Id)
 	assert.Equal(t, "Project 2", project2.Name)
 	assert.Equal(t, "Description 2", project2.Description)
 	assert.Equal(t, "Completed", project2.Status)
 	assert.Equal(t, uint32(2), project2.Priority)
 	assert.Equal(t, float64(2000.0), project2.Budget)
+	assert.Equal(t, uint32(4), project2.Version)
 
 	// Проверка команды проекта 2
 	assert.NotNil(t, project2.Team)
@@ -185,12 +307,43 @@ func TestGetAllProjects(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestGetAllProjectsWithCursor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewProjectRepository(db)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM projects p`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	mock.ExpectQuery(`SELECT .* FROM projects p LEFT JOIN teams t ON p.team_id = t.id AND t.deleted_at IS NULL WHERE p.deleted_at IS NULL AND \(p.name, p.id\) < \(\$1, \$2\) ORDER BY p.name DESC, p.id DESC LIMIT 11`).
+		WithArgs("Project 5", uint32(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	page, err := repo.GetAllProjects(context.Background(), usecases.ProjectFilter{}, common.PageRequest{
+		Limit:  10,
+		Sort:   "name",
+		Desc:   true,
+		Cursor: &common.Cursor{Sort: "-name", Value: "Project 5", ID: 5},
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, page.Items)
+	assert.Empty(t, page.NextCursor)
+
+	// Неизвестное поле сортировки отклоняется до запроса к базе
+	_, err = repo.GetAllProjects(context.Background(), usecases.ProjectFilter{}, common.PageRequest{Sort: "password"})
+	assert.ErrorIs(t, err, common.ErrInvalidInput)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetProjectById(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
-	repo := &ProjectRepository{db: db}
+	repo := NewProjectRepository(db)
 
 	projectID := uint32(1)
 	projectName := "Project 1"
@@ -206,16 +359,16 @@ func TestGetProjectById(t *testing.T) {
 	budget := float64(1000.0)
 
 	projectRow := sqlmock.NewRows([]string{
-		"id", "name", "description", "start_date", "planned_end_date", "actual_end_date", "status", "priority", "team_id", "team_name", "manager_id", "budget",
+		"id", "name", "description", "start_date", "planned_end_date", "actual_end_date", "status", "priority", "team_id", "team_name", "manager_id", "budget", "version",
 	}).AddRow(
-		projectID, projectName, description, startDate, plannedEndDate, actualEndDate, status, priority, teamID, teamName, managerID, budget,
+		projectID, projectName, description, startDate, plannedEndDate, actualEndDate, status, priority, teamID, teamName, managerID, budget, 2,
 	)
 
-	mock.ExpectQuery(`SELECT p.id, p.name, p.description, p.start_date, p.planned_end_date, p.actual_end_date, p.status, p.priority, p.team_id, t.name AS team_name, t.manager_id, p.budget FROM projects p LEFT JOIN teams t ON p.team_id = t.id WHERE p.id = \$1;`).
+	mock.ExpectQuery(`SELECT p.id, p.name, p.description, p.start_date, p.planned_end_date, p.actual_end_date, p.status, p.priority, p.team_id, t.name AS team_name, t.manager_id, p.budget, p.version FROM projects p LEFT JOIN teams t ON p.team_id = t.id AND t.deleted_at IS NULL WHERE p.id = \$1 AND p.deleted_at IS NULL;`).
 		WithArgs(projectID).
 		WillReturnRows(projectRow)
 
-	project, err := repo.GetProjectById(projectID)
+	project, err := repo.GetProjectById(context.Background(), projectID)
 	assert.NoError(t, err)
 	assert.NotNil(t, project)
 
@@ -228,6 +381,7 @@ func TestGetProjectById(t *testing.T) {
 	assert.Equal(t, status, project.Status)
 	assert.Equal(t, priority, project.Priority)
 	assert.Equal(t, budget, project.Budget)
+	assert.Equal(t, uint32(2), project.Version)
 
 	assert.NotNil(t, project.Team)
 	assert.Equal(t, teamID, project.Team.ID)
@@ -238,11 +392,115 @@ func TestGetProjectById(t *testing.T) {
 }
 
 func TestCreateTeam(t *testing.T) {
-	// TODO
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewProjectRepository(db)
+
+	team := &models.Team{
+		Name:      "Team 1",
+		ManagerID: 1,
+		Members:   []models.Member{{ID: 1, Role: common.RoleManager}, {ID: 2, Role: common.RoleMember}},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id FROM users WHERE id = \$1 FOR UPDATE`).
+		WithArgs(team.ManagerID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(`INSERT INTO teams`).
+		WithArgs(team.Name, team.ManagerID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectExec(`INSERT INTO team_memberships .* ON CONFLICT \(user_id, team_id\) DO UPDATE`).
+		WithArgs(uint32(1), uint32(5), common.RoleManager).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO team_memberships`).
+		WithArgs(uint32(2), uint32(5), common.RoleMember).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	id, err := repo.CreateTeam(context.Background(), team)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(5), id)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
 func TestUpdateTeam(t *testing.T) {
-	// TODO
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewProjectRepository(db)
+
+	team := &models.Team{
+		ID:        5,
+		Name:      "Team 1",
+		ManagerID: 1,
+		Members: []models.Member{
+			{ID: 1, Role: common.RoleManager},
+			{ID: 3, Role: common.RoleViewer},
+		},
+		Version: 2,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE teams SET name = \$1, manager_id = \$2, version = version \+ 1`).
+		WithArgs(team.Name, team.ManagerID, team.ID, team.Version).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// Удаляются только членства в этой команде
+	mock.ExpectExec(`DELETE FROM team_memberships WHERE team_id = \$1 AND NOT \(user_id = ANY\(\$2\)\)`).
+		WithArgs(team.ID, pq.Array([]int64{1, 3})).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO team_memberships`).
+		WithArgs(uint32(1), team.ID, common.RoleManager).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO team_memberships`).
+		WithArgs(uint32(3), team.ID, common.RoleViewer).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT user_id FROM team_memberships WHERE user_id = \$1 AND team_id = \$2`).
+		WithArgs(team.ManagerID, team.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	err = repo.UpdateTeam(context.Background(), team)
+	assert.NoError(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateTeamManagerNotMember(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewProjectRepository(db)
+
+	team := &models.Team{ID: 5, Name: "Team 1", ManagerID: 7, Members: []models.Member{{ID: 3, Role: common.RoleMember}}}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE teams`).
+		WithArgs(team.Name, team.ManagerID, team.ID, team.Version).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM team_memberships`).
+		WithArgs(team.ID, pq.Array([]int64{3})).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO team_memberships`).
+		WithArgs(uint32(3), team.ID, common.RoleMember).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT user_id FROM team_memberships`).
+		WithArgs(team.ManagerID, team.ID).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	// Смена состава откатывается, ошибка отдаётся клиенту как неверный ввод
+	err = repo.UpdateTeam(context.Background(), team)
+	assert.ErrorIs(t, err, common.ErrInvalidInput)
+	assert.ErrorContains(t, err, "is not a member of team 5")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
 func TestDeleteTeam(t *testing.T) {
 	// TODO
 }
@@ -254,19 +512,35 @@ func TestGetAllTeams(t *testing.T) {
 
 	repo := NewProjectRepository(db)
 
-	rows := sqlmock.NewRows([]string{"id", "name", "manager_id", "member_id", "username", "role"}).
-		AddRow(1, "Team 1", 1, 1, "User 1", "developer").
-		AddRow(1, "Team 1", 1, 2, "User 2", "designer").
-		AddRow(2, "Team 2", 2, 3, "User 3", "manager")
-
-	mock.ExpectQuery(`SELECT .* FROM teams`).
-		WillReturnRows(rows)
-
-	teams, err := repo.GetAllTeams()
+	teamRows := sqlmock.NewRows([]string{"id", "name", "manager_id", "version"}).
+		AddRow(1, "Team 1", 1, 1).
+		AddRow(2, "Team 2", 2, 1)
+
+	// Пользователь 2 состоит в обеих командах с разными ролями
+	memberRows := sqlmock.NewRows([]string{"id", "username", "role", "team_id"}).
+		AddRow(1, "User 1", "member", 1).
+		AddRow(2, "User 2", "member", 1).
+		AddRow(2, "User 2", "manager", 2)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM teams`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(`SELECT .* FROM teams t WHERE t.deleted_at IS NULL ORDER BY t.id ASC, t.id ASC LIMIT 51`).
+		WillReturnRows(teamRows)
+	mock.ExpectQuery(`SELECT .* FROM team_memberships m JOIN users u ON u.id = m.user_id WHERE m.team_id = ANY\(\$1\)`).
+		WithArgs(pq.Array([]int64{1, 2})).
+		WillReturnRows(memberRows)
+
+	page, err := repo.GetAllTeams(context.Background(), usecases.TeamFilter{}, common.PageRequest{Limit: common.DefaultPageLimit})
 	assert.NoError(t, err)
-	assert.Len(t, teams, 2)
-	assert.Equal(t, uint32(1), teams[0].ID)
-	assert.Equal(t, uint32(2), teams[1].ID)
+	assert.Equal(t, 2, page.TotalCount)
+	assert.Empty(t, page.NextCursor)
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, uint32(1), page.Items[0].ID)
+	assert.Len(t, page.Items[0].Members, 2)
+	assert.Equal(t, uint32(2), page.Items[1].ID)
+	assert.Equal(t, []models.Member{{ID: 2, Name: "User 2", Role: "manager", TeamID: 2}}, page.Items[1].Members)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestGetTeamById(t *testing.T) {
@@ -278,19 +552,25 @@ func TestGetTeamById(t *testing.T) {
 
 	teamID := uint32(1)
 
-	rows := sqlmock.NewRows([]string{"id", "name", "manager_id"}).
-		AddRow(1, "Team 1", 1)
+	rows := sqlmock.NewRows([]string{"id", "name", "manager_id", "version"}).
+		AddRow(1, "Team 1", 1, 3)
 
 	mock.ExpectQuery(`SELECT .* FROM teams`).
 		WithArgs(teamID).
 		WillReturnRows(rows)
+	mock.ExpectQuery(`SELECT .* FROM team_memberships m`).
+		WithArgs(pq.Array([]int64{1})).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "role", "team_id"}).AddRow(1, "User 1", "manager", 1))
 
-	team, err := repo.GetTeamById(teamID)
+	team, err := repo.GetTeamById(context.Background(), teamID)
 	assert.NoError(t, err)
 	assert.Equal(t, uint32(1), team.ID)
+	assert.Len(t, team.Members, 1)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestGetTeamIdByUserID(t *testing.T) {
+func TestGetMember(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
@@ -298,87 +578,141 @@ func TestGetTeamIdByUserID(t *testing.T) {
 	repo := NewProjectRepository(db)
 
 	userID := uint32(1)
+	teamID := uint32(2)
 
-	rows := sqlmock.NewRows([]string{"team_id"}).
-		AddRow(1)
+	rows := sqlmock.NewRows([]string{"id", "username", "role", "team_id", "deactivated"}).
+		AddRow(1, "User 1", "member", 2, true)
 
-	mock.ExpectQuery(`SELECT team_id FROM users`).
-		WithArgs(userID).
+	mock.ExpectQuery(`SELECT .* FROM users u LEFT JOIN team_memberships m ON m.user_id = u.id AND m.team_id = \$2`).
+		WithArgs(userID, teamID).
 		WillReturnRows(rows)
 
-	teamID, err := repo.GetTeamIdByUserID(userID)
+	member, err := repo.GetMember(context.Background(), userID, teamID)
 	assert.NoError(t, err)
-	assert.Equal(t, uint32(1), teamID)
+	assert.Equal(t, uint32(1), member.ID)
+	assert.Equal(t, "member", member.Role)
+	assert.True(t, member.Deactivated)
 }
 
-func TestGetMember(t *testing.T) {
+func TestGetMembers(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
 	repo := NewProjectRepository(db)
 
-	userID := uint32(1)
+	filter := usecases.MemberFilter{
+		Role:   "member",
+		TeamID: 1,
+	}
 
-	rows := sqlmock.NewRows([]string{"id", "username", "role", "team_id"}).
-		AddRow(1, "User 1", "developer", 1)
+	rows := sqlmock.NewRows([]string{"id", "username", "role", "team_id", "team_ids"}).
+		AddRow(1, "User 1", "member", 1, "{1}").
+		AddRow(2, "User 2", "member", 1, "{1,3}")
 
-	mock.ExpectQuery(`SELECT .* FROM users`).
-		WithArgs(userID).
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM users u JOIN team_memberships m ON m.user_id = u.id`).
+		WithArgs(filter.TeamID, filter.Role).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(`SELECT .* FROM users u JOIN team_memberships m ON m.user_id = u.id WHERE u.deactivated_at IS NULL AND m.team_id = \$1 AND m.role = \$2 ORDER BY u.username ASC, u.id ASC`).
+		WithArgs(filter.TeamID, filter.Role).
 		WillReturnRows(rows)
 
-	member, err := repo.GetMember(userID)
+	page, err := repo.GetMembers(context.Background(), filter, common.PageRequest{Sort: "name"})
 	assert.NoError(t, err)
-	assert.Equal(t, uint32(1), member.ID)
+	assert.Equal(t, 2, page.TotalCount)
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, uint32(1), page.Items[0].ID)
+	assert.Equal(t, uint32(2), page.Items[1].ID)
+	assert.Equal(t, []uint32{1, 3}, page.Items[1].TeamIDs)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestGetMembers(t *testing.T) {
+func TestGetMembersWithoutTeam(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
 	repo := NewProjectRepository(db)
 
-	filter := usecases.MemberFilter{
-		Role:   "developer",
-		TeamID: 1,
-	}
-
-	rows := sqlmock.NewRows([]string{"id", "username", "role", "team_id"}).
-		AddRow(1, "User 1", "developer", 1).
-		AddRow(2, "User 2", "developer", 1)
+	rows := sqlmock.NewRows([]string{"id", "username", "role", "team_id", "team_ids"}).
+		AddRow(1, "User 1", "admin", 0, "{}")
 
-	mock.ExpectQuery(`SELECT .* FROM users`).
-		WithArgs(filter.Role, filter.TeamID).
+	// Без фильтра по команде возвращается глобальная роль пользователя
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM users u WHERE u.deactivated_at IS NULL AND u.role = \$1`).
+		WithArgs("admin").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT u.id, u.username, u.role, 0, ARRAY\(.*\) FROM users u WHERE u.deactivated_at IS NULL AND u.role = \$1 ORDER BY u.id ASC, u.id ASC`).
+		WithArgs("admin").
 		WillReturnRows(rows)
 
-	members, err := repo.GetMembers(filter)
+	page, err := repo.GetMembers(context.Background(), usecases.MemberFilter{Role: "admin"}, common.PageRequest{})
 	assert.NoError(t, err)
-	assert.Len(t, members, 2)
-	assert.Equal(t, uint32(1), members[0].ID)
-	assert.Equal(t, uint32(2), members[1].ID)
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, "admin", page.Items[0].Role)
+	assert.Empty(t, page.Items[0].TeamIDs)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestGetTasksByEmployeeID(t *testing.T) {
+func TestUpdateComment(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
 	repo := NewProjectRepository(db)
 
-	employeeID := uint32(1)
+	comment := &models.Comment{ID: 1, Body: "Updated body"}
+	editorID := uint32(2)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO comment_edits`).
+		WithArgs(comment.ID, editorID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`UPDATE comments SET body = \$1, updated_at = NOW\(\), edit_count = edit_count \+ 1`).
+		WithArgs(comment.Body, comment.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.UpdateComment(context.Background(), comment, editorID)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewProjectRepository(db)
 
-	rows := sqlmock.NewRows([]string{"id", "description", "employee_id", "project_id", "is_completed"}).
-		AddRow(1, "Description 1", 1, 1, false).
-		AddRow(2, "Description 2", 2, 2, true)
+	rows := sqlmock.NewRows([]string{"entity_type", "id", "project_id", "task_id", "title", "snippet", "rank"}).
+		AddRow(models.SearchEntityProject, 1, 1, nil, "Billing", markStart+"Billing"+markStop+" service", 0.6).
+		AddRow(models.SearchEntityComment, 7, 1, 3, "Invoices", "<b>fix</b> "+markStart+"billing"+markStop+" rounding", 0.1)
 
-	mock.ExpectQuery(`SELECT .* FROM tasks`).
-		WithArgs(employeeID).
+	mock.ExpectQuery(`websearch_to_tsquery\('simple', \$1\) .* WHERE hits.team_id = ANY\(\$3\) ORDER BY hits.rank DESC, hits.entity_type, hits.id LIMIT 20`).
+		WithArgs("billing", headlineOptions, pq.Array([]uint32{1})).
 		WillReturnRows(rows)
 
-	tasks, err := repo.GetTasksByEmployeeID(employeeID)
+	results, err := repo.Search(context.Background(), usecases.SearchFilter{Query: "billing", TeamIDs: []uint32{1}}, 20)
 	assert.NoError(t, err)
-	assert.Len(t, tasks, 2)
-	assert.Equal(t, uint32(1), tasks[0].ID)
-	assert.Equal(t, uint32(2), tasks[1].ID)
+	assert.Len(t, results, 2)
+	assert.Equal(t, models.SearchEntityProject, results[0].EntityType)
+	assert.Equal(t, uint32(0), results[0].TaskID)
+	assert.Equal(t, uint32(3), results[1].TaskID)
+	assert.Equal(t, "<mark>Billing</mark> service", results[0].Snippet)
+	assert.Equal(t, "&lt;b&gt;fix&lt;/b&gt; <mark>billing</mark> rounding", results[1].Snippet)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	// Текст пользователя экранируется, в том числе поддельные теги <mark>
+	assert.Equal(t,
+		"&lt;mark&gt;&lt;script&gt;x&lt;/script&gt; <mark>a&amp;b</mark>",
+		highlightSnippet("<mark><script>x</script> "+markStart+"a&b"+markStop))
+
+	// Непарные маркеры не ломают разметку
+	assert.Equal(t, "a<mark>b</mark>", highlightSnippet("a"+markStart+"b"))
+	assert.Equal(t, "ab", highlightSnippet("a"+markStop+"b"))
 }
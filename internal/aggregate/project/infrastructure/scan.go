@@ -0,0 +1,73 @@
+package infrastructure
+
+import (
+	"database/sql"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/models"
+)
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTask читает задачу в порядке колонок
+// id, title, description, status, priority, due_date, estimate, employee_id, project_id, created_at, updated_at, version
+func scanTask(row rowScanner) (*models.Task, error) {
+	task := &models.Task{}
+	var (
+		dueDate  sql.NullTime
+		estimate sql.NullInt32
+	)
+
+	err := row.Scan(
+		&task.ID,
+		&task.Title,
+		&task.Description,
+		&task.Status,
+		&task.Priority,
+		&dueDate,
+		&estimate,
+		&task.EmployeeID,
+		&task.ProjectID,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+		&task.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if dueDate.Valid {
+		task.DueDate = &dueDate.Time
+	}
+	if estimate.Valid {
+		value := uint32(estimate.Int32)
+		task.Estimate = &value
+	}
+
+	return task, nil
+}
+
+// scanComment читает комментарий в порядке колонок
+// id, task_id, author_id, body, created_at, updated_at, edit_count
+func scanComment(row rowScanner) (*models.Comment, error) {
+	comment := &models.Comment{}
+	var authorID sql.NullInt64
+
+	err := row.Scan(
+		&comment.ID,
+		&comment.TaskID,
+		&authorID,
+		&comment.Body,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+		&comment.EditCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	comment.AuthorID = uint32(authorID.Int64)
+
+	return comment, nil
+}
@@ -0,0 +1,152 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/models"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/usecases"
+)
+
+// ts_headline не экранирует текст, поэтому совпадения отмечаются символами из области
+// частного использования Unicode, а теги <mark> расставляет highlightSnippet после экранирования
+const (
+	markStart = "\uE000"
+	markStop  = "\uE001"
+
+	headlineOptions = `StartSel="` + markStart + `", StopSel="` + markStop + `", MaxFragments=2, MaxWords=20, MinWords=5`
+)
+
+func (r *ProjectRepository) Search(ctx context.Context, filter usecases.SearchFilter, limit int) ([]*models.SearchResult, error) {
+	args := []interface{}{filter.Query, headlineOptions}
+
+	teamFilter := ""
+	if filter.TeamIDs != nil {
+		teamFilter = fmt.Sprintf("WHERE hits.team_id = ANY($%d)", len(args)+1)
+		args = append(args, pq.Array(filter.TeamIDs))
+	}
+
+	query := fmt.Sprintf(`
+	SELECT 
+		hits.entity_type, 
+		hits.id, 
+		hits.project_id, 
+		hits.task_id, 
+		hits.title, 
+		hits.snippet, 
+		hits.rank
+	FROM (
+		SELECT 
+			'project' AS entity_type, 
+			p.id, 
+			p.id AS project_id, 
+			NULL::INT AS task_id, 
+			p.name AS title,
+			ts_headline('simple', p.name || ' ' || COALESCE(p.description, ''), q, $2) AS snippet,
+			ts_rank(p.search_vector, q) AS rank,
+			p.team_id
+		FROM projects p
+		CROSS JOIN websearch_to_tsquery('simple', $1) q
+		WHERE p.search_vector @@ q AND p.deleted_at IS NULL
+
+		UNION ALL
+
+		SELECT 
+			'task', 
+			t.id, 
+			t.project_id, 
+			t.id, 
+			t.title,
+			ts_headline('simple', t.title || ' ' || t.description, q, $2),
+			ts_rank(t.search_vector, q),
+			p.team_id
+		FROM tasks t
+		JOIN projects p ON t.project_id = p.id
+		CROSS JOIN websearch_to_tsquery('simple', $1) q
+		WHERE t.search_vector @@ q AND t.deleted_at IS NULL AND p.deleted_at IS NULL
+
+		UNION ALL
+
+		SELECT 
+			'comment', 
+			c.id, 
+			t.project_id, 
+			t.id, 
+			t.title,
+			ts_headline('simple', c.body, q, $2),
+			ts_rank(c.search_vector, q),
+			p.team_id
+		FROM comments c
+		JOIN tasks t ON c.task_id = t.id
+		JOIN projects p ON t.project_id = p.id
+		CROSS JOIN websearch_to_tsquery('simple', $1) q
+		WHERE c.search_vector @@ q AND t.deleted_at IS NULL AND p.deleted_at IS NULL
+	) hits
+	%s
+	ORDER BY hits.rank DESC, hits.entity_type, hits.id
+	LIMIT %d`, teamFilter, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute search query: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*models.SearchResult
+	for rows.Next() {
+		var (
+			result models.SearchResult
+			taskID sql.NullInt64
+		)
+
+		err := rows.Scan(
+			&result.EntityType,
+			&result.ID,
+			&result.ProjectID,
+			&taskID,
+			&result.Title,
+			&result.Snippet,
+			&result.Rank,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		result.TaskID = uint32(taskID.Int64)
+		result.Snippet = highlightSnippet(result.Snippet)
+
+		results = append(results, &result)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over search results: %w", err)
+	}
+
+	return results, nil
+}
+
+// highlightSnippet экранирует HTML в тексте фрагмента и заменяет маркеры совпадений тегами <mark>
+func highlightSnippet(snippet string) string {
+	parts := strings.Split(snippet, markStart)
+
+	var b strings.Builder
+	b.WriteString(escapeSnippetText(parts[0]))
+
+	for _, part := range parts[1:] {
+		match, rest, _ := strings.Cut(part, markStop)
+
+		b.WriteString("<mark>")
+		b.WriteString(escapeSnippetText(match))
+		b.WriteString("</mark>")
+		b.WriteString(escapeSnippetText(rest))
+	}
+
+	return b.String()
+}
+
+func escapeSnippetText(text string) string {
+	return html.EscapeString(strings.ReplaceAll(text, markStop, ""))
+}
@@ -0,0 +1,128 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+)
+
+// softDelete выполняет запрос, помечающий запись удалённой, и возвращает ErrNotFound,
+// если запись не найдена или уже удалена
+func (r *ProjectRepository) softDelete(ctx context.Context, query string, id uint32, entity string) error {
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("error deleting %s: %v", entity, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error deleting %s: %v", entity, err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s with id %d not found: %w", entity, id, common.ErrNotFound)
+	}
+	return nil
+}
+
+// RestoreProject восстанавливает проект и задачи, удалённые вместе с ним
+func (r *ProjectRepository) RestoreProject(ctx context.Context, projectID uint32) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	var deletedAt time.Time
+	err = tx.QueryRowContext(ctx, `SELECT deleted_at FROM projects WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE`, projectID).Scan(&deletedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("deleted project with id %d not found: %w", projectID, common.ErrNotFound)
+		}
+		return fmt.Errorf("error restoring project: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE projects SET deleted_at = NULL WHERE id = $1`, projectID)
+	if err != nil {
+		return fmt.Errorf("error restoring project: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE tasks SET deleted_at = NULL WHERE project_id = $1 AND deleted_at = $2`, projectID, deletedAt)
+	if err != nil {
+		return fmt.Errorf("error restoring project tasks: %w", err)
+	}
+	return nil
+}
+
+func (r *ProjectRepository) RestoreTeam(ctx context.Context, teamID uint32) error {
+	query := `UPDATE teams SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	return r.restore(ctx, query, teamID, "team")
+}
+
+// RestoreTask восстанавливает задачу, только если её проект не удалён
+func (r *ProjectRepository) RestoreTask(ctx context.Context, taskID uint32) error {
+	query := `
+	UPDATE tasks t
+	SET deleted_at = NULL
+	FROM projects p
+	WHERE t.id = $1 AND t.deleted_at IS NOT NULL AND p.id = t.project_id AND p.deleted_at IS NULL`
+
+	return r.restore(ctx, query, taskID, "task")
+}
+
+func (r *ProjectRepository) restore(ctx context.Context, query string, id uint32, entity string) error {
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("error restoring %s: %v", entity, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error restoring %s: %v", entity, err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("deleted %s with id %d not found: %w", entity, id, common.ErrNotFound)
+	}
+	return nil
+}
+
+// PurgeDeleted окончательно удаляет задачи, проекты и команды, помеченные удалёнными раньше before
+func (r *ProjectRepository) PurgeDeleted(ctx context.Context, before time.Time) (purged int64, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	for _, table := range []string{"tasks", "projects", "teams"} {
+		var result sql.Result
+		result, err = tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE deleted_at < $1`, table), before)
+		if err != nil {
+			return 0, fmt.Errorf("failed to purge deleted %s: %w", table, err)
+		}
+
+		var rowsAffected int64
+		rowsAffected, err = result.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to purge deleted %s: %w", table, err)
+		}
+		purged += rowsAffected
+	}
+
+	return purged, nil
+}
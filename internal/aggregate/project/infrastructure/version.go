@@ -0,0 +1,25 @@
+package infrastructure
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+)
+
+// checkVersionedUpdate проверяет, что UPDATE с условием на версию затронул запись.
+// Если версия не передавалась, запись была удалена, иначе её успели изменить.
+func checkVersionedUpdate(result sql.Result, entity string, id uint32, version uint32) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error updating %s: %v", entity, err)
+	}
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	if version == 0 {
+		return fmt.Errorf("%s with id %d not found: %w", entity, id, common.ErrNotFound)
+	}
+	return fmt.Errorf("%s with id %d was modified, expected version %d: %w", entity, id, version, common.ErrPreconditionFailed)
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+type Comment struct {
+	ID        uint32
+	TaskID    uint32
+	AuthorID  uint32
+	Body      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	EditCount uint32
+}
+
+// CommentEdit - предыдущая версия текста комментария
+type CommentEdit struct {
+	ID           uint32
+	CommentID    uint32
+	EditorID     uint32
+	PreviousBody string
+	EditedAt     time.Time
+}
@@ -1,8 +1,13 @@
 package models
 
+// Member - пользователь в составе команды. Role и TeamID относятся к членству
+// в команде TeamID; TeamIDs - все команды пользователя, заполняется в GetMembers.
+// Deactivated заполняется в GetMember, GetMembers деактивированных не возвращает.
 type Member struct {
-	ID     uint32
-	Name   string
-	Role   string
-	TeamID uint32
+	ID          uint32
+	Name        string
+	Role        string
+	TeamID      uint32
+	TeamIDs     []uint32
+	Deactivated bool `json:"-"`
 }
@@ -2,6 +2,12 @@ package models
 
 import "time"
 
+// Длины столбцов name и status
+const (
+	MaxProjectNameLength   = 80
+	MaxProjectStatusLength = 15
+)
+
 type Project struct {
 	Id             uint32
 	Name           string
@@ -13,4 +19,5 @@ type Project struct {
 	Priority       uint32
 	Team           *Team
 	Budget         float64
+	Version        uint32
 }
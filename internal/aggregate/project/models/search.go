@@ -0,0 +1,20 @@
+package models
+
+const (
+	SearchEntityProject = "project"
+	SearchEntityTask    = "task"
+	SearchEntityComment = "comment"
+)
+
+// SearchResult - найденный проект, задача или комментарий.
+// Snippet содержит фрагмент текста с экранированным HTML, совпадения выделены тегом <mark>.
+// Title - обычный текст, его нельзя выводить как HTML без экранирования.
+type SearchResult struct {
+	EntityType string
+	ID         uint32
+	ProjectID  uint32
+	TaskID     uint32
+	Title      string
+	Snippet    string
+	Rank       float64
+}
@@ -1,9 +1,26 @@
 package models
 
+import "time"
+
+const MaxTaskPriority uint32 = 3
+
+// MaxTaskTitleLength - длина столбца title
+const MaxTaskTitleLength = 255
+
+// MaxTaskEstimate - верхняя граница оценки, столбец estimate имеет тип SMALLINT
+const MaxTaskEstimate uint32 = 32767
+
 type Task struct {
 	ID          uint32
+	Title       string
 	Description string
+	Status      TaskStatus
+	Priority    uint32
+	DueDate     *time.Time
+	Estimate    *uint32
 	EmployeeID  uint32
 	ProjectID   uint32
-	IsCompleted bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Version     uint32
 }
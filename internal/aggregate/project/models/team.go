@@ -1,8 +1,12 @@
 package models
 
+// MaxTeamNameLength - длина столбца name
+const MaxTeamNameLength = 80
+
 type Team struct {
 	ID        uint32
 	Name      string
 	Members   []Member
 	ManagerID uint32
+	Version   uint32
 }
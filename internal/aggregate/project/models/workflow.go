@@ -0,0 +1,61 @@
+package models
+
+import "slices"
+
+type TaskStatus string
+
+const (
+	TaskStatusTodo       TaskStatus = "todo"
+	TaskStatusInProgress TaskStatus = "in_progress"
+	TaskStatusReview     TaskStatus = "review"
+	TaskStatusDone       TaskStatus = "done"
+)
+
+// Workflow описывает допустимые статусы задачи и переходы между ними
+type Workflow struct {
+	initial     TaskStatus
+	transitions map[TaskStatus][]TaskStatus
+}
+
+func NewWorkflow(initial TaskStatus, transitions map[TaskStatus][]TaskStatus) *Workflow {
+	return &Workflow{
+		initial:     initial,
+		transitions: transitions,
+	}
+}
+
+// DefaultWorkflow: todo → in_progress → review → done с возвратом на предыдущий шаг
+func DefaultWorkflow() *Workflow {
+	return NewWorkflow(TaskStatusTodo, map[TaskStatus][]TaskStatus{
+		TaskStatusTodo:       {TaskStatusInProgress},
+		TaskStatusInProgress: {TaskStatusTodo, TaskStatusReview},
+		TaskStatusReview:     {TaskStatusInProgress, TaskStatusDone},
+		TaskStatusDone:       {TaskStatusInProgress},
+	})
+}
+
+func (w *Workflow) Initial() TaskStatus {
+	return w.initial
+}
+
+func (w *Workflow) IsValid(status TaskStatus) bool {
+	if status == w.initial {
+		return true
+	}
+	if _, ok := w.transitions[status]; ok {
+		return true
+	}
+	for _, targets := range w.transitions {
+		if slices.Contains(targets, status) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Workflow) CanTransition(from, to TaskStatus) bool {
+	if from == to {
+		return true
+	}
+	return slices.Contains(w.transitions[from], to)
+}
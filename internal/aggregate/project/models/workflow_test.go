@@ -0,0 +1,21 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultWorkflow(t *testing.T) {
+	workflow := DefaultWorkflow()
+
+	assert.Equal(t, TaskStatusTodo, workflow.Initial())
+	assert.True(t, workflow.IsValid(TaskStatusReview))
+	assert.False(t, workflow.IsValid("archived"))
+
+	assert.True(t, workflow.CanTransition(TaskStatusTodo, TaskStatusInProgress))
+	assert.True(t, workflow.CanTransition(TaskStatusReview, TaskStatusDone))
+	assert.True(t, workflow.CanTransition(TaskStatusDone, TaskStatusDone))
+	assert.False(t, workflow.CanTransition(TaskStatusTodo, TaskStatusDone))
+	assert.False(t, workflow.CanTransition(TaskStatusInProgress, TaskStatusDone))
+}
@@ -0,0 +1,129 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/transport/dto"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/usecases"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/utils"
+)
+
+func (h *ProjectHandlers) getTaskComments(w http.ResponseWriter, r *http.Request) error {
+	taskID, err := utils.ExtractIDFromPathValue(r, "id")
+	if err != nil {
+		return fmt.Errorf("failed to extract id: %w", err)
+	}
+
+	query := usecases.NewGetTaskCommentsQuery(taskID)
+	comments, err := h.usecases.GetTaskComments(r.Context(), query)
+	if err != nil {
+		return err
+	}
+
+	responseData := make([]dto.CommentResponseDTO, len(comments))
+	for i, v := range comments {
+		responseData[i] = commentModelToDTO(v)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(responseData); err != nil {
+		return fmt.Errorf("failed to encode comments to JSON: %w", err)
+	}
+
+	return nil
+}
+
+func (h *ProjectHandlers) createComment(w http.ResponseWriter, r *http.Request) error {
+	taskID, err := utils.ExtractIDFromPathValue(r, "id")
+	if err != nil {
+		return fmt.Errorf("failed to extract id: %w", err)
+	}
+
+	var requestData dto.CreateCommentRequestDTO
+
+	if err := utils.DecodeJSON(r, &requestData); err != nil {
+		return err
+	}
+
+	cmd := usecases.NewCreateCommentCommand(taskID, requestData.Body)
+
+	id, err := h.usecases.CreateComment(r.Context(), cmd)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	response := map[string]interface{}{"id": id}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		return fmt.Errorf("failed to encode response: %w", err)
+	}
+
+	return nil
+}
+
+func (h *ProjectHandlers) updateComment(w http.ResponseWriter, r *http.Request) error {
+	id, err := utils.ExtractIDFromPathValue(r, "id")
+	if err != nil {
+		return fmt.Errorf("failed to extract id: %w", err)
+	}
+
+	var requestData dto.UpdateCommentRequestDTO
+
+	if err := utils.DecodeJSON(r, &requestData); err != nil {
+		return err
+	}
+
+	cmd := usecases.NewUpdateCommentCommand(id, requestData.Body)
+	if err := h.usecases.UpdateComment(r.Context(), cmd); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (h *ProjectHandlers) deleteComment(w http.ResponseWriter, r *http.Request) error {
+	id, err := utils.ExtractIDFromPathValue(r, "id")
+	if err != nil {
+		return fmt.Errorf("failed to extract id: %w", err)
+	}
+
+	cmd := usecases.NewDeleteCommentCommand(id)
+	if err := h.usecases.DeleteComment(r.Context(), cmd); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (h *ProjectHandlers) getCommentEdits(w http.ResponseWriter, r *http.Request) error {
+	id, err := utils.ExtractIDFromPathValue(r, "id")
+	if err != nil {
+		return fmt.Errorf("failed to extract id: %w", err)
+	}
+
+	query := usecases.NewGetCommentEditsQuery(id)
+	edits, err := h.usecases.GetCommentEdits(r.Context(), query)
+	if err != nil {
+		return err
+	}
+
+	responseData := make([]dto.CommentEditResponseDTO, len(edits))
+	for i, v := range edits {
+		responseData[i] = dto.CommentEditResponseDTO{
+			EditorID:     v.EditorID,
+			PreviousBody: v.PreviousBody,
+			EditedAt:     v.EditedAt,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(responseData); err != nil {
+		return fmt.Errorf("failed to encode comment edits to JSON: %w", err)
+	}
+
+	return nil
+}
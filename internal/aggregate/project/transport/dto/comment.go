@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+type CreateCommentRequestDTO struct {
+	Body string `json:"body" validate:"required"`
+}
+
+type UpdateCommentRequestDTO struct {
+	Body string `json:"body" validate:"required"`
+}
+
+type CommentResponseDTO struct {
+	ID        uint32    `json:"id"`
+	TaskID    uint32    `json:"task_id"`
+	AuthorID  uint32    `json:"author_id"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Edited    bool      `json:"edited"`
+	EditCount uint32    `json:"edit_count"`
+}
+
+type CommentEditResponseDTO struct {
+	EditorID     uint32    `json:"editor_id"`
+	PreviousBody string    `json:"previous_body"`
+	EditedAt     time.Time `json:"edited_at"`
+}
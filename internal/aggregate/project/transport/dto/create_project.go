@@ -3,11 +3,11 @@ package dto
 import "time"
 
 type CreateProjectRequestDTO struct {
-	Name           string    `json:"name"`
+	Name           string    `json:"name" validate:"required,max=80"`
 	Description    string    `json:"description"`
-	PlannedEndDate time.Time `json:"plannedEndDate"`
-	Status         string    `json:"status"`
+	PlannedEndDate time.Time `json:"plannedEndDate" validate:"required"`
+	Status         string    `json:"status" validate:"max=15"`
 	Priority       uint32    `json:"priority"`
 	TeamId         uint32    `json:"teamId"`
-	Budget         float64   `json:"budget"`
+	Budget         float64   `json:"budget" validate:"min=0"`
 }
@@ -1,8 +1,14 @@
 package dto
 
+import "time"
+
 type CreateTaskRequestDTO struct {
-	Description string `json:"description"`
-	EmployeeID  uint32 `json:"employee_id"`
-	ProjectID   uint32 `json:"project_id"`
-	IsCompleted bool   `json:"is_completed"`
+	Title       string     `json:"title" validate:"required,max=255"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	Priority    uint32     `json:"priority" validate:"max=3"`
+	DueDate     *time.Time `json:"due_date"`
+	Estimate    *uint32    `json:"estimate" validate:"max=32767"`
+	EmployeeID  uint32     `json:"employee_id"`
+	ProjectID   uint32     `json:"project_id" validate:"required"`
 }
@@ -1,7 +1,7 @@
 package dto
 
 type CreateTeamRequestDTO struct {
-	Name      string      `json:"name"`
+	Name      string      `json:"name" validate:"required,max=80"`
 	Members   []MemberDTO `json:"members"`
-	ManagerID uint32      `json:"managerId"`
+	ManagerID uint32      `json:"managerId" validate:"required"`
 }
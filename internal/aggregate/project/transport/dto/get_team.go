@@ -5,4 +5,5 @@ type GetTeamResponseDTO struct {
 	Name      string      `json:"name"`
 	Members   []MemberDTO `json:"members"`
 	ManagerID uint32      `json:"managerId"`
+	Version   uint32      `json:"version"`
 }
@@ -1,7 +1,7 @@
 package dto
 
 type MemberDTO struct {
-	ID   uint32 `json:"id"`
+	ID   uint32 `json:"id" validate:"required"`
 	Name string `json:"name"`
 	Role string `json:"role"`
 }
@@ -0,0 +1,7 @@
+package dto
+
+type PageResponseDTO[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	TotalCount int    `json:"total_count"`
+}
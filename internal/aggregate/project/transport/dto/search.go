@@ -0,0 +1,11 @@
+package dto
+
+type SearchResultDTO struct {
+	EntityType string  `json:"entity_type"`
+	ID         uint32  `json:"id"`
+	ProjectID  uint32  `json:"project_id"`
+	TaskID     uint32  `json:"task_id,omitempty"`
+	Title      string  `json:"title"`
+	Snippet    string  `json:"snippet"`
+	Rank       float64 `json:"rank"`
+}
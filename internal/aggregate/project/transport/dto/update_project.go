@@ -3,14 +3,14 @@ package dto
 import "time"
 
 type UpdateProjectRequestDTO struct {
-	ID             uint32    `json:"id"`
-	Name           string    `json:"name"`
+	ID             uint32    `json:"id" validate:"required"`
+	Name           string    `json:"name" validate:"required,max=80"`
 	Description    string    `json:"description"`
-	StartDate      time.Time `json:"startDate"`
-	PlannedEndDate time.Time `json:"plannedEndDate"`
+	StartDate      time.Time `json:"startDate" validate:"required"`
+	PlannedEndDate time.Time `json:"plannedEndDate" validate:"required"`
 	ActualEndDate  time.Time `json:"actualEndDate"`
-	Status         string    `json:"status"`
+	Status         string    `json:"status" validate:"max=15"`
 	Priority       uint32    `json:"priority"`
 	TeamId         uint32    `json:"teamId"`
-	Budget         float64   `json:"budget"`
+	Budget         float64   `json:"budget" validate:"min=0"`
 }
@@ -1,8 +1,8 @@
 package dto
 
 type UpdateTeamRequestDTO struct {
-	ID        uint32      `json:"id"`
-	Name      string      `json:"name"`
+	ID        uint32      `json:"id" validate:"required"`
+	Name      string      `json:"name" validate:"required,max=80"`
 	Members   []MemberDTO `json:"members"`
-	ManagerID uint32      `json:"managerId"`
+	ManagerID uint32      `json:"managerId" validate:"required"`
 }
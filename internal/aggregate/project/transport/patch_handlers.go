@@ -0,0 +1,258 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/models"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/transport/dto"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/usecases"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/utils"
+)
+
+func (h *ProjectHandlers) patchProject(w http.ResponseWriter, r *http.Request) error {
+	id, err := utils.ExtractIDFromPathValue(r, "id")
+	if err != nil {
+		return fmt.Errorf("failed to extract id: %w", err)
+	}
+
+	version, err := parseIfMatch(r)
+	if err != nil {
+		return err
+	}
+
+	defer r.Body.Close()
+	patch, err := decodeProjectPatch(r)
+	if err != nil {
+		return err
+	}
+
+	cmd := usecases.NewPatchProjectCommand(id, patch, version)
+	if err := h.usecases.PatchProject(r.Context(), cmd); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (h *ProjectHandlers) patchTeam(w http.ResponseWriter, r *http.Request) error {
+	id, err := utils.ExtractIDFromPathValue(r, "id")
+	if err != nil {
+		return fmt.Errorf("failed to extract id: %w", err)
+	}
+
+	version, err := parseIfMatch(r)
+	if err != nil {
+		return err
+	}
+
+	defer r.Body.Close()
+	patch, err := decodeTeamPatch(r)
+	if err != nil {
+		return err
+	}
+
+	cmd := usecases.NewPatchTeamCommand(id, patch, version)
+	if err := h.usecases.PatchTeam(r.Context(), cmd); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (h *ProjectHandlers) patchTask(w http.ResponseWriter, r *http.Request) error {
+	id, err := utils.ExtractIDFromPathValue(r, "id")
+	if err != nil {
+		return fmt.Errorf("failed to extract id: %w", err)
+	}
+
+	version, err := parseIfMatch(r)
+	if err != nil {
+		return err
+	}
+
+	defer r.Body.Close()
+	patch, err := decodeTaskPatch(r)
+	if err != nil {
+		return err
+	}
+
+	cmd := usecases.NewPatchTaskCommand(id, patch, version)
+	if err := h.usecases.PatchTask(r.Context(), cmd); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// mergePatch - тело запроса JSON Merge Patch (RFC 7396)
+type mergePatch map[string]json.RawMessage
+
+func decodeMergePatch(r *http.Request, allowed []string) (mergePatch, error) {
+	contentType := r.Header.Get("Content-Type")
+	if contentType != "" && !strings.HasPrefix(contentType, "application/merge-patch+json") && !strings.HasPrefix(contentType, "application/json") {
+		return nil, fmt.Errorf("%w: unsupported content type %q", common.ErrInvalidInput, contentType)
+	}
+
+	var patch mergePatch
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		return nil, fmt.Errorf("%w: merge patch must be a JSON object: %v", common.ErrInvalidInput, err)
+	}
+
+	var unknown []string
+	for key := range patch {
+		if !slices.Contains(allowed, key) {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("%w: unknown fields %s", common.ErrInvalidInput, strings.Join(unknown, ", "))
+	}
+
+	return patch, nil
+}
+
+// patchField читает поле патча. null сбрасывает поле в нулевое значение,
+// если оно nullable, и является ошибкой для обязательных полей.
+func patchField[T any](patch mergePatch, key string, nullable bool) (usecases.PatchField[T], error) {
+	var field usecases.PatchField[T]
+
+	raw, ok := patch[key]
+	if !ok {
+		return field, nil
+	}
+	field.Present = true
+
+	if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		if !nullable {
+			return field, fmt.Errorf("%w: field %s cannot be null", common.ErrInvalidInput, key)
+		}
+		return field, nil
+	}
+
+	if err := json.Unmarshal(raw, &field.Value); err != nil {
+		return field, fmt.Errorf("%w: invalid %s: %v", common.ErrInvalidInput, key, err)
+	}
+
+	return field, nil
+}
+
+func decodeProjectPatch(r *http.Request) (usecases.ProjectPatch, error) {
+	var patch usecases.ProjectPatch
+
+	body, err := decodeMergePatch(r, []string{
+		"name", "description", "startDate", "plannedEndDate", "actualEndDate", "status", "priority", "teamId", "budget",
+	})
+	if err != nil {
+		return patch, err
+	}
+
+	if patch.Name, err = patchField[string](body, "name", false); err != nil {
+		return patch, err
+	}
+	if patch.Description, err = patchField[string](body, "description", true); err != nil {
+		return patch, err
+	}
+	if patch.StartDate, err = patchField[time.Time](body, "startDate", false); err != nil {
+		return patch, err
+	}
+	if patch.PlannedEndDate, err = patchField[time.Time](body, "plannedEndDate", false); err != nil {
+		return patch, err
+	}
+	if patch.ActualEndDate, err = patchField[time.Time](body, "actualEndDate", true); err != nil {
+		return patch, err
+	}
+	if patch.Status, err = patchField[string](body, "status", false); err != nil {
+		return patch, err
+	}
+	if patch.Priority, err = patchField[uint32](body, "priority", false); err != nil {
+		return patch, err
+	}
+	if patch.TeamID, err = patchField[uint32](body, "teamId", true); err != nil {
+		return patch, err
+	}
+	if patch.Budget, err = patchField[float64](body, "budget", false); err != nil {
+		return patch, err
+	}
+
+	return patch, nil
+}
+
+func decodeTeamPatch(r *http.Request) (usecases.TeamPatch, error) {
+	var patch usecases.TeamPatch
+
+	body, err := decodeMergePatch(r, []string{"name", "members", "managerId"})
+	if err != nil {
+		return patch, err
+	}
+
+	if patch.Name, err = patchField[string](body, "name", false); err != nil {
+		return patch, err
+	}
+	if patch.ManagerID, err = patchField[uint32](body, "managerId", false); err != nil {
+		return patch, err
+	}
+
+	// Массив в Merge Patch заменяет состав команды целиком
+	members, err := patchField[[]dto.MemberDTO](body, "members", true)
+	if err != nil {
+		return patch, err
+	}
+	if members.Present {
+		patch.Members = usecases.SetField(make([]usecases.Member, len(members.Value)))
+		for i, v := range members.Value {
+			patch.Members.Value[i] = *usecases.NewMember(v.ID, v.Name, v.Role)
+		}
+	}
+
+	return patch, nil
+}
+
+func decodeTaskPatch(r *http.Request) (usecases.TaskPatch, error) {
+	var patch usecases.TaskPatch
+
+	body, err := decodeMergePatch(r, []string{
+		"title", "description", "status", "priority", "due_date", "estimate", "employee_id", "project_id",
+	})
+	if err != nil {
+		return patch, err
+	}
+
+	if patch.Title, err = patchField[string](body, "title", false); err != nil {
+		return patch, err
+	}
+	if patch.Description, err = patchField[string](body, "description", true); err != nil {
+		return patch, err
+	}
+	if patch.Status, err = patchField[models.TaskStatus](body, "status", false); err != nil {
+		return patch, err
+	}
+	if patch.Priority, err = patchField[uint32](body, "priority", false); err != nil {
+		return patch, err
+	}
+	if patch.DueDate, err = patchField[*time.Time](body, "due_date", true); err != nil {
+		return patch, err
+	}
+	if patch.Estimate, err = patchField[*uint32](body, "estimate", true); err != nil {
+		return patch, err
+	}
+	if patch.EmployeeID, err = patchField[uint32](body, "employee_id", false); err != nil {
+		return patch, err
+	}
+	if patch.ProjectID, err = patchField[uint32](body, "project_id", false); err != nil {
+		return patch, err
+	}
+
+	return patch, nil
+}
@@ -0,0 +1,196 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/models"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/usecases"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepository хранит проекты и задачи в памяти, остальные методы не используются
+type fakeRepository struct {
+	usecases.ProjectRepository
+
+	projects map[uint32]*models.Project
+	tasks    map[uint32]*models.Task
+}
+
+func (r *fakeRepository) GetProjectById(_ context.Context, id uint32) (*models.Project, error) {
+	project, ok := r.projects[id]
+	if !ok {
+		return nil, common.ErrNotFound
+	}
+	copied := *project
+	return &copied, nil
+}
+
+func (r *fakeRepository) UpdateProject(_ context.Context, project *models.Project) error {
+	stored := *project
+	stored.Version = r.projects[project.Id].Version + 1
+	r.projects[project.Id] = &stored
+	return nil
+}
+
+func (r *fakeRepository) GetTaskById(_ context.Context, id uint32) (*models.Task, error) {
+	task, ok := r.tasks[id]
+	if !ok {
+		return nil, common.ErrNotFound
+	}
+	copied := *task
+	return &copied, nil
+}
+
+func (r *fakeRepository) UpdateTask(_ context.Context, task *models.Task) error {
+	stored := *task
+	stored.Version = r.tasks[task.ID].Version + 1
+	r.tasks[task.ID] = &stored
+	return nil
+}
+
+type allowAllPolicy struct{}
+
+func (allowAllPolicy) Can(context.Context, common.Permission) (bool, error) { return true, nil }
+func (allowAllPolicy) Authorize(context.Context, common.Permission) error   { return nil }
+func (allowAllPolicy) AuthorizeTeam(context.Context, common.Permission, uint32) error {
+	return nil
+}
+func (allowAllPolicy) VisibleTeams(context.Context, common.Permission) ([]uint32, bool, error) {
+	return nil, true, nil
+}
+
+type noTx struct{}
+
+func (noTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error { return fn(ctx) }
+
+type noAudit struct{}
+
+func (noAudit) Record(context.Context, string, string, uint32, any, any) error { return nil }
+
+type noMetrics struct{}
+
+func (noMetrics) ProjectCreated() {}
+func (noMetrics) TaskCreated()    {}
+func (noMetrics) TaskCompleted()  {}
+
+func newPatchTestHandlers() (*ProjectHandlers, *fakeRepository) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	estimate := uint32(8)
+	repo := &fakeRepository{
+		projects: map[uint32]*models.Project{1: {
+			Id:             1,
+			Name:           "Billing",
+			Description:    "Invoices",
+			StartDate:      start,
+			PlannedEndDate: start.AddDate(0, 3, 0),
+			Status:         "active",
+			Team:           &models.Team{ID: 10},
+			Version:        3,
+		}},
+		tasks: map[uint32]*models.Task{7: {
+			ID:         7,
+			Title:      "Send invoices",
+			Status:     models.TaskStatusTodo,
+			Estimate:   &estimate,
+			EmployeeID: 2,
+			ProjectID:  1,
+			Version:    1,
+		}},
+	}
+
+	uc := usecases.NewProjectUseCases(repo, noTx{}, allowAllPolicy{}, models.DefaultWorkflow(), noAudit{}, noMetrics{})
+	return NewProjectHandlers(uc), repo
+}
+
+func newPatchRequest(id, ifMatch, body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(body))
+	r.SetPathValue("id", id)
+	r.Header.Set("Content-Type", "application/merge-patch+json")
+	if ifMatch != "" {
+		r.Header.Set("If-Match", ifMatch)
+	}
+	claims := &common.Claims{UserID: 2, Role: common.RoleMember}
+	return r.WithContext(context.WithValue(r.Context(), common.ContextKeyClaims, claims))
+}
+
+func TestPatchProjectHandler(t *testing.T) {
+	h, repo := newPatchTestHandlers()
+
+	w := httptest.NewRecorder()
+	err := h.patchProject(w, newPatchRequest("1", `"3"`, `{"name": "Billing v2", "description": null, "teamId": null}`))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	stored := repo.projects[1]
+	assert.Equal(t, "Billing v2", stored.Name)
+	assert.Empty(t, stored.Description)
+	assert.Zero(t, stored.Team.ID)
+	assert.Equal(t, "active", stored.Status)
+}
+
+func TestPatchProjectHandlerStaleVersion(t *testing.T) {
+	h, repo := newPatchTestHandlers()
+
+	err := h.patchProject(httptest.NewRecorder(), newPatchRequest("1", `"2"`, `{"name": "Billing v2"}`))
+	assert.ErrorIs(t, err, common.ErrPreconditionFailed)
+
+	err = h.patchProject(httptest.NewRecorder(), newPatchRequest("1", `"abc"`, `{"name": "Billing v2"}`))
+	assert.ErrorIs(t, err, common.ErrPreconditionFailed)
+
+	assert.Equal(t, "Billing", repo.projects[1].Name)
+}
+
+func TestPatchProjectHandlerRejectsInvalidPatch(t *testing.T) {
+	h, repo := newPatchTestHandlers()
+
+	// Обязательное поле нельзя сбросить null
+	err := h.patchProject(httptest.NewRecorder(), newPatchRequest("1", "", `{"name": null}`))
+	assert.ErrorIs(t, err, common.ErrInvalidInput)
+
+	err = h.patchProject(httptest.NewRecorder(), newPatchRequest("1", "", `{"owner": "ann"}`))
+	assert.ErrorIs(t, err, common.ErrInvalidInput)
+
+	err = h.patchProject(httptest.NewRecorder(), newPatchRequest("1", "", `[]`))
+	assert.ErrorIs(t, err, common.ErrInvalidInput)
+
+	// Объединённый результат проверяется теми же правилами, что и PUT
+	err = h.patchProject(httptest.NewRecorder(), newPatchRequest("1", "", `{"name": "`+strings.Repeat("a", 81)+`"}`))
+	assert.ErrorIs(t, err, common.ErrValidation)
+
+	assert.Equal(t, "Billing", repo.projects[1].Name)
+}
+
+func TestPatchTeamHandlerRejectsNullManager(t *testing.T) {
+	h, _ := newPatchTestHandlers()
+
+	// Команда не может остаться без менеджера
+	err := h.patchTeam(httptest.NewRecorder(), newPatchRequest("10", "", `{"managerId": null}`))
+	assert.ErrorIs(t, err, common.ErrInvalidInput)
+}
+
+func TestPatchTaskHandler(t *testing.T) {
+	h, repo := newPatchTestHandlers()
+
+	w := httptest.NewRecorder()
+	err := h.patchTask(w, newPatchRequest("7", `W/"1"`, `{"status": "in_progress", "estimate": null}`))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	stored := repo.tasks[7]
+	assert.Equal(t, models.TaskStatusInProgress, stored.Status)
+	assert.Nil(t, stored.Estimate)
+	assert.Equal(t, "Send invoices", stored.Title)
+
+	err = h.patchTask(httptest.NewRecorder(), newPatchRequest("7", `"1"`, `{"title": "Renamed"}`))
+	assert.ErrorIs(t, err, common.ErrPreconditionFailed)
+
+	err = h.patchTask(httptest.NewRecorder(), newPatchRequest("7", "", `{"title": ""}`))
+	assert.ErrorIs(t, err, common.ErrValidation)
+}
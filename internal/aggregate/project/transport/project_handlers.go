@@ -3,7 +3,6 @@ package transport
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
 	"strconv"
 	"strings"
@@ -11,6 +10,7 @@ import (
 	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/models"
 	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/transport/dto"
 	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/usecases"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
 	"github.com/lunarKettle/task-management-platform-monolith/pkg/utils"
 )
 
@@ -31,13 +31,17 @@ func (h *ProjectHandlers) RegisterRoutes(mux *http.ServeMux, errorHandler func(h
 	mux.Handle("GET /projects/{id}", errorHandler(h.getProject))
 	mux.Handle("POST /projects", errorHandler(h.createProject))
 	mux.Handle("PUT /projects", errorHandler(h.updateProject))
+	mux.Handle("PATCH /projects/{id}", errorHandler(h.patchProject))
 	mux.Handle("DELETE /projects/{id}", errorHandler(h.deleteProject))
+	mux.Handle("POST /projects/{id}/restore", errorHandler(h.restoreProject))
 
 	mux.Handle("GET /teams", errorHandler(h.getAllTeams))
 	mux.Handle("GET /teams/{id}", errorHandler(h.getTeam))
 	mux.Handle("POST /teams", errorHandler(h.createTeam))
 	mux.Handle("PUT /teams", errorHandler(h.updateTeam))
+	mux.Handle("PATCH /teams/{id}", errorHandler(h.patchTeam))
 	mux.Handle("DELETE /teams/{id}", errorHandler(h.deleteTeam))
+	mux.Handle("POST /teams/{id}/restore", errorHandler(h.restoreTeam))
 
 	mux.Handle("GET /members", errorHandler(h.getMembers))
 
@@ -45,22 +49,33 @@ func (h *ProjectHandlers) RegisterRoutes(mux *http.ServeMux, errorHandler func(h
 	mux.Handle("GET /tasks", errorHandler(h.getTasks))
 	mux.Handle("POST /tasks", errorHandler(h.createTask))
 	mux.Handle("PUT /tasks", errorHandler(h.updateTask))
+	mux.Handle("PATCH /tasks/{id}", errorHandler(h.patchTask))
 	mux.Handle("DELETE /tasks/{id}", errorHandler(h.deleteTask))
+	mux.Handle("POST /tasks/{id}/restore", errorHandler(h.restoreTask))
+
+	mux.Handle("GET /tasks/{id}/comments", errorHandler(h.getTaskComments))
+	mux.Handle("POST /tasks/{id}/comments", errorHandler(h.createComment))
+	mux.Handle("PUT /comments/{id}", errorHandler(h.updateComment))
+	mux.Handle("DELETE /comments/{id}", errorHandler(h.deleteComment))
+	mux.Handle("GET /comments/{id}/edits", errorHandler(h.getCommentEdits))
+
+	mux.Handle("GET /search", errorHandler(h.search))
 }
 
 func (h *ProjectHandlers) getAllProjects(w http.ResponseWriter, r *http.Request) error {
-	projects, err := h.usecases.GetAllProjects(r.Context())
-
+	page, err := common.ParsePageRequest(r.URL.Query(), "id", usecases.ProjectSortFields)
 	if err != nil {
 		return err
 	}
 
-	if projects == nil {
-		projects = []*models.Project{}
+	projects, err := h.usecases.GetAllProjects(r.Context(), page)
+
+	if err != nil {
+		return err
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(projects); err != nil {
+	if err := json.NewEncoder(w).Encode(pageToDTO(projects)); err != nil {
 		err = fmt.Errorf("failed to encode project to JSON: %w", err)
 		return err
 	}
@@ -82,6 +97,7 @@ func (h *ProjectHandlers) getProject(w http.ResponseWriter, r *http.Request) err
 		return err
 	}
 
+	setETag(w, project.Version)
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(project); err != nil {
 		err = fmt.Errorf("failed to encode project to JSON: %w", err)
@@ -93,12 +109,10 @@ func (h *ProjectHandlers) getProject(w http.ResponseWriter, r *http.Request) err
 func (h *ProjectHandlers) createProject(w http.ResponseWriter, r *http.Request) error {
 	var requestData dto.CreateProjectRequestDTO
 
-	err := json.NewDecoder(r.Body).Decode(&requestData)
+	err := utils.DecodeJSON(r, &requestData)
 	if err != nil {
-		err = fmt.Errorf("error while decoding request body: %w", err)
 		return err
 	}
-	defer r.Body.Close()
 
 	cmd := usecases.NewCreateProjectCommand(
 		requestData.Name,
@@ -120,7 +134,6 @@ func (h *ProjectHandlers) createProject(w http.ResponseWriter, r *http.Request)
 	response := map[string]interface{}{"id": id}
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		err = fmt.Errorf("failed to encode project to JSON: %w", err)
-		log.Print(err)
 		return err
 	}
 	return err
@@ -131,14 +144,17 @@ func (h *ProjectHandlers) updateProject(w http.ResponseWriter, r *http.Request)
 		return fmt.Errorf("invalid request method: %s", r.Method)
 	}
 
+	version, err := parseIfMatch(r)
+	if err != nil {
+		return err
+	}
+
 	var requestData dto.UpdateProjectRequestDTO
 
-	err := json.NewDecoder(r.Body).Decode(&requestData)
+	err = utils.DecodeJSON(r, &requestData)
 	if err != nil {
-		err = fmt.Errorf("error while decoding request body: %w", err)
 		return err
 	}
-	defer r.Body.Close()
 
 	cmd := usecases.NewUpdateProjectCommand(
 		requestData.ID,
@@ -151,6 +167,7 @@ func (h *ProjectHandlers) updateProject(w http.ResponseWriter, r *http.Request)
 		requestData.Priority,
 		requestData.TeamId,
 		requestData.Budget,
+		version,
 	)
 
 	err = h.usecases.UpdateProject(r.Context(), cmd)
@@ -187,18 +204,19 @@ func (h *ProjectHandlers) deleteProject(w http.ResponseWriter, r *http.Request)
 }
 
 func (h *ProjectHandlers) getAllTeams(w http.ResponseWriter, r *http.Request) error {
-	teams, err := h.usecases.GetAllTeams(r.Context())
-
+	page, err := common.ParsePageRequest(r.URL.Query(), "id", usecases.TeamSortFields)
 	if err != nil {
 		return err
 	}
 
-	if teams == nil {
-		teams = []*models.Team{}
+	teams, err := h.usecases.GetAllTeams(r.Context(), page)
+
+	if err != nil {
+		return err
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(teams); err != nil {
+	if err := json.NewEncoder(w).Encode(pageToDTO(teams)); err != nil {
 		err = fmt.Errorf("failed to encode team to JSON: %w", err)
 		return err
 	}
@@ -231,8 +249,10 @@ func (h *ProjectHandlers) getTeam(w http.ResponseWriter, r *http.Request) error
 		Name:      team.Name,
 		Members:   membersDTO,
 		ManagerID: team.ManagerID,
+		Version:   team.Version,
 	}
 
+	setETag(w, team.Version)
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(responseData); err != nil {
 		err = fmt.Errorf("failed to encode team to JSON: %w", err)
@@ -244,12 +264,10 @@ func (h *ProjectHandlers) getTeam(w http.ResponseWriter, r *http.Request) error
 func (h *ProjectHandlers) createTeam(w http.ResponseWriter, r *http.Request) error {
 	var requestData dto.CreateTeamRequestDTO
 
-	err := json.NewDecoder(r.Body).Decode(&requestData)
+	err := utils.DecodeJSON(r, &requestData)
 	if err != nil {
-		err = fmt.Errorf("error while decoding request body: %w", err)
 		return err
 	}
-	defer r.Body.Close()
 
 	members := make([]usecases.Member, len(requestData.Members))
 	for i, v := range requestData.Members {
@@ -272,7 +290,6 @@ func (h *ProjectHandlers) createTeam(w http.ResponseWriter, r *http.Request) err
 	response := map[string]interface{}{"id": id}
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		err = fmt.Errorf("failed to encode team to JSON: %w", err)
-		log.Print(err)
 		return err
 	}
 	return err
@@ -283,14 +300,17 @@ func (h *ProjectHandlers) updateTeam(w http.ResponseWriter, r *http.Request) err
 		return fmt.Errorf("invalid request method: %s", r.Method)
 	}
 
+	version, err := parseIfMatch(r)
+	if err != nil {
+		return err
+	}
+
 	var requestData dto.UpdateTeamRequestDTO
 
-	err := json.NewDecoder(r.Body).Decode(&requestData)
+	err = utils.DecodeJSON(r, &requestData)
 	if err != nil {
-		err = fmt.Errorf("error while decoding request body: %w", err)
 		return err
 	}
-	defer r.Body.Close()
 
 	members := make([]usecases.Member, len(requestData.Members))
 	for i, v := range requestData.Members {
@@ -302,6 +322,7 @@ func (h *ProjectHandlers) updateTeam(w http.ResponseWriter, r *http.Request) err
 		requestData.Name,
 		members,
 		requestData.ManagerID,
+		version,
 	)
 
 	err = h.usecases.UpdateTeam(r.Context(), cmd)
@@ -348,18 +369,19 @@ func (h *ProjectHandlers) getMembers(w http.ResponseWriter, r *http.Request) err
 		TeamID: uint32(teamID),
 	}
 
-	members, err := h.usecases.GetMembers(r.Context(), filter)
-
+	page, err := common.ParsePageRequest(query, "id", usecases.MemberSortFields)
 	if err != nil {
 		return err
 	}
 
-	if members == nil {
-		members = []*models.Member{}
+	members, err := h.usecases.GetMembers(r.Context(), filter, page)
+
+	if err != nil {
+		return err
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(members); err != nil {
+	if err := json.NewEncoder(w).Encode(pageToDTO(members)); err != nil {
 		err = fmt.Errorf("failed to encode team to JSON: %w", err)
 		return err
 	}
@@ -378,6 +400,7 @@ func (h *ProjectHandlers) getTask(w http.ResponseWriter, r *http.Request) error
 		return err
 	}
 
+	setETag(w, task.Version)
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(task); err != nil {
 		return fmt.Errorf("failed to encode task to JSON: %w", err)
@@ -393,23 +416,43 @@ func (h *ProjectHandlers) getTasks(w http.ResponseWriter, r *http.Request) error
 	projectID, _ := strconv.Atoi(query.Get("project_id"))
 	isCompleted := query.Get("is_completed")
 
+	priority, err := parseUint32(query.Get("priority"))
+	if err != nil {
+		return fmt.Errorf("%w: invalid priority: %v", common.ErrInvalidInput, err)
+	}
+
+	dueAfter, err := parseTime(query.Get("due_after"))
+	if err != nil {
+		return fmt.Errorf("%w: invalid due_after: %v", common.ErrInvalidInput, err)
+	}
+
+	dueBefore, err := parseTime(query.Get("due_before"))
+	if err != nil {
+		return fmt.Errorf("%w: invalid due_before: %v", common.ErrInvalidInput, err)
+	}
+
 	filter := usecases.TaskFilter{
 		EmployeeID:  uint32(employeeID),
 		ProjectID:   uint32(projectID),
 		IsCompleted: parseBool(isCompleted),
+		Status:      models.TaskStatus(query.Get("status")),
+		Priority:    priority,
+		DueAfter:    dueAfter,
+		DueBefore:   dueBefore,
 	}
 
-	tasks, err := h.usecases.GetTasks(r.Context(), filter)
+	page, err := common.ParsePageRequest(query, "id", usecases.TaskSortFields)
 	if err != nil {
 		return err
 	}
 
-	if tasks == nil {
-		tasks = []*models.Task{}
+	tasks, err := h.usecases.GetTasks(r.Context(), filter, page)
+	if err != nil {
+		return err
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(tasks); err != nil {
+	if err := json.NewEncoder(w).Encode(pageToDTO(tasks)); err != nil {
 		return fmt.Errorf("failed to encode tasks to JSON: %w", err)
 	}
 
@@ -419,16 +462,19 @@ func (h *ProjectHandlers) getTasks(w http.ResponseWriter, r *http.Request) error
 func (h *ProjectHandlers) createTask(w http.ResponseWriter, r *http.Request) error {
 	var requestData dto.CreateTaskRequestDTO
 
-	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
-		return fmt.Errorf("error decoding request body: %w", err)
+	if err := utils.DecodeJSON(r, &requestData); err != nil {
+		return err
 	}
-	defer r.Body.Close()
 
 	cmd := usecases.NewCreateTaskCommand(
+		requestData.Title,
 		requestData.Description,
+		models.TaskStatus(requestData.Status),
+		requestData.Priority,
+		requestData.DueDate,
+		requestData.Estimate,
 		requestData.EmployeeID,
 		requestData.ProjectID,
-		requestData.IsCompleted,
 	)
 
 	id, err := h.usecases.CreateTask(r.Context(), cmd)
@@ -446,19 +492,28 @@ func (h *ProjectHandlers) createTask(w http.ResponseWriter, r *http.Request) err
 }
 
 func (h *ProjectHandlers) updateTask(w http.ResponseWriter, r *http.Request) error {
+	version, err := parseIfMatch(r)
+	if err != nil {
+		return err
+	}
+
 	var requestData dto.UpdateTaskRequestDTO
 
-	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
-		return fmt.Errorf("error decoding request body: %w", err)
+	if err := utils.DecodeJSON(r, &requestData); err != nil {
+		return err
 	}
-	defer r.Body.Close()
 
 	cmd := usecases.NewUpdateTaskCommand(
 		requestData.ID,
+		requestData.Title,
 		requestData.Description,
+		models.TaskStatus(requestData.Status),
+		requestData.Priority,
+		requestData.DueDate,
+		requestData.Estimate,
 		requestData.EmployeeID,
 		requestData.ProjectID,
-		requestData.IsCompleted,
+		version,
 	)
 
 	if err := h.usecases.UpdateTask(r.Context(), cmd); err != nil {
@@ -0,0 +1,51 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/transport/dto"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/usecases"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+)
+
+func (h *ProjectHandlers) search(w http.ResponseWriter, r *http.Request) error {
+	params := r.URL.Query()
+
+	limit := usecases.DefaultSearchLimit
+	if value := params.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%w: invalid limit: %v", common.ErrInvalidInput, err)
+		}
+		limit = parsed
+	}
+
+	query := usecases.NewSearchQuery(params.Get("q"), limit)
+	results, err := h.usecases.Search(r.Context(), query)
+	if err != nil {
+		return err
+	}
+
+	responseData := make([]dto.SearchResultDTO, len(results))
+	for i, v := range results {
+		responseData[i] = dto.SearchResultDTO{
+			EntityType: v.EntityType,
+			ID:         v.ID,
+			ProjectID:  v.ProjectID,
+			TaskID:     v.TaskID,
+			Title:      v.Title,
+			Snippet:    v.Snippet,
+			Rank:       v.Rank,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(responseData); err != nil {
+		return fmt.Errorf("failed to encode search results to JSON: %w", err)
+	}
+
+	return nil
+}
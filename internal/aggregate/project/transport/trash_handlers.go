@@ -0,0 +1,54 @@
+package transport
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/usecases"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/utils"
+)
+
+func (h *ProjectHandlers) restoreProject(w http.ResponseWriter, r *http.Request) error {
+	id, err := utils.ExtractIDFromPathValue(r, "id")
+	if err != nil {
+		return fmt.Errorf("failed to extract id: %w", err)
+	}
+
+	cmd := usecases.NewRestoreProjectCommand(id)
+	if err := h.usecases.RestoreProject(r.Context(), cmd); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (h *ProjectHandlers) restoreTeam(w http.ResponseWriter, r *http.Request) error {
+	id, err := utils.ExtractIDFromPathValue(r, "id")
+	if err != nil {
+		return fmt.Errorf("failed to extract id: %w", err)
+	}
+
+	cmd := usecases.NewRestoreTeamCommand(id)
+	if err := h.usecases.RestoreTeam(r.Context(), cmd); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (h *ProjectHandlers) restoreTask(w http.ResponseWriter, r *http.Request) error {
+	id, err := utils.ExtractIDFromPathValue(r, "id")
+	if err != nil {
+		return fmt.Errorf("failed to extract id: %w", err)
+	}
+
+	cmd := usecases.NewRestoreTaskCommand(id)
+	if err := h.usecases.RestoreTask(r.Context(), cmd); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
@@ -1,8 +1,15 @@
 package transport
 
 import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/models"
 	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/transport/dto"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
 )
 
 func memberModelToDTO(member models.Member) dto.MemberDTO {
@@ -12,6 +19,19 @@ func memberModelToDTO(member models.Member) dto.MemberDTO {
 	}
 }
 
+func commentModelToDTO(comment *models.Comment) dto.CommentResponseDTO {
+	return dto.CommentResponseDTO{
+		ID:        comment.ID,
+		TaskID:    comment.TaskID,
+		AuthorID:  comment.AuthorID,
+		Body:      comment.Body,
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+		Edited:    comment.EditCount > 0,
+		EditCount: comment.EditCount,
+	}
+}
+
 func parseBool(value string) *bool {
 	if value == "" {
 		return nil
@@ -19,3 +39,61 @@ func parseBool(value string) *bool {
 	val := value == "true"
 	return &val
 }
+
+func parseUint32(value string) (*uint32, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	val := uint32(parsed)
+	return &val, nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func pageToDTO[T any](page *common.Page[T]) dto.PageResponseDTO[T] {
+	items := page.Items
+	if items == nil {
+		items = []T{}
+	}
+
+	return dto.PageResponseDTO[T]{
+		Items:      items,
+		NextCursor: page.NextCursor,
+		TotalCount: page.TotalCount,
+	}
+}
+
+// setETag записывает версию сущности в заголовок ETag
+func setETag(w http.ResponseWriter, version uint32) {
+	w.Header().Set("ETag", fmt.Sprintf("%q", strconv.FormatUint(uint64(version), 10)))
+}
+
+// parseIfMatch возвращает версию из заголовка If-Match. Без заголовка или для "*" возвращается 0.
+func parseIfMatch(r *http.Request) (uint32, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+
+	value = strings.TrimPrefix(value, "W/")
+	unquoted, err := strconv.Unquote(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: malformed If-Match header %s", common.ErrPreconditionFailed, value)
+	}
+
+	version, err := strconv.ParseUint(unquoted, 10, 32)
+	if err != nil || version == 0 {
+		return 0, fmt.Errorf("%w: unknown entity tag %s", common.ErrPreconditionFailed, value)
+	}
+
+	return uint32(version), nil
+}
@@ -0,0 +1,20 @@
+package usecases
+
+import "context"
+
+// AuditRecorder записывает событие в журнал аудита; вызывается внутри транзакции изменения
+type AuditRecorder interface {
+	Record(ctx context.Context, entityType, action string, entityID uint32, before, after any) error
+}
+
+const (
+	auditEntityProject = "project"
+	auditEntityTeam    = "team"
+	auditEntityTask    = "task"
+	auditEntityComment = "comment"
+
+	auditActionCreate  = "create"
+	auditActionUpdate  = "update"
+	auditActionDelete  = "delete"
+	auditActionRestore = "restore"
+)
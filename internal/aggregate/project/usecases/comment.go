@@ -0,0 +1,232 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/models"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+)
+
+// Запрос для получения комментариев задачи
+type GetTaskCommentsQuery struct {
+	taskID uint32
+}
+
+func NewGetTaskCommentsQuery(taskID uint32) *GetTaskCommentsQuery {
+	return &GetTaskCommentsQuery{taskID: taskID}
+}
+
+func (uc *ProjectUseCases) GetTaskComments(ctx context.Context, query *GetTaskCommentsQuery) ([]*models.Comment, error) {
+	ctx, span := startSpan(ctx, "GetTaskComments")
+	defer span.End()
+
+	if _, err := uc.authorizeTask(ctx, common.PermTaskRead, query.taskID); err != nil {
+		return nil, err
+	}
+
+	comments, err := uc.repo.GetCommentsByTaskID(ctx, query.taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments of task %d: %w", query.taskID, err)
+	}
+
+	return comments, nil
+}
+
+// Команда для создания комментария
+type CreateCommentCommand struct {
+	taskID uint32
+	body   string
+}
+
+func NewCreateCommentCommand(taskID uint32, body string) *CreateCommentCommand {
+	return &CreateCommentCommand{
+		taskID: taskID,
+		body:   body,
+	}
+}
+
+func (uc *ProjectUseCases) CreateComment(ctx context.Context, cmd *CreateCommentCommand) (uint32, error) {
+	ctx, span := startSpan(ctx, "CreateComment")
+	defer span.End()
+
+	claims := ctx.Value(common.ContextKeyClaims).(*common.Claims)
+
+	if strings.TrimSpace(cmd.body) == "" {
+		return 0, fmt.Errorf("%w: comment body is empty", common.ErrInvalidInput)
+	}
+
+	if _, err := uc.authorizeTask(ctx, common.PermCommentWrite, cmd.taskID); err != nil {
+		return 0, err
+	}
+
+	comment := &models.Comment{
+		TaskID:   cmd.taskID,
+		AuthorID: claims.UserID,
+		Body:     cmd.body,
+	}
+
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		id, err := uc.repo.CreateComment(ctx, comment)
+		if err != nil {
+			return fmt.Errorf("failed to create comment: %w", err)
+		}
+
+		comment.ID = id
+		return uc.audit.Record(ctx, auditEntityComment, auditActionCreate, id, nil, comment)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return comment.ID, nil
+}
+
+// Команда для изменения комментария
+type UpdateCommentCommand struct {
+	id   uint32
+	body string
+}
+
+func NewUpdateCommentCommand(id uint32, body string) *UpdateCommentCommand {
+	return &UpdateCommentCommand{
+		id:   id,
+		body: body,
+	}
+}
+
+func (uc *ProjectUseCases) UpdateComment(ctx context.Context, cmd *UpdateCommentCommand) error {
+	ctx, span := startSpan(ctx, "UpdateComment")
+	defer span.End()
+
+	claims := ctx.Value(common.ContextKeyClaims).(*common.Claims)
+
+	if strings.TrimSpace(cmd.body) == "" {
+		return fmt.Errorf("%w: comment body is empty", common.ErrInvalidInput)
+	}
+
+	return uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		comment, err := uc.authorizeComment(ctx, cmd.id)
+		if err != nil {
+			return err
+		}
+
+		before := *comment
+		comment.Body = cmd.body
+
+		if err := uc.repo.UpdateComment(ctx, comment, claims.UserID); err != nil {
+			return fmt.Errorf("failed to update comment: %w", err)
+		}
+
+		return uc.audit.Record(ctx, auditEntityComment, auditActionUpdate, cmd.id, &before, comment)
+	})
+}
+
+// Команда для удаления комментария
+type DeleteCommentCommand struct {
+	id uint32
+}
+
+func NewDeleteCommentCommand(id uint32) *DeleteCommentCommand {
+	return &DeleteCommentCommand{id: id}
+}
+
+func (uc *ProjectUseCases) DeleteComment(ctx context.Context, cmd *DeleteCommentCommand) error {
+	ctx, span := startSpan(ctx, "DeleteComment")
+	defer span.End()
+
+	return uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		comment, err := uc.authorizeComment(ctx, cmd.id)
+		if err != nil {
+			return err
+		}
+
+		if err := uc.repo.DeleteComment(ctx, cmd.id); err != nil {
+			return fmt.Errorf("failed to delete comment: %w", err)
+		}
+
+		return uc.audit.Record(ctx, auditEntityComment, auditActionDelete, cmd.id, comment, nil)
+	})
+}
+
+// Запрос для получения истории правок комментария
+type GetCommentEditsQuery struct {
+	commentID uint32
+}
+
+func NewGetCommentEditsQuery(commentID uint32) *GetCommentEditsQuery {
+	return &GetCommentEditsQuery{commentID: commentID}
+}
+
+func (uc *ProjectUseCases) GetCommentEdits(ctx context.Context, query *GetCommentEditsQuery) ([]*models.CommentEdit, error) {
+	ctx, span := startSpan(ctx, "GetCommentEdits")
+	defer span.End()
+
+	comment, err := uc.getComment(ctx, query.commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.authorizeTask(ctx, common.PermTaskRead, comment.TaskID); err != nil {
+		return nil, err
+	}
+
+	edits, err := uc.repo.GetCommentEdits(ctx, query.commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get edits of comment %d: %w", query.commentID, err)
+	}
+
+	return edits, nil
+}
+
+func (uc *ProjectUseCases) getComment(ctx context.Context, id uint32) (*models.Comment, error) {
+	comment, err := uc.repo.GetCommentById(ctx, id)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return nil, fmt.Errorf("comment with id %d is not found: %w", id, err)
+		}
+		return nil, fmt.Errorf("failed to get comment with id %d: %w", id, err)
+	}
+	return comment, nil
+}
+
+// authorizeComment разрешает изменять комментарий только автору или модератору
+func (uc *ProjectUseCases) authorizeComment(ctx context.Context, id uint32) (*models.Comment, error) {
+	claims := ctx.Value(common.ContextKeyClaims).(*common.Claims)
+
+	comment, err := uc.getComment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.authorizeTask(ctx, common.PermCommentWrite, comment.TaskID); err != nil {
+		return nil, err
+	}
+
+	if comment.AuthorID != claims.UserID {
+		if err := uc.policy.Authorize(ctx, common.PermCommentModerate); err != nil {
+			return nil, err
+		}
+	}
+
+	return comment, nil
+}
+
+// authorizeTask проверяет право permission в команде, которой принадлежит задача
+func (uc *ProjectUseCases) authorizeTask(ctx context.Context, permission common.Permission, taskID uint32) (*models.Task, error) {
+	task, err := uc.repo.GetTaskById(ctx, taskID)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return nil, fmt.Errorf("task with id %d is not found: %w", taskID, err)
+		}
+		return nil, fmt.Errorf("failed to get task with id %d: %w", taskID, err)
+	}
+
+	if err := uc.authorizeProject(ctx, permission, task.ProjectID); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
@@ -0,0 +1,207 @@
+package usecases
+
+import (
+	"context"
+	"slices"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/models"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+)
+
+// fakeProjectRepository хранит проекты, команды и задачи в памяти. Методы, которые
+// тестам не нужны, не реализованы: вызов встроенного nil-интерфейса завершит тест паникой.
+type fakeProjectRepository struct {
+	ProjectRepository
+
+	projects map[uint32]*models.Project
+	teams    map[uint32]*models.Team
+	tasks    map[uint32]*models.Task
+	users    map[uint32]string
+	// deactivated - ID деактивированных пользователей из users
+	deactivated []uint32
+}
+
+func newFakeProjectRepository() *fakeProjectRepository {
+	return &fakeProjectRepository{
+		projects: map[uint32]*models.Project{},
+		teams:    map[uint32]*models.Team{},
+		tasks:    map[uint32]*models.Task{},
+		users:    map[uint32]string{},
+	}
+}
+
+func (r *fakeProjectRepository) GetProjectById(_ context.Context, projectID uint32) (*models.Project, error) {
+	project, ok := r.projects[projectID]
+	if !ok {
+		return nil, common.ErrNotFound
+	}
+	copied := *project
+	return &copied, nil
+}
+
+func (r *fakeProjectRepository) UpdateProject(_ context.Context, project *models.Project) error {
+	current, ok := r.projects[project.Id]
+	if !ok {
+		return common.ErrNotFound
+	}
+	if project.Version != 0 && project.Version != current.Version {
+		return common.ErrPreconditionFailed
+	}
+	stored := *project
+	stored.Version = current.Version + 1
+	r.projects[project.Id] = &stored
+	return nil
+}
+
+func (r *fakeProjectRepository) GetTeamById(_ context.Context, teamID uint32) (*models.Team, error) {
+	team, ok := r.teams[teamID]
+	if !ok {
+		return nil, common.ErrNotFound
+	}
+	copied := *team
+	copied.Members = slices.Clone(team.Members)
+	return &copied, nil
+}
+
+func (r *fakeProjectRepository) CreateTeam(_ context.Context, team *models.Team) (uint32, error) {
+	stored := *team
+	stored.ID = uint32(len(r.teams) + 1)
+	stored.Members = slices.Clone(team.Members)
+	stored.Version = 1
+	r.teams[stored.ID] = &stored
+	return stored.ID, nil
+}
+
+func (r *fakeProjectRepository) UpdateTeam(_ context.Context, team *models.Team) error {
+	current, ok := r.teams[team.ID]
+	if !ok {
+		return common.ErrNotFound
+	}
+	if team.Version != 0 && team.Version != current.Version {
+		return common.ErrPreconditionFailed
+	}
+	stored := *team
+	stored.Members = slices.Clone(team.Members)
+	stored.Version = current.Version + 1
+	r.teams[team.ID] = &stored
+	return nil
+}
+
+func (r *fakeProjectRepository) GetMember(_ context.Context, userID uint32, teamID uint32) (*models.Member, error) {
+	name, ok := r.users[userID]
+	if !ok {
+		return nil, common.ErrNotFound
+	}
+
+	member := &models.Member{ID: userID, Name: name, Deactivated: slices.Contains(r.deactivated, userID)}
+	if team, ok := r.teams[teamID]; ok {
+		for _, m := range team.Members {
+			if m.ID == userID {
+				member.Role, member.TeamID = m.Role, teamID
+			}
+		}
+	}
+	return member, nil
+}
+
+func (r *fakeProjectRepository) GetTaskById(_ context.Context, taskID uint32) (*models.Task, error) {
+	task, ok := r.tasks[taskID]
+	if !ok {
+		return nil, common.ErrNotFound
+	}
+	copied := *task
+	return &copied, nil
+}
+
+func (r *fakeProjectRepository) CreateTask(_ context.Context, task *models.Task) (uint32, error) {
+	stored := *task
+	stored.ID = uint32(len(r.tasks) + 1)
+	stored.Version = 1
+	r.tasks[stored.ID] = &stored
+	return stored.ID, nil
+}
+
+func (r *fakeProjectRepository) UpdateTask(_ context.Context, task *models.Task) error {
+	current, ok := r.tasks[task.ID]
+	if !ok {
+		return common.ErrNotFound
+	}
+	if task.Version != 0 && task.Version != current.Version {
+		return common.ErrPreconditionFailed
+	}
+	stored := *task
+	stored.Version = current.Version + 1
+	r.tasks[task.ID] = &stored
+	return nil
+}
+
+// fakePolicy разрешает всё, кроме прав из denied. Если teams не nil, права в командах
+// действуют только в перечисленных командах.
+type fakePolicy struct {
+	denied []common.Permission
+	teams  []uint32
+}
+
+func (p *fakePolicy) Can(_ context.Context, permission common.Permission) (bool, error) {
+	return !slices.Contains(p.denied, permission), nil
+}
+
+func (p *fakePolicy) Authorize(ctx context.Context, permission common.Permission) error {
+	if ok, _ := p.Can(ctx, permission); !ok {
+		return common.ErrForbidden
+	}
+	return nil
+}
+
+func (p *fakePolicy) AuthorizeTeam(ctx context.Context, permission common.Permission, teamID uint32) error {
+	if p.teams != nil && !slices.Contains(p.teams, teamID) {
+		return common.ErrForbidden
+	}
+	return p.Authorize(ctx, permission)
+}
+
+func (p *fakePolicy) VisibleTeams(context.Context, common.Permission) ([]uint32, bool, error) {
+	return nil, true, nil
+}
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeAuditRecorder struct {
+	actions []string
+}
+
+func (r *fakeAuditRecorder) Record(_ context.Context, entityType, action string, _ uint32, _, _ any) error {
+	r.actions = append(r.actions, entityType+"."+action)
+	return nil
+}
+
+type fakeMetrics struct{}
+
+func (fakeMetrics) ProjectCreated() {}
+func (fakeMetrics) TaskCreated()    {}
+func (fakeMetrics) TaskCompleted()  {}
+
+type testProjects struct {
+	*ProjectUseCases
+	repo   *fakeProjectRepository
+	policy *fakePolicy
+	audit  *fakeAuditRecorder
+}
+
+func newTestProjects() *testProjects {
+	t := &testProjects{
+		repo:   newFakeProjectRepository(),
+		policy: &fakePolicy{},
+		audit:  &fakeAuditRecorder{},
+	}
+	t.ProjectUseCases = NewProjectUseCases(t.repo, fakeTransactor{}, t.policy, models.DefaultWorkflow(), t.audit, fakeMetrics{})
+	return t
+}
+
+func contextWithClaims(userID uint32, role string) context.Context {
+	return context.WithValue(context.Background(), common.ContextKeyClaims, &common.Claims{UserID: userID, Role: role})
+}
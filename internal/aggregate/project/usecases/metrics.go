@@ -0,0 +1,8 @@
+package usecases
+
+// Metrics - доменные счётчики проектов и задач
+type Metrics interface {
+	ProjectCreated()
+	TaskCreated()
+	TaskCompleted()
+}
@@ -0,0 +1,208 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/models"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+)
+
+// PatchField - поле частичного обновления. Поле без Present не изменяется.
+type PatchField[T any] struct {
+	Value   T
+	Present bool
+}
+
+func SetField[T any](value T) PatchField[T] {
+	return PatchField[T]{Value: value, Present: true}
+}
+
+func (f PatchField[T]) apply(current T) T {
+	if f.Present {
+		return f.Value
+	}
+	return current
+}
+
+type ProjectPatch struct {
+	Name           PatchField[string]
+	Description    PatchField[string]
+	StartDate      PatchField[time.Time]
+	PlannedEndDate PatchField[time.Time]
+	ActualEndDate  PatchField[time.Time]
+	Status         PatchField[string]
+	Priority       PatchField[uint32]
+	TeamID         PatchField[uint32]
+	Budget         PatchField[float64]
+}
+
+// Команда для частичного обновления проекта
+type PatchProjectCommand struct {
+	id      uint32
+	patch   ProjectPatch
+	version uint32
+}
+
+func NewPatchProjectCommand(id uint32, patch ProjectPatch, version uint32) *PatchProjectCommand {
+	return &PatchProjectCommand{
+		id:      id,
+		patch:   patch,
+		version: version,
+	}
+}
+
+func (uc *ProjectUseCases) PatchProject(ctx context.Context, cmd *PatchProjectCommand) error {
+	ctx, span := startSpan(ctx, "PatchProject")
+	defer span.End()
+
+	// Права проверяются до чтения, чтобы без них не узнать о существовании и версии проекта
+	if err := uc.policy.Authorize(ctx, common.PermProjectWrite); err != nil {
+		return err
+	}
+
+	current, err := uc.repo.GetProjectById(ctx, cmd.id)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return fmt.Errorf("project with id %d is not found: %w", cmd.id, err)
+		}
+		return fmt.Errorf("failed to get project with id %d: %w", cmd.id, err)
+	}
+
+	if err := checkVersion(current.Version, cmd.version); err != nil {
+		return err
+	}
+
+	// Текущая версия защищает от изменений, сделанных между чтением и записью
+	patch := cmd.patch
+	return uc.UpdateProject(ctx, NewUpdateProjectCommand(
+		cmd.id,
+		patch.Name.apply(current.Name),
+		patch.Description.apply(current.Description),
+		patch.StartDate.apply(current.StartDate),
+		patch.PlannedEndDate.apply(current.PlannedEndDate),
+		patch.ActualEndDate.apply(current.ActualEndDate),
+		patch.Status.apply(current.Status),
+		patch.Priority.apply(current.Priority),
+		patch.TeamID.apply(projectTeamID(current)),
+		patch.Budget.apply(current.Budget),
+		current.Version,
+	))
+}
+
+type TeamPatch struct {
+	Name      PatchField[string]
+	Members   PatchField[[]Member]
+	ManagerID PatchField[uint32]
+}
+
+// Команда для частичного обновления команды
+type PatchTeamCommand struct {
+	id      uint32
+	patch   TeamPatch
+	version uint32
+}
+
+func NewPatchTeamCommand(id uint32, patch TeamPatch, version uint32) *PatchTeamCommand {
+	return &PatchTeamCommand{
+		id:      id,
+		patch:   patch,
+		version: version,
+	}
+}
+
+func (uc *ProjectUseCases) PatchTeam(ctx context.Context, cmd *PatchTeamCommand) error {
+	ctx, span := startSpan(ctx, "PatchTeam")
+	defer span.End()
+
+	if err := uc.policy.AuthorizeTeam(ctx, common.PermTeamManage, cmd.id); err != nil {
+		return err
+	}
+
+	current, err := uc.repo.GetTeamById(ctx, cmd.id)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return fmt.Errorf("team with id %d is not found: %w", cmd.id, err)
+		}
+		return fmt.Errorf("failed to get team with id %d: %w", cmd.id, err)
+	}
+
+	if err := checkVersion(current.Version, cmd.version); err != nil {
+		return err
+	}
+
+	// UpdateTeam заменяет состав команды целиком, поэтому без members в патче
+	// передаём текущих участников: GetTeamById загружает их полностью, без пагинации
+	members := cmd.patch.Members.Value
+	if !cmd.patch.Members.Present {
+		members = make([]Member, len(current.Members))
+		for i, member := range current.Members {
+			members[i] = *NewMember(member.ID, member.Name, member.Role)
+		}
+	}
+
+	return uc.UpdateTeam(ctx, NewUpdateTeamCommand(
+		cmd.id,
+		cmd.patch.Name.apply(current.Name),
+		members,
+		cmd.patch.ManagerID.apply(current.ManagerID),
+		current.Version,
+	))
+}
+
+type TaskPatch struct {
+	Title       PatchField[string]
+	Description PatchField[string]
+	Status      PatchField[models.TaskStatus]
+	Priority    PatchField[uint32]
+	DueDate     PatchField[*time.Time]
+	Estimate    PatchField[*uint32]
+	EmployeeID  PatchField[uint32]
+	ProjectID   PatchField[uint32]
+}
+
+// Команда для частичного обновления задачи
+type PatchTaskCommand struct {
+	id      uint32
+	patch   TaskPatch
+	version uint32
+}
+
+func NewPatchTaskCommand(id uint32, patch TaskPatch, version uint32) *PatchTaskCommand {
+	return &PatchTaskCommand{
+		id:      id,
+		patch:   patch,
+		version: version,
+	}
+}
+
+func (uc *ProjectUseCases) PatchTask(ctx context.Context, cmd *PatchTaskCommand) error {
+	ctx, span := startSpan(ctx, "PatchTask")
+	defer span.End()
+
+	// Права на задачу зависят от её проекта, поэтому версия сверяется только после их проверки
+	current, err := uc.authorizeTask(ctx, common.PermTaskWrite, cmd.id)
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersion(current.Version, cmd.version); err != nil {
+		return err
+	}
+
+	patch := cmd.patch
+	return uc.UpdateTask(ctx, NewUpdateTaskCommand(
+		cmd.id,
+		patch.Title.apply(current.Title),
+		patch.Description.apply(current.Description),
+		patch.Status.apply(current.Status),
+		patch.Priority.apply(current.Priority),
+		patch.DueDate.apply(current.DueDate),
+		patch.Estimate.apply(current.Estimate),
+		patch.EmployeeID.apply(current.EmployeeID),
+		patch.ProjectID.apply(current.ProjectID),
+		current.Version,
+	))
+}
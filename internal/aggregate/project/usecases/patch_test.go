@@ -0,0 +1,241 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/models"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedProject(uc *testProjects) *models.Project {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	project := &models.Project{
+		Id:             1,
+		Name:           "Billing",
+		Description:    "Invoices",
+		StartDate:      start,
+		PlannedEndDate: start.AddDate(0, 3, 0),
+		ActualEndDate:  start.AddDate(0, 2, 0),
+		Status:         "active",
+		Priority:       2,
+		Team:           &models.Team{ID: 10},
+		Budget:         1000,
+		Version:        3,
+	}
+	uc.repo.projects[project.Id] = project
+	return project
+}
+
+func TestPatchProjectMergesFields(t *testing.T) {
+	uc := newTestProjects()
+	seedProject(uc)
+
+	err := uc.PatchProject(context.Background(), NewPatchProjectCommand(1, ProjectPatch{
+		Name:   SetField("Billing v2"),
+		Budget: SetField(1500.0),
+	}, 3))
+	require.NoError(t, err)
+
+	stored := uc.repo.projects[1]
+	assert.Equal(t, "Billing v2", stored.Name)
+	assert.Equal(t, 1500.0, stored.Budget)
+	// Поля, которых нет в патче, сохраняются
+	assert.Equal(t, "Invoices", stored.Description)
+	assert.Equal(t, "active", stored.Status)
+	assert.Equal(t, uint32(10), stored.Team.ID)
+	assert.Equal(t, uint32(4), stored.Version)
+	assert.Equal(t, []string{"project.update"}, uc.audit.actions)
+}
+
+func TestPatchProjectClearsNullableFields(t *testing.T) {
+	uc := newTestProjects()
+	seedProject(uc)
+
+	// null в Merge Patch приходит как присутствующее поле с нулевым значением
+	err := uc.PatchProject(context.Background(), NewPatchProjectCommand(1, ProjectPatch{
+		Description:   PatchField[string]{Present: true},
+		ActualEndDate: PatchField[time.Time]{Present: true},
+		TeamID:        PatchField[uint32]{Present: true},
+	}, 0))
+	require.NoError(t, err)
+
+	stored := uc.repo.projects[1]
+	assert.Empty(t, stored.Description)
+	assert.True(t, stored.ActualEndDate.IsZero())
+	assert.Zero(t, projectTeamID(stored))
+	assert.Equal(t, "Billing", stored.Name)
+}
+
+func TestPatchProjectVersionMismatch(t *testing.T) {
+	uc := newTestProjects()
+	seedProject(uc)
+
+	err := uc.PatchProject(context.Background(), NewPatchProjectCommand(1, ProjectPatch{Name: SetField("Billing v2")}, 2))
+	assert.ErrorIs(t, err, common.ErrPreconditionFailed)
+	assert.Equal(t, "Billing", uc.repo.projects[1].Name)
+	assert.Empty(t, uc.audit.actions)
+}
+
+func TestPatchProjectValidatesMergedResult(t *testing.T) {
+	uc := newTestProjects()
+	seedProject(uc)
+
+	err := uc.PatchProject(context.Background(), NewPatchProjectCommand(1, ProjectPatch{Name: SetField(strings.Repeat("a", 81))}, 0))
+	assert.ErrorIs(t, err, common.ErrValidation)
+
+	err = uc.PatchProject(context.Background(), NewPatchProjectCommand(1, ProjectPatch{Name: SetField(" ")}, 0))
+	assert.ErrorIs(t, err, common.ErrValidation)
+
+	// Новая дата начала проверяется вместе с текущей плановой датой окончания
+	err = uc.PatchProject(context.Background(), NewPatchProjectCommand(1, ProjectPatch{
+		StartDate: SetField(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)),
+	}, 0))
+	assert.ErrorIs(t, err, common.ErrValidation)
+
+	assert.Equal(t, "Billing", uc.repo.projects[1].Name)
+}
+
+func TestPatchProjectAuthorizesBeforeLoading(t *testing.T) {
+	uc := newTestProjects()
+	seedProject(uc)
+	uc.policy.denied = []common.Permission{common.PermProjectWrite}
+
+	// Без прав ответ не зависит от существования проекта и версии
+	err := uc.PatchProject(context.Background(), NewPatchProjectCommand(404, ProjectPatch{}, 0))
+	assert.ErrorIs(t, err, common.ErrForbidden)
+
+	err = uc.PatchProject(context.Background(), NewPatchProjectCommand(1, ProjectPatch{}, 2))
+	assert.ErrorIs(t, err, common.ErrForbidden)
+}
+
+func seedTeam(uc *testProjects) {
+	uc.repo.users = map[uint32]string{1: "ann", 2: "bob", 3: "eve"}
+	uc.repo.teams[10] = &models.Team{
+		ID:   10,
+		Name: "Core",
+		Members: []models.Member{
+			{ID: 1, Name: "ann", Role: common.RoleManager},
+			{ID: 2, Name: "bob", Role: common.RoleMember},
+		},
+		ManagerID: 1,
+		Version:   5,
+	}
+}
+
+func TestPatchTeamKeepsMembers(t *testing.T) {
+	uc := newTestProjects()
+	seedTeam(uc)
+
+	err := uc.PatchTeam(context.Background(), NewPatchTeamCommand(10, TeamPatch{Name: SetField("Platform")}, 5))
+	require.NoError(t, err)
+
+	stored := uc.repo.teams[10]
+	assert.Equal(t, "Platform", stored.Name)
+	assert.Len(t, stored.Members, 2)
+	assert.Equal(t, uint32(1), stored.ManagerID)
+}
+
+func TestPatchTeamVersionMismatch(t *testing.T) {
+	uc := newTestProjects()
+	seedTeam(uc)
+
+	err := uc.PatchTeam(context.Background(), NewPatchTeamCommand(10, TeamPatch{Name: SetField("Platform")}, 4))
+	assert.ErrorIs(t, err, common.ErrPreconditionFailed)
+	assert.Equal(t, "Core", uc.repo.teams[10].Name)
+}
+
+func TestPatchTeamValidatesName(t *testing.T) {
+	uc := newTestProjects()
+	seedTeam(uc)
+
+	err := uc.PatchTeam(context.Background(), NewPatchTeamCommand(10, TeamPatch{Name: SetField(strings.Repeat("a", 81))}, 0))
+	assert.ErrorIs(t, err, common.ErrValidation)
+}
+
+func TestPatchTeamAuthorizesBeforeLoading(t *testing.T) {
+	uc := newTestProjects()
+	seedTeam(uc)
+	uc.policy.denied = []common.Permission{common.PermTeamManage}
+
+	err := uc.PatchTeam(context.Background(), NewPatchTeamCommand(404, TeamPatch{}, 0))
+	assert.ErrorIs(t, err, common.ErrForbidden)
+
+	err = uc.PatchTeam(context.Background(), NewPatchTeamCommand(10, TeamPatch{}, 4))
+	assert.ErrorIs(t, err, common.ErrForbidden)
+}
+
+func seedTask(uc *testProjects) {
+	seedProject(uc)
+	due := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	estimate := uint32(8)
+	uc.repo.tasks[7] = &models.Task{
+		ID:          7,
+		Title:       "Send invoices",
+		Description: "Monthly",
+		Status:      models.TaskStatusTodo,
+		Priority:    1,
+		DueDate:     &due,
+		Estimate:    &estimate,
+		EmployeeID:  2,
+		ProjectID:   1,
+		Version:     1,
+	}
+}
+
+func TestPatchTaskMergesAndClearsFields(t *testing.T) {
+	uc := newTestProjects()
+	seedTask(uc)
+
+	err := uc.PatchTask(contextWithClaims(2, common.RoleMember), NewPatchTaskCommand(7, TaskPatch{
+		Status:   SetField(models.TaskStatusInProgress),
+		DueDate:  PatchField[*time.Time]{Present: true},
+		Estimate: PatchField[*uint32]{Present: true},
+	}, 1))
+	require.NoError(t, err)
+
+	stored := uc.repo.tasks[7]
+	assert.Equal(t, models.TaskStatusInProgress, stored.Status)
+	assert.Nil(t, stored.DueDate)
+	assert.Nil(t, stored.Estimate)
+	assert.Equal(t, "Send invoices", stored.Title)
+	assert.Equal(t, "Monthly", stored.Description)
+	assert.Equal(t, uint32(2), stored.Version)
+	assert.Equal(t, []string{"task.update"}, uc.audit.actions)
+}
+
+func TestPatchTaskVersionMismatch(t *testing.T) {
+	uc := newTestProjects()
+	seedTask(uc)
+
+	err := uc.PatchTask(contextWithClaims(2, common.RoleMember), NewPatchTaskCommand(7, TaskPatch{Title: SetField("Renamed")}, 9))
+	assert.ErrorIs(t, err, common.ErrPreconditionFailed)
+	assert.Equal(t, "Send invoices", uc.repo.tasks[7].Title)
+}
+
+func TestPatchTaskValidatesTitle(t *testing.T) {
+	uc := newTestProjects()
+	seedTask(uc)
+	ctx := contextWithClaims(2, common.RoleMember)
+
+	err := uc.PatchTask(ctx, NewPatchTaskCommand(7, TaskPatch{Title: SetField("")}, 0))
+	assert.ErrorIs(t, err, common.ErrValidation)
+
+	err = uc.PatchTask(ctx, NewPatchTaskCommand(7, TaskPatch{Title: SetField(strings.Repeat("a", 256))}, 0))
+	assert.ErrorIs(t, err, common.ErrValidation)
+
+	assert.Equal(t, "Send invoices", uc.repo.tasks[7].Title)
+}
+
+func TestPatchTaskAuthorizesBeforeVersionCheck(t *testing.T) {
+	uc := newTestProjects()
+	seedTask(uc)
+	uc.policy.denied = []common.Permission{common.PermTaskWrite}
+
+	err := uc.PatchTask(contextWithClaims(2, common.RoleMember), NewPatchTaskCommand(7, TaskPatch{}, 9))
+	assert.ErrorIs(t, err, common.ErrForbidden)
+}
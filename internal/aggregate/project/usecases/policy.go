@@ -0,0 +1,14 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+)
+
+type Policy interface {
+	Can(ctx context.Context, permission common.Permission) (bool, error)
+	Authorize(ctx context.Context, permission common.Permission) error
+	AuthorizeTeam(ctx context.Context, permission common.Permission, teamID uint32) error
+	VisibleTeams(ctx context.Context, permission common.Permission) (teamIDs []uint32, all bool, err error)
+}
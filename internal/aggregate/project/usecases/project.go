@@ -4,70 +4,69 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
-	"os"
-	"sync"
+	"slices"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/models"
 	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/validation"
 )
 
-const adminRole string = "admin"
-
 type ProjectUseCases struct {
-	repo   ProjectRepository
-	logger *log.Logger
-	mu     sync.Mutex
+	repo     ProjectRepository
+	tx       Transactor
+	policy   Policy
+	workflow *models.Workflow
+	audit    AuditRecorder
+	metrics  Metrics
 }
 
-func NewProjectUseCases(repo ProjectRepository) *ProjectUseCases {
-	logFile, err := os.OpenFile("business_operations.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		panic(fmt.Sprintf("failed to create log file: %v", err))
-	}
-
-	logger := log.New(logFile, "INFO: ", log.Ldate|log.Ltime|log.Lshortfile)
-
+func NewProjectUseCases(repo ProjectRepository, tx Transactor, policy Policy, workflow *models.Workflow, audit AuditRecorder, metrics Metrics) *ProjectUseCases {
 	return &ProjectUseCases{
-		repo:   repo,
-		logger: logger,
+		repo:     repo,
+		tx:       tx,
+		policy:   policy,
+		workflow: workflow,
+		audit:    audit,
+		metrics:  metrics,
 	}
 }
 
-func (uc *ProjectUseCases) logMessage(ctx context.Context, message string) {
-	uc.mu.Lock()
-	defer uc.mu.Unlock()
+// Поля, по которым можно сортировать списки
+var (
+	ProjectSortFields = []string{"id", "name", "start_date", "planned_end_date", "status", "priority", "budget"}
+	TeamSortFields    = []string{"id", "name"}
+	MemberSortFields  = []string{"id", "name"}
+	TaskSortFields    = []string{"id", "title", "status", "priority", "due_date", "created_at", "updated_at"}
+)
 
-	claims := ctx.Value(common.ContextKeyClaims).(*common.Claims)
-	uc.logger.Printf("UserID: %d, Role: %s - %s", claims.UserID, claims.Role, message)
+type ProjectFilter struct {
+	// TeamIDs ограничивает выборку проектами этих команд, nil - без ограничения
+	TeamIDs []uint32
 }
 
 // Запрос для получения всех проектов
-func (uc *ProjectUseCases) GetAllProjects(ctx context.Context) ([]*models.Project, error) {
-	claims := ctx.Value(common.ContextKeyClaims).(*common.Claims)
-	projects, err := uc.repo.GetAllProjects()
-	var result []*models.Project
+func (uc *ProjectUseCases) GetAllProjects(ctx context.Context, page common.PageRequest) (*common.Page[*models.Project], error) {
+	ctx, span := startSpan(ctx, "GetAllProjects")
+	defer span.End()
+
+	teamIDs, all, err := uc.policy.VisibleTeams(ctx, common.PermProjectRead)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get all projects: %w", err)
+		return nil, err
 	}
 
-	if claims.Role != adminRole {
-		teamID, err := uc.repo.GetTeamIdByUserID(claims.UserID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get team by userID %d: %w", claims.UserID, err)
-		}
-
-		for _, project := range projects {
-			if project.Team.ID == teamID {
-				result = append(result, project)
-			}
-		}
+	var filter ProjectFilter
+	if !all {
+		filter.TeamIDs = nonNilTeamIDs(teamIDs)
+	}
 
-		return result, nil
+	projects, err := uc.repo.GetAllProjects(ctx, filter, page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all projects: %w", err)
 	}
 
-	uc.logMessage(ctx, "Fetching all projects")
 	return projects, nil
 }
 
@@ -81,25 +80,24 @@ func NewGetProjectByIDQuery(id uint32) *GetProjectByIDQuery {
 }
 
 func (uc *ProjectUseCases) GetProjectByID(ctx context.Context, query *GetProjectByIDQuery) (*models.Project, error) {
-	claims := ctx.Value(common.ContextKeyClaims).(*common.Claims)
+	ctx, span := startSpan(ctx, "GetProjectByID")
+	defer span.End()
+
+	// Без права чтения запрос отклоняется до обращения к базе, а проект чужой команды
+	// неотличим от несуществующего
+	if err := uc.policy.Authorize(ctx, common.PermProjectRead); err != nil {
+		return nil, err
+	}
 
-	project, err := uc.repo.GetProjectById(query.id)
+	project, err := uc.repo.GetProjectById(ctx, query.id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get project by id: %w", err)
 	}
 
-	if claims.Role != adminRole {
-		teamID, err := uc.repo.GetTeamIdByUserID(claims.UserID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get team by userID %d: %w", claims.UserID, err)
-		}
-
-		if project.Team.ID != teamID {
-			return nil, common.ErrForbidden
-		}
+	if err := uc.policy.AuthorizeTeam(ctx, common.PermProjectRead, projectTeamID(project)); err != nil {
+		return nil, hideForbidden(err, "project", query.id)
 	}
 
-	uc.logMessage(ctx, fmt.Sprintf("Fetching project by id: %d", query.id))
 	return project, nil
 }
 
@@ -134,10 +132,11 @@ func NewCreateProjectCommand(
 }
 
 func (uc *ProjectUseCases) CreateProject(ctx context.Context, cmd *CreateProjectCommand) (uint32, error) {
-	claims := ctx.Value(common.ContextKeyClaims).(*common.Claims)
+	ctx, span := startSpan(ctx, "CreateProject")
+	defer span.End()
 
-	if claims.Role != adminRole {
-		return 0, common.ErrForbidden
+	if err := uc.policy.Authorize(ctx, common.PermProjectWrite); err != nil {
+		return 0, err
 	}
 
 	project := &models.Project{
@@ -152,13 +151,25 @@ func (uc *ProjectUseCases) CreateProject(ctx context.Context, cmd *CreateProject
 		Budget:         cmd.budget,
 	}
 
-	id, err := uc.repo.CreateProject(project)
+	if err := validateProject(project); err != nil {
+		return 0, err
+	}
+
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		id, err := uc.repo.CreateProject(ctx, project)
+		if err != nil {
+			return fmt.Errorf("failed to create project: %w", err)
+		}
+
+		project.Id = id
+		return uc.audit.Record(ctx, auditEntityProject, auditActionCreate, id, nil, project)
+	})
 	if err != nil {
-		return 0, fmt.Errorf("failed to create project: %w", err)
+		return 0, err
 	}
 
-	uc.logMessage(ctx, fmt.Sprintf("Creating new project (id: %d)", id))
-	return id, nil
+	uc.metrics.ProjectCreated()
+	return project.Id, nil
 }
 
 // Команда для обновления проекта
@@ -173,6 +184,7 @@ type UpdateProjectCommand struct {
 	priority       uint32
 	teamId         uint32
 	budget         float64
+	version        uint32
 }
 
 func NewUpdateProjectCommand(
@@ -185,7 +197,8 @@ func NewUpdateProjectCommand(
 	status string,
 	priority uint32,
 	teamId uint32,
-	budget float64) *UpdateProjectCommand {
+	budget float64,
+	version uint32) *UpdateProjectCommand {
 	return &UpdateProjectCommand{
 		id:             id,
 		name:           name,
@@ -197,23 +210,16 @@ func NewUpdateProjectCommand(
 		priority:       priority,
 		teamId:         teamId,
 		budget:         budget,
+		version:        version,
 	}
 }
 
 func (uc *ProjectUseCases) UpdateProject(ctx context.Context, cmd *UpdateProjectCommand) error {
-	claims := ctx.Value(common.ContextKeyClaims).(*common.Claims)
+	ctx, span := startSpan(ctx, "UpdateProject")
+	defer span.End()
 
-	if claims.Role != adminRole {
-		return common.ErrForbidden
-	}
-
-	_, err := uc.repo.GetProjectById(cmd.id)
-
-	if err != nil {
-		if errors.Is(err, common.ErrNotFound) {
-			return fmt.Errorf("project with id %d is not found: %w", cmd.id, err)
-		}
-		return fmt.Errorf("failed to get project with id %d: %w", cmd.id, err)
+	if err := uc.policy.Authorize(ctx, common.PermProjectWrite); err != nil {
+		return err
 	}
 
 	project := &models.Project{
@@ -227,14 +233,32 @@ func (uc *ProjectUseCases) UpdateProject(ctx context.Context, cmd *UpdateProject
 		Priority:       cmd.priority,
 		Team:           &models.Team{ID: cmd.teamId},
 		Budget:         cmd.budget,
+		Version:        cmd.version,
 	}
 
-	if err := uc.repo.UpdateProject(project); err != nil {
-		return fmt.Errorf("failed to update project: %w", err)
+	if err := validateProject(project); err != nil {
+		return err
 	}
 
-	uc.logMessage(ctx, fmt.Sprintf("Updating project (id: %d)", cmd.id))
-	return nil
+	return uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		current, err := uc.repo.GetProjectById(ctx, cmd.id)
+		if err != nil {
+			if errors.Is(err, common.ErrNotFound) {
+				return fmt.Errorf("project with id %d is not found: %w", cmd.id, err)
+			}
+			return fmt.Errorf("failed to get project with id %d: %w", cmd.id, err)
+		}
+
+		if err := checkVersion(current.Version, cmd.version); err != nil {
+			return err
+		}
+
+		if err := uc.repo.UpdateProject(ctx, project); err != nil {
+			return fmt.Errorf("failed to update project: %w", err)
+		}
+
+		return uc.audit.Record(ctx, auditEntityProject, auditActionUpdate, cmd.id, current, project)
+	})
 }
 
 // Команда для удаления проекта
@@ -247,41 +271,54 @@ func NewDeleteProjectCommand(id uint32) *DeleteProjectCommand {
 }
 
 func (uc *ProjectUseCases) DeleteProject(ctx context.Context, cmd *DeleteProjectCommand) error {
-	claims := ctx.Value(common.ContextKeyClaims).(*common.Claims)
+	ctx, span := startSpan(ctx, "DeleteProject")
+	defer span.End()
 
-	if claims.Role != adminRole {
-		return common.ErrForbidden
+	if err := uc.policy.Authorize(ctx, common.PermProjectWrite); err != nil {
+		return err
 	}
 
-	if err := uc.repo.DeleteProject(cmd.id); err != nil {
-		return fmt.Errorf("failed to delete project: %w", err)
-	}
+	return uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		project, err := uc.repo.GetProjectById(ctx, cmd.id)
+		if err != nil {
+			if errors.Is(err, common.ErrNotFound) {
+				return fmt.Errorf("project with id %d is not found: %w", cmd.id, err)
+			}
+			return fmt.Errorf("failed to get project with id %d: %w", cmd.id, err)
+		}
 
-	uc.logMessage(ctx, fmt.Sprintf("Deleting project (id: %d)", cmd.id))
-	return nil
+		if err := uc.repo.DeleteProject(ctx, cmd.id); err != nil {
+			return fmt.Errorf("failed to delete project: %w", err)
+		}
+
+		return uc.audit.Record(ctx, auditEntityProject, auditActionDelete, cmd.id, project, nil)
+	})
 }
 
-func (uc *ProjectUseCases) GetAllTeams(ctx context.Context) ([]*models.Team, error) {
-	claims := ctx.Value(common.ContextKeyClaims).(*common.Claims)
+type TeamFilter struct {
+	// TeamIDs ограничивает выборку этими командами, nil - без ограничения
+	TeamIDs []uint32
+}
+
+func (uc *ProjectUseCases) GetAllTeams(ctx context.Context, page common.PageRequest) (*common.Page[*models.Team], error) {
+	ctx, span := startSpan(ctx, "GetAllTeams")
+	defer span.End()
 
-	teams, err := uc.repo.GetAllTeams()
-	var result []*models.Team
+	teamIDs, all, err := uc.policy.VisibleTeams(ctx, common.PermTeamRead)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get all teams: %w", err)
+		return nil, err
 	}
 
-	if claims.Role != adminRole {
-		for _, team := range teams {
-			for _, member := range team.Members {
-				if member.ID == claims.UserID {
-					result = append(result, team)
-				}
-			}
-		}
-		return result, nil
+	var filter TeamFilter
+	if !all {
+		filter.TeamIDs = nonNilTeamIDs(teamIDs)
+	}
+
+	teams, err := uc.repo.GetAllTeams(ctx, filter, page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all teams: %w", err)
 	}
 
-	uc.logMessage(ctx, "Fetching all teams")
 	return teams, nil
 }
 
@@ -295,12 +332,18 @@ func NewGetTeamByIDQuery(id uint32) *GetProjectByIDQuery {
 }
 
 func (uc *ProjectUseCases) GetTeamByID(ctx context.Context, query *GetProjectByIDQuery) (*models.Team, error) {
-	team, err := uc.repo.GetTeamById(query.id)
+	ctx, span := startSpan(ctx, "GetTeamByID")
+	defer span.End()
+
+	if err := uc.policy.AuthorizeTeam(ctx, common.PermTeamRead, query.id); err != nil {
+		return nil, err
+	}
+
+	team, err := uc.repo.GetTeamById(ctx, query.id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get team by id: %w", err)
 	}
 
-	uc.logMessage(ctx, fmt.Sprintf("Fetching team (id: %d)", query.id))
 	return team, nil
 }
 
@@ -320,10 +363,23 @@ func NewCreateTeamCommand(name string, members []Member, managerID uint32) *Crea
 }
 
 func (uc *ProjectUseCases) CreateTeam(ctx context.Context, cmd *CreateTeamCommand) (uint32, error) {
-	claims := ctx.Value(common.ContextKeyClaims).(*common.Claims)
+	ctx, span := startSpan(ctx, "CreateTeam")
+	defer span.End()
+
+	if err := uc.policy.Authorize(ctx, common.PermTeamWrite); err != nil {
+		return 0, err
+	}
+
+	if err := validateName("name", cmd.name, models.MaxTeamNameLength); err != nil {
+		return 0, err
+	}
+
+	if err := checkManagerListed(cmd.members, cmd.managerID); err != nil {
+		return 0, err
+	}
 
-	if claims.Role != adminRole {
-		return 0, common.ErrForbidden
+	if err := validateMemberRoles(cmd.members); err != nil {
+		return 0, err
 	}
 
 	team := &models.Team{
@@ -332,13 +388,35 @@ func (uc *ProjectUseCases) CreateTeam(ctx context.Context, cmd *CreateTeamComman
 		ManagerID: cmd.managerID,
 	}
 
-	id, err := uc.repo.CreateTeam(team)
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		for _, value := range cmd.members {
+			if err := uc.checkActiveUser(ctx, value.id); err != nil {
+				return err
+			}
+		}
+
+		id, err := uc.repo.CreateTeam(ctx, team)
+		if err != nil {
+			return fmt.Errorf("failed to create team: %w", err)
+		}
+
+		team.ID = id
+		return uc.audit.Record(ctx, auditEntityTeam, auditActionCreate, id, nil, team)
+	})
 	if err != nil {
-		return 0, fmt.Errorf("failed to create team: %w", err)
+		return 0, err
 	}
 
-	uc.logMessage(ctx, fmt.Sprintf("Creating new team (id: %d)", id))
-	return id, nil
+	return team.ID, nil
+}
+
+// checkManagerListed требует, чтобы менеджер был среди участников. Состав задаётся целиком
+// и при создании, и при обновлении, поэтому менеджер без членства не мог бы управлять командой.
+func checkManagerListed(members []Member, managerID uint32) error {
+	if !slices.ContainsFunc(members, func(member Member) bool { return member.id == managerID }) {
+		return fmt.Errorf("%w: manager %d must be listed among team members", common.ErrInvalidInput, managerID)
+	}
+	return nil
 }
 
 // Команда для обновления команды
@@ -347,47 +425,43 @@ type UpdateTeamCommand struct {
 	name      string
 	members   []Member
 	managerID uint32
+	version   uint32
 }
 
 func NewUpdateTeamCommand(
 	id uint32,
 	name string,
 	members []Member,
-	managerID uint32) *UpdateTeamCommand {
+	managerID uint32,
+	version uint32) *UpdateTeamCommand {
 	return &UpdateTeamCommand{
 		id:        id,
 		name:      name,
 		members:   members,
 		managerID: managerID,
+		version:   version,
 	}
 }
 
 func (uc *ProjectUseCases) UpdateTeam(ctx context.Context, cmd *UpdateTeamCommand) error {
-	claims := ctx.Value(common.ContextKeyClaims).(*common.Claims)
+	ctx, span := startSpan(ctx, "UpdateTeam")
+	defer span.End()
 
-	if claims.Role != adminRole {
-		return common.ErrForbidden
+	if err := uc.policy.AuthorizeTeam(ctx, common.PermTeamManage, cmd.id); err != nil {
+		return err
 	}
 
-	_, err := uc.repo.GetTeamById(cmd.id)
-
-	if err != nil {
-		if errors.Is(err, common.ErrNotFound) {
-			return fmt.Errorf("team with id %d is not found: %w", cmd.id, err)
-		}
-		return fmt.Errorf("failed to get user with id %d: %w", cmd.id, err)
+	if err := validateName("name", cmd.name, models.MaxTeamNameLength); err != nil {
+		return err
 	}
 
-	for _, value := range cmd.members {
-		member, err := uc.repo.GetMember(value.id)
-
-		if err != nil {
-			return fmt.Errorf("failed to get member by id %d: %w", value.id, err)
-		}
+	if err := checkManagerListed(cmd.members, cmd.managerID); err != nil {
+		return err
+	}
 
-		if member.Name != value.name {
-			return common.ErrForbidden
-		}
+	canAssignRoles, err := uc.policy.Can(ctx, common.PermRoleAssign)
+	if err != nil {
+		return err
 	}
 
 	team := &models.Team{
@@ -395,14 +469,54 @@ func (uc *ProjectUseCases) UpdateTeam(ctx context.Context, cmd *UpdateTeamComman
 		Name:      cmd.name,
 		Members:   mapMembersToModels(cmd.members),
 		ManagerID: cmd.managerID,
+		Version:   cmd.version,
 	}
 
-	if err := uc.repo.UpdateTeam(team); err != nil {
-		return fmt.Errorf("failed to update team: %w", err)
-	}
+	// Проверка участников, смена состава и запись в журнал выполняются атомарно
+	return uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		current, err := uc.repo.GetTeamById(ctx, cmd.id)
+		if err != nil {
+			if errors.Is(err, common.ErrNotFound) {
+				return fmt.Errorf("team with id %d is not found: %w", cmd.id, err)
+			}
+			return fmt.Errorf("failed to get team with id %d: %w", cmd.id, err)
+		}
 
-	uc.logMessage(ctx, fmt.Sprintf("Updating team (id: %d)", cmd.id))
-	return nil
+		if err := checkVersion(current.Version, cmd.version); err != nil {
+			return err
+		}
+
+		for _, value := range cmd.members {
+			member, err := uc.repo.GetMember(ctx, value.id, cmd.id)
+			if err != nil {
+				return fmt.Errorf("failed to get member by id %d: %w", value.id, err)
+			}
+
+			if member.Name != value.name {
+				return common.ErrForbidden
+			}
+
+			// Уже состоящий в команде деактивированный пользователь не мешает правке остального состава
+			if member.Deactivated && member.TeamID == 0 {
+				return fmt.Errorf("%w: user %d is deactivated", common.ErrInvalidInput, value.id)
+			}
+
+			// Проверяются только изменённые роли, чтобы не мешать правке команд с устаревшими значениями
+			if member.Role != value.role && !common.IsValidRole(value.role) {
+				return fmt.Errorf("%w: unknown role %q of member %d", common.ErrInvalidInput, value.role, value.id)
+			}
+
+			if member.Role != value.role && !canAssignRoles && !slices.Contains(common.TeamRoles, value.role) {
+				return common.ErrForbidden
+			}
+		}
+
+		if err := uc.repo.UpdateTeam(ctx, team); err != nil {
+			return fmt.Errorf("failed to update team: %w", err)
+		}
+
+		return uc.audit.Record(ctx, auditEntityTeam, auditActionUpdate, cmd.id, current, team)
+	})
 }
 
 // Команда для удаления команды
@@ -415,18 +529,28 @@ func NewDeleteTeamCommand(id uint32) *DeleteTeamCommand {
 }
 
 func (uc *ProjectUseCases) DeleteTeam(ctx context.Context, cmd *DeleteTeamCommand) error {
-	claims := ctx.Value(common.ContextKeyClaims).(*common.Claims)
+	ctx, span := startSpan(ctx, "DeleteTeam")
+	defer span.End()
 
-	if claims.Role != adminRole {
-		return common.ErrForbidden
+	if err := uc.policy.Authorize(ctx, common.PermTeamWrite); err != nil {
+		return err
 	}
 
-	if err := uc.repo.DeleteTeam(cmd.id); err != nil {
-		return fmt.Errorf("failed to delete team: %w", err)
-	}
+	return uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		team, err := uc.repo.GetTeamById(ctx, cmd.id)
+		if err != nil {
+			if errors.Is(err, common.ErrNotFound) {
+				return fmt.Errorf("team with id %d is not found: %w", cmd.id, err)
+			}
+			return fmt.Errorf("failed to get team with id %d: %w", cmd.id, err)
+		}
 
-	uc.logMessage(ctx, fmt.Sprintf("Deleting team (id: %d)", cmd.id))
-	return nil
+		if err := uc.repo.DeleteTeam(ctx, cmd.id); err != nil {
+			return fmt.Errorf("failed to delete team: %w", err)
+		}
+
+		return uc.audit.Record(ctx, auditEntityTeam, auditActionDelete, cmd.id, team, nil)
+	})
 }
 
 type MemberFilter struct {
@@ -434,13 +558,19 @@ type MemberFilter struct {
 	TeamID uint32
 }
 
-func (uc *ProjectUseCases) GetMembers(ctx context.Context, filter MemberFilter) ([]*models.Member, error) {
-	members, err := uc.repo.GetMembers(filter)
+func (uc *ProjectUseCases) GetMembers(ctx context.Context, filter MemberFilter, page common.PageRequest) (*common.Page[*models.Member], error) {
+	ctx, span := startSpan(ctx, "GetMembers")
+	defer span.End()
+
+	if err := uc.policy.Authorize(ctx, common.PermMemberRead); err != nil {
+		return nil, err
+	}
+
+	members, err := uc.repo.GetMembers(ctx, filter, page)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get members: %w", err)
 	}
 
-	uc.logMessage(ctx, "Fetching teams members")
 	return members, nil
 }
 
@@ -454,7 +584,14 @@ func NewGetTaskByIDQuery(id uint32) *GetTaskByIDQuery {
 }
 
 func (uc *ProjectUseCases) GetTaskByID(ctx context.Context, query *GetTaskByIDQuery) (*models.Task, error) {
-	task, err := uc.repo.GetTaskById(query.id)
+	ctx, span := startSpan(ctx, "GetTaskByID")
+	defer span.End()
+
+	if err := uc.policy.Authorize(ctx, common.PermTaskRead); err != nil {
+		return nil, err
+	}
+
+	task, err := uc.repo.GetTaskById(ctx, query.id)
 	if err != nil {
 		if errors.Is(err, common.ErrNotFound) {
 			return nil, fmt.Errorf("task with id %d is not found: %w", query.id, err)
@@ -462,97 +599,226 @@ func (uc *ProjectUseCases) GetTaskByID(ctx context.Context, query *GetTaskByIDQu
 		return nil, fmt.Errorf("failed to get task by id: %w", err)
 	}
 
-	uc.logMessage(ctx, fmt.Sprintf("Fetching task (id: %d)", query.id))
+	if err := uc.authorizeProject(ctx, common.PermTaskRead, task.ProjectID); err != nil {
+		return nil, hideForbidden(err, "task", query.id)
+	}
+
 	return task, nil
 }
 
 // Команда для создания задачи
 type CreateTaskCommand struct {
+	title       string
 	description string
+	status      models.TaskStatus
+	priority    uint32
+	dueDate     *time.Time
+	estimate    *uint32
 	employeeID  uint32
 	projectID   uint32
-	isCompleted bool
 }
 
-func NewCreateTaskCommand(description string, employeeID, projectID uint32, isCompleted bool) *CreateTaskCommand {
+func NewCreateTaskCommand(
+	title string,
+	description string,
+	status models.TaskStatus,
+	priority uint32,
+	dueDate *time.Time,
+	estimate *uint32,
+	employeeID uint32,
+	projectID uint32) *CreateTaskCommand {
 	return &CreateTaskCommand{
+		title:       title,
 		description: description,
+		status:      status,
+		priority:    priority,
+		dueDate:     dueDate,
+		estimate:    estimate,
 		employeeID:  employeeID,
 		projectID:   projectID,
-		isCompleted: isCompleted,
 	}
 }
 
 func (uc *ProjectUseCases) CreateTask(ctx context.Context, cmd *CreateTaskCommand) (uint32, error) {
+	ctx, span := startSpan(ctx, "CreateTask")
+	defer span.End()
+
 	claims := ctx.Value(common.ContextKeyClaims).(*common.Claims)
 
-	if claims.Role != adminRole {
-		return 0, common.ErrForbidden
+	if err := uc.authorizeProject(ctx, common.PermTaskWrite, cmd.projectID); err != nil {
+		return 0, err
+	}
+
+	if cmd.employeeID != claims.UserID {
+		if err := uc.authorizeProject(ctx, common.PermTaskAssign, cmd.projectID); err != nil {
+			return 0, err
+		}
+
+		if err := uc.checkActiveUser(ctx, cmd.employeeID); err != nil {
+			return 0, err
+		}
+	}
+
+	status := cmd.status
+	if status == "" {
+		status = uc.workflow.Initial()
+	}
+
+	if err := validateName("title", cmd.title, models.MaxTaskTitleLength); err != nil {
+		return 0, err
+	}
+
+	if err := uc.validateTask(status, cmd.priority, cmd.estimate); err != nil {
+		return 0, err
 	}
 
 	task := &models.Task{
+		Title:       cmd.title,
 		Description: cmd.description,
+		Status:      status,
+		Priority:    cmd.priority,
+		DueDate:     cmd.dueDate,
+		Estimate:    cmd.estimate,
 		EmployeeID:  cmd.employeeID,
 		ProjectID:   cmd.projectID,
-		IsCompleted: cmd.isCompleted,
 	}
 
-	id, err := uc.repo.CreateTask(task)
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		id, err := uc.repo.CreateTask(ctx, task)
+		if err != nil {
+			return fmt.Errorf("failed to create task: %w", err)
+		}
+
+		task.ID = id
+		return uc.audit.Record(ctx, auditEntityTask, auditActionCreate, id, nil, task)
+	})
 	if err != nil {
-		return 0, fmt.Errorf("failed to create task: %w", err)
+		return 0, err
 	}
 
-	uc.logMessage(ctx, fmt.Sprintf("Creating new task (id: %d)", id))
-	return id, nil
+	uc.metrics.TaskCreated()
+	if task.Status == models.TaskStatusDone {
+		uc.metrics.TaskCompleted()
+	}
+	return task.ID, nil
 }
 
 // Команда для обновления задачи
 type UpdateTaskCommand struct {
 	id          uint32
+	title       string
 	description string
+	status      models.TaskStatus
+	priority    uint32
+	dueDate     *time.Time
+	estimate    *uint32
 	employeeID  uint32
 	projectID   uint32
-	isCompleted bool
+	version     uint32
 }
 
-func NewUpdateTaskCommand(id uint32, description string, employeeID, projectID uint32, isCompleted bool) *UpdateTaskCommand {
+func NewUpdateTaskCommand(
+	id uint32,
+	title string,
+	description string,
+	status models.TaskStatus,
+	priority uint32,
+	dueDate *time.Time,
+	estimate *uint32,
+	employeeID uint32,
+	projectID uint32,
+	version uint32) *UpdateTaskCommand {
 	return &UpdateTaskCommand{
 		id:          id,
+		title:       title,
 		description: description,
+		status:      status,
+		priority:    priority,
+		dueDate:     dueDate,
+		estimate:    estimate,
 		employeeID:  employeeID,
 		projectID:   projectID,
-		isCompleted: isCompleted,
+		version:     version,
 	}
 }
 
 func (uc *ProjectUseCases) UpdateTask(ctx context.Context, cmd *UpdateTaskCommand) error {
-	claims := ctx.Value(common.ContextKeyClaims).(*common.Claims)
+	ctx, span := startSpan(ctx, "UpdateTask")
+	defer span.End()
 
-	if claims.Role != adminRole {
-		return common.ErrForbidden
+	if err := validateName("title", cmd.title, models.MaxTaskTitleLength); err != nil {
+		return err
 	}
 
-	_, err := uc.repo.GetTaskById(cmd.id)
-	if err != nil {
-		if errors.Is(err, common.ErrNotFound) {
-			return fmt.Errorf("task with id %d is not found: %w", cmd.id, err)
+	var completed bool
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		current, err := uc.repo.GetTaskById(ctx, cmd.id)
+		if err != nil {
+			if errors.Is(err, common.ErrNotFound) {
+				return fmt.Errorf("task with id %d is not found: %w", cmd.id, err)
+			}
+			return fmt.Errorf("failed to get task with id %d: %w", cmd.id, err)
 		}
-		return fmt.Errorf("failed to get task with id %d: %w", cmd.id, err)
-	}
 
-	task := &models.Task{
-		ID:          cmd.id,
-		Description: cmd.description,
-		EmployeeID:  cmd.employeeID,
-		ProjectID:   cmd.projectID,
-		IsCompleted: cmd.isCompleted,
-	}
+		if err := uc.authorizeProject(ctx, common.PermTaskWrite, current.ProjectID); err != nil {
+			return err
+		}
+
+		if err := checkVersion(current.Version, cmd.version); err != nil {
+			return err
+		}
+
+		if cmd.projectID != current.ProjectID {
+			if err := uc.authorizeProject(ctx, common.PermTaskWrite, cmd.projectID); err != nil {
+				return err
+			}
+		}
+
+		if cmd.employeeID != current.EmployeeID {
+			if err := uc.authorizeProject(ctx, common.PermTaskAssign, cmd.projectID); err != nil {
+				return err
+			}
+
+			if err := uc.checkActiveUser(ctx, cmd.employeeID); err != nil {
+				return err
+			}
+		}
+
+		if err := uc.validateTask(cmd.status, cmd.priority, cmd.estimate); err != nil {
+			return err
+		}
+
+		if !uc.workflow.CanTransition(current.Status, cmd.status) {
+			return fmt.Errorf("%w: %q -> %q", common.ErrInvalidTransition, current.Status, cmd.status)
+		}
+
+		task := &models.Task{
+			ID:          cmd.id,
+			Title:       cmd.title,
+			Description: cmd.description,
+			Status:      cmd.status,
+			Priority:    cmd.priority,
+			DueDate:     cmd.dueDate,
+			Estimate:    cmd.estimate,
+			EmployeeID:  cmd.employeeID,
+			ProjectID:   cmd.projectID,
+			Version:     cmd.version,
+		}
+
+		if err := uc.repo.UpdateTask(ctx, task); err != nil {
+			return fmt.Errorf("failed to update task: %w", err)
+		}
 
-	if err := uc.repo.UpdateTask(task); err != nil {
-		return fmt.Errorf("failed to update task: %w", err)
+		completed = current.Status != models.TaskStatusDone && task.Status == models.TaskStatusDone
+		return uc.audit.Record(ctx, auditEntityTask, auditActionUpdate, cmd.id, current, task)
+	})
+	if err != nil {
+		return err
 	}
 
-	uc.logMessage(ctx, fmt.Sprintf("Updating task (id: %d)", cmd.id))
+	if completed {
+		uc.metrics.TaskCompleted()
+	}
 	return nil
 }
 
@@ -566,18 +832,28 @@ func NewDeleteTaskCommand(id uint32) *DeleteTaskCommand {
 }
 
 func (uc *ProjectUseCases) DeleteTask(ctx context.Context, cmd *DeleteTaskCommand) error {
-	claims := ctx.Value(common.ContextKeyClaims).(*common.Claims)
+	ctx, span := startSpan(ctx, "DeleteTask")
+	defer span.End()
 
-	if claims.Role != adminRole {
-		return common.ErrForbidden
-	}
+	return uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		task, err := uc.repo.GetTaskById(ctx, cmd.id)
+		if err != nil {
+			if errors.Is(err, common.ErrNotFound) {
+				return fmt.Errorf("task with id %d is not found: %w", cmd.id, err)
+			}
+			return fmt.Errorf("failed to get task with id %d: %w", cmd.id, err)
+		}
 
-	if err := uc.repo.DeleteTask(cmd.id); err != nil {
-		return fmt.Errorf("failed to delete task: %w", err)
-	}
+		if err := uc.authorizeProject(ctx, common.PermTaskWrite, task.ProjectID); err != nil {
+			return err
+		}
 
-	uc.logMessage(ctx, fmt.Sprintf("Deleting task (id: %d)", cmd.id))
-	return nil
+		if err := uc.repo.DeleteTask(ctx, cmd.id); err != nil {
+			return fmt.Errorf("failed to delete task: %w", err)
+		}
+
+		return uc.audit.Record(ctx, auditEntityTask, auditActionDelete, cmd.id, task, nil)
+	})
 }
 
 // Запрос для получения задач сотрудника
@@ -589,13 +865,26 @@ func NewGetTasksByEmployeeIDQuery(employeeID uint32) *GetTasksByEmployeeIDQuery
 	return &GetTasksByEmployeeIDQuery{employeeID: employeeID}
 }
 
-func (uc *ProjectUseCases) GetTasksByEmployeeID(ctx context.Context, query *GetTasksByEmployeeIDQuery) ([]*models.Task, error) {
-	tasks, err := uc.repo.GetTasksByEmployeeID(query.employeeID)
+// GetTasksByEmployeeID возвращает страницу задач сотрудника из доступных пользователю команд
+func (uc *ProjectUseCases) GetTasksByEmployeeID(ctx context.Context, query *GetTasksByEmployeeIDQuery, page common.PageRequest) (*common.Page[*models.Task], error) {
+	ctx, span := startSpan(ctx, "GetTasksByEmployeeID")
+	defer span.End()
+
+	teamIDs, all, err := uc.policy.VisibleTeams(ctx, common.PermTaskRead)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := TaskFilter{EmployeeID: query.employeeID}
+	if !all {
+		filter.TeamIDs = nonNilTeamIDs(teamIDs)
+	}
+
+	tasks, err := uc.repo.GetTasks(ctx, filter, page)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tasks for employee id %d: %w", query.employeeID, err)
 	}
 
-	uc.logMessage(ctx, fmt.Sprintf("Fetching tasks by employee id (id: %d)", query.employeeID))
 	return tasks, nil
 }
 
@@ -603,9 +892,132 @@ type TaskFilter struct {
 	EmployeeID  uint32
 	ProjectID   uint32
 	IsCompleted *bool
+	Status      models.TaskStatus
+	Priority    *uint32
+	DueAfter    time.Time
+	DueBefore   time.Time
+	// TeamIDs ограничивает выборку задачами проектов этих команд, nil - без ограничения
+	TeamIDs []uint32
+}
+
+func (uc *ProjectUseCases) GetTasks(ctx context.Context, filter TaskFilter, page common.PageRequest) (*common.Page[*models.Task], error) {
+	ctx, span := startSpan(ctx, "GetTasks")
+	defer span.End()
+
+	teamIDs, all, err := uc.policy.VisibleTeams(ctx, common.PermTaskRead)
+	if err != nil {
+		return nil, err
+	}
+
+	if !all {
+		filter.TeamIDs = nonNilTeamIDs(teamIDs)
+	}
+
+	return uc.repo.GetTasks(ctx, filter, page)
+}
+
+func (uc *ProjectUseCases) validateTask(status models.TaskStatus, priority uint32, estimate *uint32) error {
+	if !uc.workflow.IsValid(status) {
+		return fmt.Errorf("%w: unknown task status %q", common.ErrInvalidInput, status)
+	}
+
+	if priority > models.MaxTaskPriority {
+		return fmt.Errorf("%w: task priority must be between 0 and %d", common.ErrInvalidInput, models.MaxTaskPriority)
+	}
+
+	if estimate != nil && *estimate > models.MaxTaskEstimate {
+		return fmt.Errorf("%w: task estimate must be between 0 and %d", common.ErrInvalidInput, models.MaxTaskEstimate)
+	}
+
+	return nil
+}
+
+// validateProject проверяет доменные правила проекта, в том числе для PUT и PATCH
+func validateProject(project *models.Project) error {
+	var errs validation.Errors
+
+	checkName(&errs, "name", project.Name, models.MaxProjectNameLength)
+
+	if utf8.RuneCountInString(project.Status) > models.MaxProjectStatusLength {
+		errs.Add("status", fmt.Sprintf("must be at most %d characters long", models.MaxProjectStatusLength))
+	}
+
+	if project.Budget < 0 {
+		errs.Add("budget", "must be greater than or equal to 0")
+	}
+
+	if !project.PlannedEndDate.After(project.StartDate) {
+		errs.Add("plannedEndDate", "must be after startDate")
+	}
+
+	if !project.ActualEndDate.IsZero() && project.ActualEndDate.Before(project.StartDate) {
+		errs.Add("actualEndDate", "must not be before startDate")
+	}
+
+	return errs.Err()
+}
+
+// validateName проверяет обязательное имя или заголовок. Для PUT то же проверяет DTO,
+// для PATCH объединённый результат проверяется только здесь.
+func validateName(field, value string, maxLength int) error {
+	var errs validation.Errors
+	checkName(&errs, field, value, maxLength)
+	return errs.Err()
+}
+
+func checkName(errs *validation.Errors, field, value string, maxLength int) {
+	switch {
+	case strings.TrimSpace(value) == "":
+		errs.Add(field, "is required")
+	case utf8.RuneCountInString(value) > maxLength:
+		errs.Add(field, fmt.Sprintf("must be at most %d characters long", maxLength))
+	}
+}
+
+func validateMemberRoles(members []Member) error {
+	var errs validation.Errors
+
+	for i, member := range members {
+		if !common.IsValidRole(member.role) {
+			errs.Add(fmt.Sprintf("members[%d].role", i), "must be one of: "+strings.Join(common.Roles, ", "))
+		}
+	}
+
+	return errs.Err()
+}
+
+// authorizeProject проверяет право permission в команде, которой принадлежит проект
+// hideForbidden заменяет отказ в доступе к сущности чужой команды на ErrNotFound,
+// чтобы ответ не выдавал её существование
+func hideForbidden(err error, entity string, id uint32) error {
+	if errors.Is(err, common.ErrForbidden) {
+		return fmt.Errorf("%s with id %d is not found: %w", entity, id, common.ErrNotFound)
+	}
+	return err
+}
+
+// checkActiveUser не даёт включить в команду или назначить на задачу несуществующего
+// или деактивированного пользователя
+func (uc *ProjectUseCases) checkActiveUser(ctx context.Context, userID uint32) error {
+	member, err := uc.repo.GetMember(ctx, userID, 0)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return fmt.Errorf("%w: user %d does not exist", common.ErrInvalidInput, userID)
+		}
+		return fmt.Errorf("failed to get user by id %d: %w", userID, err)
+	}
+
+	if member.Deactivated {
+		return fmt.Errorf("%w: user %d is deactivated", common.ErrInvalidInput, userID)
+	}
+	return nil
 }
 
-func (uc *ProjectUseCases) GetTasks(ctx context.Context, filter TaskFilter) ([]*models.Task, error) {
-	uc.logMessage(ctx, "Fetching tasks")
-	return uc.repo.GetTasks(filter)
+func (uc *ProjectUseCases) authorizeProject(ctx context.Context, permission common.Permission, projectID uint32) error {
+	project, err := uc.repo.GetProjectById(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to get project with id %d: %w", projectID, err)
+	}
+
+	return uc.policy.AuthorizeTeam(ctx, permission, projectTeamID(project))
 }
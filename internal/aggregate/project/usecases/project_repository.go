@@ -1,28 +1,46 @@
 package usecases
 
-import "github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/models"
+import (
+	"context"
+	"time"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/models"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+)
 
 type ProjectRepository interface {
-	CreateProject(project *models.Project) (uint32, error)
-	UpdateProject(project *models.Project) error
-	DeleteProject(projectID uint32) error
-	GetAllProjects() ([]*models.Project, error)
-	GetProjectById(projectID uint32) (*models.Project, error)
-
-	CreateTeam(team *models.Team) (uint32, error)
-	UpdateTeam(team *models.Team) error
-	DeleteTeam(teamID uint32) error
-	GetAllTeams() ([]*models.Team, error)
-	GetTeamById(teamID uint32) (*models.Team, error)
-	GetTeamIdByUserID(userID uint32) (uint32, error)
-
-	GetMember(userID uint32) (*models.Member, error)
-	GetMembers(filter MemberFilter) ([]*models.Member, error)
-
-	CreateTask(task *models.Task) (uint32, error)
-	UpdateTask(task *models.Task) error
-	DeleteTask(taskID uint32) error
-	GetTaskById(taskID uint32) (*models.Task, error)
-	GetTasksByEmployeeID(employeeID uint32) ([]*models.Task, error)
-	GetTasks(filter TaskFilter) ([]*models.Task, error)
+	CreateProject(ctx context.Context, project *models.Project) (uint32, error)
+	UpdateProject(ctx context.Context, project *models.Project) error
+	DeleteProject(ctx context.Context, projectID uint32) error
+	GetAllProjects(ctx context.Context, filter ProjectFilter, page common.PageRequest) (*common.Page[*models.Project], error)
+	GetProjectById(ctx context.Context, projectID uint32) (*models.Project, error)
+	RestoreProject(ctx context.Context, projectID uint32) error
+
+	CreateTeam(ctx context.Context, team *models.Team) (uint32, error)
+	UpdateTeam(ctx context.Context, team *models.Team) error
+	DeleteTeam(ctx context.Context, teamID uint32) error
+	GetAllTeams(ctx context.Context, filter TeamFilter, page common.PageRequest) (*common.Page[*models.Team], error)
+	GetTeamById(ctx context.Context, teamID uint32) (*models.Team, error)
+	RestoreTeam(ctx context.Context, teamID uint32) error
+
+	GetMember(ctx context.Context, userID uint32, teamID uint32) (*models.Member, error)
+	GetMembers(ctx context.Context, filter MemberFilter, page common.PageRequest) (*common.Page[*models.Member], error)
+
+	CreateTask(ctx context.Context, task *models.Task) (uint32, error)
+	UpdateTask(ctx context.Context, task *models.Task) error
+	DeleteTask(ctx context.Context, taskID uint32) error
+	GetTaskById(ctx context.Context, taskID uint32) (*models.Task, error)
+	RestoreTask(ctx context.Context, taskID uint32) error
+	GetTasks(ctx context.Context, filter TaskFilter, page common.PageRequest) (*common.Page[*models.Task], error)
+
+	CreateComment(ctx context.Context, comment *models.Comment) (uint32, error)
+	UpdateComment(ctx context.Context, comment *models.Comment, editorID uint32) error
+	DeleteComment(ctx context.Context, commentID uint32) error
+	GetCommentById(ctx context.Context, commentID uint32) (*models.Comment, error)
+	GetCommentsByTaskID(ctx context.Context, taskID uint32) ([]*models.Comment, error)
+	GetCommentEdits(ctx context.Context, commentID uint32) ([]*models.CommentEdit, error)
+
+	Search(ctx context.Context, filter SearchFilter, limit int) ([]*models.SearchResult, error)
+
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
 }
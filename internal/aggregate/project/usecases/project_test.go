@@ -0,0 +1,146 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateTeamAssignsNewMemberAsManager(t *testing.T) {
+	uc := newTestProjects()
+	seedTeam(uc)
+
+	// Участник добавляется и назначается менеджером одним запросом
+	err := uc.UpdateTeam(context.Background(), NewUpdateTeamCommand(10, "Core", []Member{
+		*NewMember(1, "ann", common.RoleMember),
+		*NewMember(3, "eve", common.RoleManager),
+	}, 3, 5))
+	require.NoError(t, err)
+
+	stored := uc.repo.teams[10]
+	assert.Equal(t, uint32(3), stored.ManagerID)
+	assert.Len(t, stored.Members, 2)
+	assert.Equal(t, []string{"team.update"}, uc.audit.actions)
+}
+
+func TestUpdateTeamRequiresManagerAmongMembers(t *testing.T) {
+	uc := newTestProjects()
+	seedTeam(uc)
+
+	err := uc.UpdateTeam(context.Background(), NewUpdateTeamCommand(10, "Core", []Member{
+		*NewMember(2, "bob", common.RoleMember),
+	}, 1, 0))
+	assert.ErrorIs(t, err, common.ErrInvalidInput)
+
+	// Состав не изменился, менеджер не потерял членство
+	assert.Len(t, uc.repo.teams[10].Members, 2)
+	assert.Empty(t, uc.audit.actions)
+
+	// PATCH без members передаёт текущий состав, в котором нового менеджера нет
+	err = uc.PatchTeam(context.Background(), NewPatchTeamCommand(10, TeamPatch{ManagerID: SetField(uint32(3))}, 0))
+	assert.ErrorIs(t, err, common.ErrInvalidInput)
+	assert.Equal(t, uint32(1), uc.repo.teams[10].ManagerID)
+}
+
+func TestCreateTeam(t *testing.T) {
+	uc := newTestProjects()
+	uc.repo.users = map[uint32]string{1: "ann", 2: "bob"}
+
+	id, err := uc.CreateTeam(context.Background(), NewCreateTeamCommand("Core", []Member{
+		*NewMember(1, "ann", common.RoleManager),
+		*NewMember(2, "bob", common.RoleMember),
+	}, 1))
+	require.NoError(t, err)
+
+	stored := uc.repo.teams[id]
+	assert.Equal(t, uint32(1), stored.ManagerID)
+	assert.Len(t, stored.Members, 2)
+	assert.Equal(t, []string{"team.create"}, uc.audit.actions)
+}
+
+func TestCreateTeamRequiresManagerAmongMembers(t *testing.T) {
+	uc := newTestProjects()
+	uc.repo.users = map[uint32]string{1: "ann", 2: "bob"}
+
+	// Создание подчиняется тому же правилу, что и обновление: менеджер не добавляется неявно
+	_, err := uc.CreateTeam(context.Background(), NewCreateTeamCommand("Core", []Member{
+		*NewMember(2, "bob", common.RoleMember),
+	}, 1))
+	assert.ErrorIs(t, err, common.ErrInvalidInput)
+	assert.Empty(t, uc.repo.teams)
+	assert.Empty(t, uc.audit.actions)
+}
+
+func TestTeamsRejectDeactivatedUsers(t *testing.T) {
+	uc := newTestProjects()
+	seedTeam(uc)
+	uc.repo.deactivated = []uint32{2, 3}
+
+	_, err := uc.CreateTeam(context.Background(), NewCreateTeamCommand("Billing", []Member{
+		*NewMember(1, "ann", common.RoleManager),
+		*NewMember(3, "eve", common.RoleMember),
+	}, 1))
+	assert.ErrorIs(t, err, common.ErrInvalidInput)
+
+	err = uc.UpdateTeam(context.Background(), NewUpdateTeamCommand(10, "Core", []Member{
+		*NewMember(1, "ann", common.RoleManager),
+		*NewMember(2, "bob", common.RoleMember),
+		*NewMember(3, "eve", common.RoleMember),
+	}, 1, 5))
+	assert.ErrorIs(t, err, common.ErrInvalidInput)
+	assert.Empty(t, uc.audit.actions)
+
+	// Деактивированный участник, уже состоящий в команде, не мешает её переименовать
+	err = uc.UpdateTeam(context.Background(), NewUpdateTeamCommand(10, "Platform", []Member{
+		*NewMember(1, "ann", common.RoleManager),
+		*NewMember(2, "bob", common.RoleMember),
+	}, 1, 5))
+	require.NoError(t, err)
+	assert.Equal(t, "Platform", uc.repo.teams[10].Name)
+}
+
+func TestCreateTaskRejectsDeactivatedEmployee(t *testing.T) {
+	uc := newTestProjects()
+	seedProject(uc)
+	seedTeam(uc)
+	uc.repo.deactivated = []uint32{2}
+
+	_, err := uc.CreateTask(contextWithClaims(1, common.RoleManager),
+		NewCreateTaskCommand("Send invoices", "", "", 1, nil, nil, 2, 1))
+	assert.ErrorIs(t, err, common.ErrInvalidInput)
+	assert.Empty(t, uc.repo.tasks)
+}
+
+func TestGetProjectByIDHidesOtherTeams(t *testing.T) {
+	uc := newTestProjects()
+	seedProject(uc)
+	uc.policy.teams = []uint32{20}
+
+	// Проект чужой команды и несуществующий проект неразличимы
+	_, err := uc.GetProjectByID(context.Background(), NewGetProjectByIDQuery(1))
+	assert.ErrorIs(t, err, common.ErrNotFound)
+	_, err = uc.GetProjectByID(context.Background(), NewGetProjectByIDQuery(99))
+	assert.ErrorIs(t, err, common.ErrNotFound)
+
+	// Без права чтения ответ не зависит от существования проекта
+	uc.policy.denied = []common.Permission{common.PermProjectRead}
+	_, err = uc.GetProjectByID(context.Background(), NewGetProjectByIDQuery(1))
+	assert.ErrorIs(t, err, common.ErrForbidden)
+	_, err = uc.GetProjectByID(context.Background(), NewGetProjectByIDQuery(99))
+	assert.ErrorIs(t, err, common.ErrForbidden)
+}
+
+func TestGetTaskByIDHidesOtherTeams(t *testing.T) {
+	uc := newTestProjects()
+	seedProject(uc)
+	seedTask(uc)
+	uc.policy.teams = []uint32{20}
+
+	_, err := uc.GetTaskByID(context.Background(), NewGetTaskByIDQuery(7))
+	assert.ErrorIs(t, err, common.ErrNotFound)
+	_, err = uc.GetTaskByID(context.Background(), NewGetTaskByIDQuery(99))
+	assert.ErrorIs(t, err, common.ErrNotFound)
+}
@@ -0,0 +1,66 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/models"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+)
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+type SearchFilter struct {
+	Query string
+	// TeamIDs ограничивает поиск проектами этих команд, nil - без ограничения
+	TeamIDs []uint32
+}
+
+// Запрос для полнотекстового поиска по проектам, задачам и комментариям
+type SearchQuery struct {
+	text  string
+	limit int
+}
+
+func NewSearchQuery(text string, limit int) *SearchQuery {
+	return &SearchQuery{
+		text:  text,
+		limit: limit,
+	}
+}
+
+func (uc *ProjectUseCases) Search(ctx context.Context, query *SearchQuery) ([]*models.SearchResult, error) {
+	ctx, span := startSpan(ctx, "Search")
+	defer span.End()
+
+	text := strings.TrimSpace(query.text)
+	if text == "" {
+		return nil, fmt.Errorf("%w: search query is empty", common.ErrInvalidInput)
+	}
+
+	if query.limit < 1 || query.limit > MaxSearchLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", common.ErrInvalidInput, MaxSearchLimit)
+	}
+
+	// Пользователь видит только совпадения из проектов своих команд, как в GetAllProjects
+	teamIDs, all, err := uc.policy.VisibleTeams(ctx, common.PermProjectRead)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := SearchFilter{Query: text}
+	if !all {
+		filter.TeamIDs = nonNilTeamIDs(teamIDs)
+	}
+
+	results, err := uc.repo.Search(ctx, filter, query.limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,15 @@
+package usecases
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/usecases"
+
+// startSpan открывает span сценария, внутри которого окажутся span'ы запросов к базе
+func startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, "ProjectUseCases."+name)
+}
@@ -0,0 +1,126 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/logger"
+)
+
+// Команда для восстановления удалённого проекта
+type RestoreProjectCommand struct {
+	id uint32
+}
+
+func NewRestoreProjectCommand(id uint32) *RestoreProjectCommand {
+	return &RestoreProjectCommand{id: id}
+}
+
+func (uc *ProjectUseCases) RestoreProject(ctx context.Context, cmd *RestoreProjectCommand) error {
+	ctx, span := startSpan(ctx, "RestoreProject")
+	defer span.End()
+
+	if err := uc.policy.Authorize(ctx, common.PermTrashRestore); err != nil {
+		return err
+	}
+
+	return uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.repo.RestoreProject(ctx, cmd.id); err != nil {
+			return fmt.Errorf("failed to restore project: %w", err)
+		}
+
+		project, err := uc.repo.GetProjectById(ctx, cmd.id)
+		if err != nil {
+			return fmt.Errorf("failed to get restored project with id %d: %w", cmd.id, err)
+		}
+
+		return uc.audit.Record(ctx, auditEntityProject, auditActionRestore, cmd.id, nil, project)
+	})
+}
+
+// Команда для восстановления удалённой команды
+type RestoreTeamCommand struct {
+	id uint32
+}
+
+func NewRestoreTeamCommand(id uint32) *RestoreTeamCommand {
+	return &RestoreTeamCommand{id: id}
+}
+
+func (uc *ProjectUseCases) RestoreTeam(ctx context.Context, cmd *RestoreTeamCommand) error {
+	ctx, span := startSpan(ctx, "RestoreTeam")
+	defer span.End()
+
+	if err := uc.policy.Authorize(ctx, common.PermTrashRestore); err != nil {
+		return err
+	}
+
+	return uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.repo.RestoreTeam(ctx, cmd.id); err != nil {
+			return fmt.Errorf("failed to restore team: %w", err)
+		}
+
+		team, err := uc.repo.GetTeamById(ctx, cmd.id)
+		if err != nil {
+			return fmt.Errorf("failed to get restored team with id %d: %w", cmd.id, err)
+		}
+
+		return uc.audit.Record(ctx, auditEntityTeam, auditActionRestore, cmd.id, nil, team)
+	})
+}
+
+// Команда для восстановления удалённой задачи
+type RestoreTaskCommand struct {
+	id uint32
+}
+
+func NewRestoreTaskCommand(id uint32) *RestoreTaskCommand {
+	return &RestoreTaskCommand{id: id}
+}
+
+func (uc *ProjectUseCases) RestoreTask(ctx context.Context, cmd *RestoreTaskCommand) error {
+	ctx, span := startSpan(ctx, "RestoreTask")
+	defer span.End()
+
+	if err := uc.policy.Authorize(ctx, common.PermTrashRestore); err != nil {
+		return err
+	}
+
+	return uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.repo.RestoreTask(ctx, cmd.id); err != nil {
+			return fmt.Errorf("failed to restore task: %w", err)
+		}
+
+		task, err := uc.repo.GetTaskById(ctx, cmd.id)
+		if err != nil {
+			return fmt.Errorf("failed to get restored task with id %d: %w", cmd.id, err)
+		}
+
+		return uc.audit.Record(ctx, auditEntityTask, auditActionRestore, cmd.id, nil, task)
+	})
+}
+
+// RunPurgeJob раз в interval окончательно удаляет записи, находящиеся в корзине дольше retention.
+// Работает до отмены ctx.
+func (uc *ProjectUseCases) RunPurgeJob(ctx context.Context, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := uc.repo.PurgeDeleted(ctx, time.Now().Add(-retention))
+		if err != nil {
+			logger.FromContext(ctx).Error("failed to purge deleted records", slog.Any("error", err))
+		} else if purged > 0 {
+			logger.FromContext(ctx).Info("purged deleted records", slog.Int64("count", purged), slog.Duration("retention", retention))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -1,6 +1,11 @@
 package usecases
 
-import "github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/models"
+import (
+	"fmt"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/project/models"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+)
 
 func mapMembersToModels(members []Member) []models.Member {
 	memberModels := make([]models.Member, len(members))
@@ -11,3 +16,26 @@ func mapMembersToModels(members []Member) []models.Member {
 
 	return memberModels
 }
+
+func projectTeamID(project *models.Project) uint32 {
+	if project.Team == nil {
+		return 0
+	}
+	return project.Team.ID
+}
+
+// nonNilTeamIDs отличает "нет доступных команд" от "без ограничения"
+func nonNilTeamIDs(teamIDs []uint32) []uint32 {
+	if teamIDs == nil {
+		return []uint32{}
+	}
+	return teamIDs
+}
+
+// checkVersion сравнивает текущую версию с ожидаемой клиентом, 0 - без проверки
+func checkVersion(current, expected uint32) error {
+	if expected != 0 && current != expected {
+		return fmt.Errorf("%w: current version is %d, expected %d", common.ErrPreconditionFailed, current, expected)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/database"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+)
+
+const invitationColumns = `id, team_id, email, role, token_hash, invited_by, status, expires_at, created_at, responded_at`
+
+// InvitationRepository хранит приглашения в команды
+type InvitationRepository struct {
+	db *database.DB
+}
+
+func NewInvitationRepository(db *sql.DB) *InvitationRepository {
+	return &InvitationRepository{db: database.New(db)}
+}
+
+// Create сохраняет новое приглашение со статусом pending.
+func (r *InvitationRepository) Create(ctx context.Context, invitation *models.Invitation) (uint32, error) {
+	query := `INSERT INTO invitations (team_id, email, role, token_hash, invited_by, expires_at, created_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+
+	var invitedBy sql.NullInt64
+	if invitation.InvitedBy != 0 {
+		invitedBy = sql.NullInt64{Int64: int64(invitation.InvitedBy), Valid: true}
+	}
+
+	var invitationID uint32
+	err := r.db.QueryRowContext(ctx, query,
+		invitation.TeamID,
+		invitation.Email,
+		invitation.Role,
+		invitation.TokenHash,
+		invitedBy,
+		invitation.ExpiresAt,
+		invitation.CreatedAt,
+	).Scan(&invitationID)
+	if err != nil {
+		return 0, err
+	}
+
+	return invitationID, nil
+}
+
+// RevokePending отзывает действующее приглашение на email в команду teamID.
+func (r *InvitationRepository) RevokePending(ctx context.Context, teamID uint32, email string) error {
+	query := `UPDATE invitations SET status = 'revoked', responded_at = NOW()
+              WHERE team_id = $1 AND LOWER(email) = LOWER($2) AND status = 'pending'`
+
+	_, err := r.db.ExecContext(ctx, query, teamID, email)
+	return err
+}
+
+// Respond переводит приглашение в статус status и возвращает его. Если приглашение не найдено,
+// на него уже ответили или оно истекло к моменту now, возвращает common.ErrNotFound.
+func (r *InvitationRepository) Respond(ctx context.Context, tokenHash []byte, status models.InvitationStatus, now time.Time) (*models.Invitation, error) {
+	query := `UPDATE invitations SET status = $2, responded_at = $3
+              WHERE token_hash = $1 AND status = 'pending' AND expires_at > $3
+              RETURNING ` + invitationColumns
+
+	return scanInvitation(r.db.QueryRowContext(ctx, query, tokenHash, status, now))
+}
+
+// GetByTeam возвращает приглашения в команду, начиная с последних.
+func (r *InvitationRepository) GetByTeam(ctx context.Context, teamID uint32) ([]*models.Invitation, error) {
+	query := `SELECT ` + invitationColumns + ` FROM invitations WHERE team_id = $1 ORDER BY id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query invitations: %w", err)
+	}
+	defer rows.Close()
+
+	var invitations []*models.Invitation
+	for rows.Next() {
+		invitation, err := scanInvitation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan invitation row: %w", err)
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over invitation rows: %w", err)
+	}
+
+	return invitations, nil
+}
+
+// GetTeamName возвращает название команды, не находящейся в корзине.
+func (r *InvitationRepository) GetTeamName(ctx context.Context, teamID uint32) (string, error) {
+	query := `SELECT name FROM teams WHERE id = $1 AND deleted_at IS NULL`
+
+	var name string
+	if err := r.db.QueryRowContext(ctx, query, teamID).Scan(&name); err != nil {
+		if err == sql.ErrNoRows {
+			return "", common.ErrNotFound
+		}
+		return "", err
+	}
+
+	return name, nil
+}
+
+// scanInvitation читает приглашение в порядке колонок invitationColumns
+func scanInvitation(row interface{ Scan(dest ...any) error }) (*models.Invitation, error) {
+	invitation := &models.Invitation{}
+	var (
+		invitedBy   sql.NullInt64
+		respondedAt sql.NullTime
+	)
+
+	err := row.Scan(
+		&invitation.ID,
+		&invitation.TeamID,
+		&invitation.Email,
+		&invitation.Role,
+		&invitation.TokenHash,
+		&invitedBy,
+		&invitation.Status,
+		&invitation.ExpiresAt,
+		&invitation.CreatedAt,
+		&respondedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+
+	invitation.InvitedBy = uint32(invitedBy.Int64)
+	invitation.RespondedAt = respondedAt.Time
+	return invitation, nil
+}
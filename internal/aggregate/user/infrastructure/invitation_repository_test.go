@@ -0,0 +1,92 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRespondInvitation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewInvitationRepository(db)
+
+	tokenHash := []byte("hash")
+	now := time.Now()
+	createdAt := now.Add(-time.Hour)
+
+	rows := sqlmock.NewRows([]string{"id", "team_id", "email", "role", "token_hash", "invited_by", "status", "expires_at", "created_at", "responded_at"}).
+		AddRow(4, 2, "bob@example.com", "member", tokenHash, nil, "accepted", now.Add(time.Hour), createdAt, now)
+
+	mock.ExpectQuery(`UPDATE invitations SET status = \$2, responded_at = \$3 WHERE token_hash = \$1 AND status = 'pending' AND expires_at > \$3 RETURNING id, team_id`).
+		WithArgs(tokenHash, models.InvitationAccepted, now).
+		WillReturnRows(rows)
+
+	invitation, err := repo.Respond(context.Background(), tokenHash, models.InvitationAccepted, now)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2), invitation.TeamID)
+	assert.Equal(t, models.InvitationAccepted, invitation.Status)
+	assert.Zero(t, invitation.InvitedBy)
+	assert.Equal(t, now, invitation.RespondedAt)
+
+	// На приглашение отвечают один раз
+	mock.ExpectQuery(`UPDATE invitations`).
+		WithArgs(tokenHash, models.InvitationDeclined, now).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.Respond(context.Background(), tokenHash, models.InvitationDeclined, now)
+	assert.ErrorIs(t, err, common.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateInvitation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewInvitationRepository(db)
+
+	now := time.Now()
+	invitation := &models.Invitation{
+		TeamID:    2,
+		Email:     "bob@example.com",
+		Role:      "viewer",
+		TokenHash: []byte("hash"),
+		InvitedBy: 1,
+		ExpiresAt: now.Add(time.Hour),
+		CreatedAt: now,
+	}
+
+	mock.ExpectQuery(`INSERT INTO invitations \(team_id, email, role, token_hash, invited_by, expires_at, created_at\)`).
+		WithArgs(uint32(2), "bob@example.com", "viewer", []byte("hash"), sql.NullInt64{Int64: 1, Valid: true}, invitation.ExpiresAt, now).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+
+	id, err := repo.Create(context.Background(), invitation)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(5), id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTeamNameSkipsDeletedTeams(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewInvitationRepository(db)
+
+	mock.ExpectQuery(`SELECT name FROM teams WHERE id = \$1 AND deleted_at IS NULL`).
+		WithArgs(uint32(9)).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetTeamName(context.Background(), 9)
+	assert.ErrorIs(t, err, common.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -10,20 +10,23 @@ import (
 
 type JWTManager struct {
 	secretKey string
+	ttl       time.Duration
 }
 
-func NewJWTManager(secretKey string) *JWTManager {
+func NewJWTManager(secretKey string, ttl time.Duration) *JWTManager {
 	return &JWTManager{
 		secretKey: secretKey,
+		ttl:       ttl,
 	}
 }
 
-func (m *JWTManager) GenerateToken(userID uint32, role string) (string, error) {
-	expirationTime := time.Now().Add(time.Hour * 1)
+func (m *JWTManager) GenerateToken(userID uint32, role string, sessionID string) (string, error) {
+	expirationTime := time.Now().Add(m.ttl)
 
 	claims := &common.Claims{
-		UserID: userID,
-		Role:   role,
+		UserID:    userID,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 		},
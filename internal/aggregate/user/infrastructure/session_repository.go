@@ -0,0 +1,117 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/database"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+)
+
+type SessionRepository struct {
+	db *database.DB
+}
+
+func NewSessionRepository(db *sql.DB) *SessionRepository {
+	return &SessionRepository{db: database.New(db)}
+}
+
+// Create сохраняет новую сессию.
+func (r *SessionRepository) Create(ctx context.Context, session *models.Session) (uint32, error) {
+	query := `INSERT INTO sessions (user_id, family_id, token_hash, expires_at)
+              VALUES ($1, $2, $3, $4) RETURNING id`
+
+	var sessionID uint32
+	err := r.db.QueryRowContext(ctx, query, session.UserID, session.FamilyID, session.TokenHash, session.ExpiresAt).Scan(&sessionID)
+	if err != nil {
+		return 0, err
+	}
+
+	return sessionID, nil
+}
+
+// GetByTokenHash получает сессию по хэшу refresh-токена.
+func (r *SessionRepository) GetByTokenHash(ctx context.Context, tokenHash []byte) (*models.Session, error) {
+	query := `SELECT id, user_id, family_id, token_hash, expires_at, created_at, rotated_at, revoked_at
+              FROM sessions WHERE token_hash = $1`
+
+	session := &models.Session{}
+	var rotatedAt, revokedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(
+		&session.ID,
+		&session.UserID,
+		&session.FamilyID,
+		&session.TokenHash,
+		&session.ExpiresAt,
+		&session.CreatedAt,
+		&rotatedAt,
+		&revokedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+
+	session.RotatedAt = rotatedAt.Time
+	session.RevokedAt = revokedAt.Time
+
+	return session, nil
+}
+
+// MarkRotated помечает сессию как использованную. Если сессия уже была
+// использована или отозвана, возвращает common.ErrNotFound.
+func (r *SessionRepository) MarkRotated(ctx context.Context, id uint32) error {
+	query := `UPDATE sessions SET rotated_at = NOW()
+              WHERE id = $1 AND rotated_at IS NULL AND revoked_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return common.ErrNotFound
+	}
+
+	return nil
+}
+
+// RevokeFamily отзывает все сессии семейства.
+func (r *SessionRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	query := `UPDATE sessions SET revoked_at = NOW()
+              WHERE family_id = $1 AND revoked_at IS NULL`
+
+	_, err := r.db.ExecContext(ctx, query, familyID)
+	return err
+}
+
+// RevokeAllForUser отзывает все сессии пользователя.
+func (r *SessionRepository) RevokeAllForUser(ctx context.Context, userID uint32) error {
+	query := `UPDATE sessions SET revoked_at = NOW()
+              WHERE user_id = $1 AND revoked_at IS NULL`
+
+	_, err := r.db.ExecContext(ctx, query, userID)
+	return err
+}
+
+// IsFamilyActive проверяет, что семейство сессий не отозвано.
+func (r *SessionRepository) IsFamilyActive(ctx context.Context, familyID string) (bool, error) {
+	query := `SELECT EXISTS (
+                  SELECT 1 FROM sessions
+                  WHERE family_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+              )`
+
+	var active bool
+	if err := r.db.QueryRowContext(ctx, query, familyID).Scan(&active); err != nil {
+		return false, err
+	}
+
+	return active, nil
+}
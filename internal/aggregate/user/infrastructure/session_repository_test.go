@@ -0,0 +1,63 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSessionByTokenHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSessionRepository(db)
+
+	tokenHash := []byte("hash")
+	expiresAt := time.Now().Add(time.Hour)
+	createdAt := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "family_id", "token_hash", "expires_at", "created_at", "rotated_at", "revoked_at"}).
+		AddRow(1, 2, "family", tokenHash, expiresAt, createdAt, nil, nil)
+
+	mock.ExpectQuery(`SELECT .* FROM sessions WHERE token_hash = \$1`).
+		WithArgs(tokenHash).
+		WillReturnRows(rows)
+
+	session, err := repo.GetByTokenHash(context.Background(), tokenHash)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), session.ID)
+	assert.Equal(t, uint32(2), session.UserID)
+	assert.Equal(t, "family", session.FamilyID)
+	assert.True(t, session.RotatedAt.IsZero())
+	assert.True(t, session.RevokedAt.IsZero())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkSessionRotated(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSessionRepository(db)
+
+	mock.ExpectExec(`UPDATE sessions SET rotated_at`).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(`UPDATE sessions SET rotated_at`).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.MarkRotated(context.Background(), 1))
+
+	// Повторный обмен того же токена
+	assert.ErrorIs(t, repo.MarkRotated(context.Background(), 1), common.ErrNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
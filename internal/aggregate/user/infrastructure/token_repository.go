@@ -0,0 +1,70 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/database"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+)
+
+// TokenRepository хранит одноразовые токены сброса пароля и подтверждения email
+type TokenRepository struct {
+	db *database.DB
+}
+
+func NewTokenRepository(db *sql.DB) *TokenRepository {
+	return &TokenRepository{db: database.New(db)}
+}
+
+// Create сохраняет новый токен.
+func (r *TokenRepository) Create(ctx context.Context, token *models.OneTimeToken) (uint32, error) {
+	query := `INSERT INTO user_tokens (user_id, purpose, token_hash, expires_at)
+              VALUES ($1, $2, $3, $4) RETURNING id`
+
+	var tokenID uint32
+	err := r.db.QueryRowContext(ctx, query, token.UserID, token.Purpose, token.TokenHash, token.ExpiresAt).Scan(&tokenID)
+	if err != nil {
+		return 0, err
+	}
+
+	return tokenID, nil
+}
+
+// Consume помечает токен использованным и возвращает его. Если токен не найден,
+// уже использован или истёк к моменту now, возвращает common.ErrNotFound.
+func (r *TokenRepository) Consume(ctx context.Context, purpose models.TokenPurpose, tokenHash []byte, now time.Time) (*models.OneTimeToken, error) {
+	query := `UPDATE user_tokens SET used_at = NOW()
+              WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > $3
+              RETURNING id, user_id, purpose, token_hash, expires_at, created_at`
+
+	token := &models.OneTimeToken{}
+	err := r.db.QueryRowContext(ctx, query, tokenHash, purpose, now).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Purpose,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+
+	token.UsedAt = now
+	return token, nil
+}
+
+// InvalidateForUser помечает использованными все действующие токены пользователя с назначением purpose.
+func (r *TokenRepository) InvalidateForUser(ctx context.Context, userID uint32, purpose models.TokenPurpose) error {
+	query := `UPDATE user_tokens SET used_at = NOW()
+              WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL`
+
+	_, err := r.db.ExecContext(ctx, query, userID, purpose)
+	return err
+}
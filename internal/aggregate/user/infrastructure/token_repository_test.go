@@ -0,0 +1,46 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsumeToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTokenRepository(db)
+
+	tokenHash := []byte("hash")
+	now := time.Now()
+	expiresAt := now.Add(time.Hour)
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "purpose", "token_hash", "expires_at", "created_at"}).
+		AddRow(3, 7, "password_reset", tokenHash, expiresAt, now)
+
+	mock.ExpectQuery(`UPDATE user_tokens SET used_at = NOW\(\) WHERE token_hash = \$1 AND purpose = \$2 AND used_at IS NULL AND expires_at > \$3 RETURNING`).
+		WithArgs(tokenHash, models.TokenPurposePasswordReset, now).
+		WillReturnRows(rows)
+
+	token, err := repo.Consume(context.Background(), models.TokenPurposePasswordReset, tokenHash, now)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(7), token.UserID)
+	assert.Equal(t, now, token.UsedAt)
+
+	// Повторное использование, истёкший или чужой токен не находятся
+	mock.ExpectQuery(`UPDATE user_tokens`).
+		WithArgs(tokenHash, models.TokenPurposePasswordReset, now).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.Consume(context.Background(), models.TokenPurposePasswordReset, tokenHash, now)
+	assert.ErrorIs(t, err, common.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
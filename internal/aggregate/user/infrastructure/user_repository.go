@@ -1,27 +1,42 @@
 package infrastructure
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/usecases"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/database"
 	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
 )
 
+const userColumns = `id, username, email, password_hash, role, failed_login_attempts, locked_until, email_verified_at, deactivated_at`
+
+// userSortColumns сопоставляет поля из usecases.UserSortFields столбцам таблицы
+var userSortColumns = map[string]string{
+	"id":       "id",
+	"username": "username",
+}
+
 type UserRepository struct {
-	db *sql.DB
+	db *database.DB
 }
 
-func NewUserRepository(database *sql.DB) *UserRepository {
-	return &UserRepository{db: database}
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{db: database.New(db)}
 }
 
 // Create создаёт нового пользователя
-func (r *UserRepository) Create(user *models.User) (uint32, error) {
+func (r *UserRepository) Create(ctx context.Context, user *models.User) (uint32, error) {
 	query := `INSERT INTO users (username, email, password_hash, role) 
               VALUES ($1, $2, $3, $4) RETURNING id`
 
 	var userID uint32
-	err := r.db.QueryRow(query, user.Username, user.Email, user.PasswordHash, user.Role).Scan(&userID)
+	err := r.db.QueryRowContext(ctx, query, user.Username, user.Email, user.PasswordHash, user.Role).Scan(&userID)
 	if err != nil {
 		return 0, err
 	}
@@ -30,61 +45,213 @@ func (r *UserRepository) Create(user *models.User) (uint32, error) {
 }
 
 // GetById получает пользователя по его ID.
-func (r *UserRepository) GetById(id uint32) (*models.User, error) {
-	query := `SELECT id, username, email, password_hash, role 
-              FROM users WHERE id = $1`
+func (r *UserRepository) GetById(ctx context.Context, id uint32) (*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
 
-	user := &models.User{}
-	err := r.db.QueryRow(query, id).Scan(&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.Role)
+	return scanUser(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetByUsername получает пользователя по его Username.
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE username = $1`
+
+	return scanUser(r.db.QueryRowContext(ctx, query, username))
+}
+
+// GetByEmail получает пользователя по его Username.
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE email = $1`
+
+	return scanUser(r.db.QueryRowContext(ctx, query, email))
+}
+
+// DeleteById удаляет пользователя по его ID.
+func (r *UserRepository) DeleteById(ctx context.Context, id uint32) error {
+	query := `DELETE FROM users WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, common.ErrNotFound
-		}
-		return nil, err
+		return err
 	}
 
-	return user, nil
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return common.ErrNotFound
+	}
+
+	return nil
 }
 
-// GetByUsername получает пользователя по его Username.
-func (r *UserRepository) GetByUsername(username string) (*models.User, error) {
-	query := `SELECT id, username, email, password_hash, role 
-              FROM users WHERE username = $1`
+// Update обновляет имя, email и роль пользователя. Смена email снимает его подтверждение.
+func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
+	query := `UPDATE users 
+              SET username = $1, email = $2, role = $3,
+                  email_verified_at = CASE WHEN email = $2 THEN email_verified_at ELSE NULL END
+              WHERE id = $4`
 
-	user := &models.User{}
-	err := r.db.QueryRow(query, username).Scan(&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.Role)
+	result, err := r.db.ExecContext(ctx, query, user.Username, user.Email, user.Role, user.ID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, common.ErrNotFound
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return common.ErrNotFound
+	}
+
+	return nil
+}
+
+// List возвращает страницу пользователей в порядке ID
+func (r *UserRepository) List(ctx context.Context, filter usecases.UserFilter, page common.PageRequest) (*common.Page[*models.User], error) {
+	var (
+		whereClauses []string
+		args         []any
+	)
+
+	if filter.Role != "" {
+		whereClauses = append(whereClauses, fmt.Sprintf("role = $%d", len(args)+1))
+		args = append(args, filter.Role)
+	}
+	if filter.Deactivated != nil {
+		if *filter.Deactivated {
+			whereClauses = append(whereClauses, "deactivated_at IS NOT NULL")
+		} else {
+			whereClauses = append(whereClauses, "deactivated_at IS NULL")
 		}
-		return nil, err
 	}
 
-	return user, nil
+	var totalCount int
+	countQuery := `SELECT COUNT(*) FROM users ` + whereSQL(whereClauses)
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&totalCount); err != nil {
+		return nil, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	column, ok := userSortColumns[page.Sort]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown sort field %q", common.ErrInvalidInput, page.Sort)
+	}
+
+	direction, operator := "ASC", ">"
+	if page.Desc {
+		direction, operator = "DESC", "<"
+	}
+
+	if page.Cursor != nil {
+		whereClauses = append(whereClauses, fmt.Sprintf("(%s, id) %s ($%d, $%d)", column, operator, len(args)+1, len(args)+2))
+		args = append(args, page.Cursor.Value, page.Cursor.ID)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM users %s ORDER BY %s %s, id %s`, userColumns, whereSQL(whereClauses), column, direction, direction)
+	if page.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", page.Limit+1)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user row: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over user rows: %w", err)
+	}
+
+	result := &common.Page[*models.User]{
+		Items:      users,
+		TotalCount: totalCount,
+	}
+
+	if page.Limit > 0 && len(users) > page.Limit {
+		result.Items = users[:page.Limit]
+
+		last := result.Items[page.Limit-1]
+		value := strconv.FormatUint(uint64(last.ID), 10)
+		if page.Sort == "username" {
+			value = last.Username
+		}
+
+		result.NextCursor = common.EncodeCursor(common.Cursor{
+			Sort:  page.SortKey(),
+			Value: value,
+			ID:    last.ID,
+		})
+	}
+
+	return result, nil
 }
 
-// GetByEmail получает пользователя по его Username.
-func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
-	query := `SELECT id, username, email, password_hash, role 
-              FROM users WHERE email = $1`
+// Deactivate отмечает пользователя деактивированным. Повторная деактивация ничего не меняет.
+func (r *UserRepository) Deactivate(ctx context.Context, id uint32) error {
+	query := `UPDATE users SET deactivated_at = COALESCE(deactivated_at, NOW()) WHERE id = $1`
 
-	user := &models.User{}
-	err := r.db.QueryRow(query, email).Scan(&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.Role)
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return common.ErrNotFound
+	}
+
+	return nil
+}
+
+// RegisterLoginFailure увеличивает счётчик неудачных входов. Когда он достигает threshold,
+// учётная запись блокируется до lockedUntil, а счётчик обнуляется. Возвращает время окончания
+// последней блокировки или нулевое время, если блокировок не было.
+func (r *UserRepository) RegisterLoginFailure(ctx context.Context, id uint32, threshold int, lockedUntil time.Time) (time.Time, error) {
+	query := `UPDATE users
+              SET failed_login_attempts = CASE WHEN failed_login_attempts + 1 >= $2 THEN 0 ELSE failed_login_attempts + 1 END,
+                  locked_until = CASE WHEN failed_login_attempts + 1 >= $2 THEN $3 ELSE locked_until END
+              WHERE id = $1
+              RETURNING locked_until`
+
+	var until sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, id, threshold, lockedUntil).Scan(&until)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, common.ErrNotFound
+			return time.Time{}, common.ErrNotFound
 		}
-		return nil, err
+		return time.Time{}, err
 	}
 
-	return user, nil
+	return until.Time, nil
 }
 
-// DeleteById удаляет пользователя по его ID.
-func (r *UserRepository) DeleteById(id uint32) error {
-	query := `DELETE FROM users WHERE id = $1`
+// ResetLoginFailures снимает блокировку и обнуляет счётчик после успешного входа.
+func (r *UserRepository) ResetLoginFailures(ctx context.Context, id uint32) error {
+	query := `UPDATE users SET failed_login_attempts = 0, locked_until = NULL
+              WHERE id = $1 AND (failed_login_attempts > 0 OR locked_until IS NOT NULL)`
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+// UpdatePassword заменяет хэш пароля пользователя.
+func (r *UserRepository) UpdatePassword(ctx context.Context, id uint32, passwordHash []byte) error {
+	query := `UPDATE users SET password_hash = $1 WHERE id = $2`
 
-	result, err := r.db.Exec(query, id)
+	result, err := r.db.ExecContext(ctx, query, passwordHash, id)
 	if err != nil {
 		return err
 	}
@@ -100,13 +267,11 @@ func (r *UserRepository) DeleteById(id uint32) error {
 	return nil
 }
 
-// Update обновляет данные пользователя.
-func (r *UserRepository) Update(user *models.User) error {
-	query := `UPDATE users 
-              SET username = $1, email = $2, password_hash = $3, role = $4, 
-              WHERE id = $5`
+// MarkEmailVerified отмечает email пользователя подтверждённым. Повторное подтверждение ничего не меняет.
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, id uint32) error {
+	query := `UPDATE users SET email_verified_at = COALESCE(email_verified_at, NOW()) WHERE id = $1`
 
-	result, err := r.db.Exec(query, user.Username, user.Email, user.PasswordHash, user.Role, user.ID)
+	result, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -121,3 +286,49 @@ func (r *UserRepository) Update(user *models.User) error {
 
 	return nil
 }
+
+// AddMembership добавляет пользователя в команду teamID с ролью role. Роль того, кто уже состоит
+// в команде, не меняется: её назначает менеджер через состав команды.
+func (r *UserRepository) AddMembership(ctx context.Context, id uint32, teamID uint32, role string) error {
+	query := `INSERT INTO team_memberships (user_id, team_id, role) VALUES ($1, $2, $3)
+              ON CONFLICT (user_id, team_id) DO NOTHING`
+
+	_, err := r.db.ExecContext(ctx, query, id, teamID, role)
+	return err
+}
+
+// scanUser читает пользователя в порядке колонок userColumns
+func scanUser(row interface{ Scan(dest ...any) error }) (*models.User, error) {
+	user := &models.User{}
+	var lockedUntil, emailVerifiedAt, deactivatedAt sql.NullTime
+
+	err := row.Scan(
+		&user.ID,
+		&user.Username,
+		&user.Email,
+		&user.PasswordHash,
+		&user.Role,
+		&user.FailedLoginAttempts,
+		&lockedUntil,
+		&emailVerifiedAt,
+		&deactivatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+
+	user.LockedUntil = lockedUntil.Time
+	user.EmailVerifiedAt = emailVerifiedAt.Time
+	user.DeactivatedAt = deactivatedAt.Time
+	return user, nil
+}
+
+func whereSQL(whereClauses []string) string {
+	if len(whereClauses) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(whereClauses, " AND ")
+}
@@ -0,0 +1,189 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/usecases"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetUserByEmail(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	lockedUntil := time.Now().Add(time.Minute)
+	rows := sqlmock.NewRows([]string{"id", "username", "email", "password_hash", "role", "failed_login_attempts", "locked_until", "email_verified_at", "deactivated_at"}).
+		AddRow(1, "alice", "alice@example.com", []byte("hash"), "employee", 0, lockedUntil, nil, nil)
+
+	mock.ExpectQuery(`SELECT id, username, email, password_hash, role, failed_login_attempts, locked_until, email_verified_at, deactivated_at FROM users WHERE email = \$1`).
+		WithArgs("alice@example.com").
+		WillReturnRows(rows)
+
+	user, err := repo.GetByEmail(context.Background(), "alice@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), user.ID)
+	assert.Equal(t, lockedUntil, user.LockedUntil)
+	assert.True(t, user.EmailVerifiedAt.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRegisterLoginFailure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	lockedUntil := time.Now().Add(15 * time.Minute)
+
+	// Порог ещё не достигнут: блокировки нет
+	mock.ExpectQuery(`UPDATE users SET failed_login_attempts = CASE WHEN failed_login_attempts \+ 1 >= \$2 .* RETURNING locked_until`).
+		WithArgs(uint32(1), 5, lockedUntil).
+		WillReturnRows(sqlmock.NewRows([]string{"locked_until"}).AddRow(nil))
+
+	until, err := repo.RegisterLoginFailure(context.Background(), 1, 5, lockedUntil)
+	assert.NoError(t, err)
+	assert.True(t, until.IsZero())
+
+	// Пятая неудача подряд блокирует учётную запись
+	mock.ExpectQuery(`UPDATE users SET failed_login_attempts`).
+		WithArgs(uint32(1), 5, lockedUntil).
+		WillReturnRows(sqlmock.NewRows([]string{"locked_until"}).AddRow(lockedUntil))
+
+	until, err = repo.RegisterLoginFailure(context.Background(), 1, 5, lockedUntil)
+	assert.NoError(t, err)
+	assert.Equal(t, lockedUntil, until)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	mock.ExpectExec(`UPDATE users SET username = \$1, email = \$2, role = \$3, email_verified_at = CASE WHEN email = \$2 THEN email_verified_at ELSE NULL END WHERE id = \$4`).
+		WithArgs("alice", "alice@example.org", "member", uint32(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.Update(context.Background(), &models.User{ID: 1, Username: "alice", Email: "alice@example.org", Role: "member"})
+	assert.NoError(t, err)
+
+	mock.ExpectExec(`UPDATE users SET username`).
+		WithArgs("bob", "bob@example.org", "member", uint32(2)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Update(context.Background(), &models.User{ID: 2, Username: "bob", Email: "bob@example.org", Role: "member"})
+	assert.ErrorIs(t, err, common.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListUsers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	deactivated := false
+	columns := []string{"id", "username", "email", "password_hash", "role", "failed_login_attempts", "locked_until", "email_verified_at", "deactivated_at"}
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM users WHERE role = \$1 AND deactivated_at IS NULL`).
+		WithArgs("member").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+
+	mock.ExpectQuery(`SELECT id, username, .* FROM users WHERE role = \$1 AND deactivated_at IS NULL AND \(id, id\) > \(\$2, \$3\) ORDER BY id ASC, id ASC LIMIT 3`).
+		WithArgs("member", "10", uint32(10)).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(11, "alice", "alice@example.com", []byte("hash"), "member", 0, nil, nil, nil).
+			AddRow(12, "bob", "bob@example.com", []byte("hash"), "member", 0, nil, nil, nil).
+			AddRow(13, "carol", "carol@example.com", []byte("hash"), "member", 0, nil, nil, nil))
+
+	page, err := repo.List(context.Background(),
+		usecases.UserFilter{Role: "member", Deactivated: &deactivated},
+		common.PageRequest{Limit: 2, Sort: "id", Cursor: &common.Cursor{Sort: "id", Value: "10", ID: 10}})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, page.TotalCount)
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, uint32(12), page.Items[1].ID)
+
+	cursor, err := common.DecodeCursor(page.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(12), cursor.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListUsersSortedByUsername(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	columns := []string{"id", "username", "email", "password_hash", "role", "failed_login_attempts", "locked_until", "email_verified_at", "deactivated_at"}
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM users`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	mock.ExpectQuery(`SELECT id, username, .* FROM users WHERE \(username, id\) < \(\$1, \$2\) ORDER BY username DESC, id DESC LIMIT 2`).
+		WithArgs("dave", uint32(4)).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(3, "carol", "carol@example.com", []byte("hash"), "member", 0, nil, nil, nil).
+			AddRow(2, "bob", "bob@example.com", []byte("hash"), "member", 0, nil, nil, nil))
+
+	page, err := repo.List(context.Background(), usecases.UserFilter{},
+		common.PageRequest{Limit: 1, Sort: "username", Desc: true, Cursor: &common.Cursor{Sort: "-username", Value: "dave", ID: 4}})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+
+	cursor, err := common.DecodeCursor(page.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, common.Cursor{Sort: "-username", Value: "carol", ID: 3}, *cursor)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeactivateUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	mock.ExpectExec(`UPDATE users SET deactivated_at = COALESCE\(deactivated_at, NOW\(\)\) WHERE id = \$1`).
+		WithArgs(uint32(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.Deactivate(context.Background(), 1))
+
+	mock.ExpectExec(`UPDATE users SET deactivated_at`).
+		WithArgs(uint32(2)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorIs(t, repo.Deactivate(context.Background(), 2), common.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddMembershipKeepsExistingRole(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	// Принятое приглашение не должно понижать менеджера, уже состоящего в команде
+	mock.ExpectExec(`INSERT INTO team_memberships \(user_id, team_id, role\) VALUES \(\$1, \$2, \$3\)\s+ON CONFLICT \(user_id, team_id\) DO NOTHING`).
+		WithArgs(uint32(2), uint32(10), common.RoleMember).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.AddMembership(context.Background(), 2, 10, common.RoleMember))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
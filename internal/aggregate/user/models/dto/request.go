@@ -5,7 +5,6 @@ type RegisterUserRequestDTO struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=8"`
 	Username string `json:"name" validate:"required"`
-	Role     string `json:"role"`
 }
 
 // LoginUserRequestDTO - данные для входа пользователя
@@ -13,3 +12,54 @@ type LoginUserRequestDTO struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
 }
+
+// ForgotPasswordRequestDTO - запрос письма для сброса пароля
+type ForgotPasswordRequestDTO struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPasswordRequestDTO - новый пароль и токен из письма
+type ResetPasswordRequestDTO struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8"`
+}
+
+// RefreshTokenRequestDTO - данные для обновления токенов
+type RefreshTokenRequestDTO struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// UpdateProfileRequestDTO - изменяемые поля профиля, отсутствующее поле не меняется
+type UpdateProfileRequestDTO struct {
+	Username *string `json:"name"`
+	Email    *string `json:"email" validate:"omitempty,email"`
+}
+
+// ChangePasswordRequestDTO - смена пароля с подтверждением текущим паролем
+type ChangePasswordRequestDTO struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8"`
+}
+
+// ChangeRoleRequestDTO - новая роль пользователя
+type ChangeRoleRequestDTO struct {
+	Role string `json:"role" validate:"required,oneof=admin manager member viewer"`
+}
+
+// InviteRequestDTO - приглашение в команду
+type InviteRequestDTO struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required,oneof=admin manager member viewer"`
+}
+
+// AcceptInvitationRequestDTO - принятие приглашения. Имя и пароль нужны для создания новой учётной записи.
+type AcceptInvitationRequestDTO struct {
+	Token    string `json:"token" validate:"required"`
+	Username string `json:"name"`
+	Password string `json:"password" validate:"omitempty,min=8"`
+}
+
+// DeclineInvitationRequestDTO - отказ от приглашения
+type DeclineInvitationRequestDTO struct {
+	Token string `json:"token" validate:"required"`
+}
@@ -1,9 +1,55 @@
 package dto
 
+import "time"
+
 type RegisterUserResponseDTO struct {
-	AccessToken string `json:"access_token" validate:"required"`
+	AccessToken  string `json:"access_token" validate:"required"`
+	RefreshToken string `json:"refresh_token" validate:"required"`
 }
 
 type LoginUserResponseDTO struct {
-	AccessToken string `json:"access_token" validate:"required"`
+	AccessToken  string `json:"access_token" validate:"required"`
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type RefreshTokenResponseDTO struct {
+	AccessToken  string `json:"access_token" validate:"required"`
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type ChangePasswordResponseDTO struct {
+	AccessToken  string `json:"access_token" validate:"required"`
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type UserResponseDTO struct {
+	ID              uint32     `json:"id"`
+	Username        string     `json:"name"`
+	Email           string     `json:"email"`
+	Role            string     `json:"role"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	DeactivatedAt   *time.Time `json:"deactivated_at,omitempty"`
+}
+
+type UsersResponseDTO struct {
+	Items      []UserResponseDTO `json:"items"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+	TotalCount int               `json:"total_count"`
+}
+
+type AcceptInvitationResponseDTO struct {
+	AccessToken  string `json:"access_token" validate:"required"`
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type InvitationResponseDTO struct {
+	ID          uint32     `json:"id"`
+	TeamID      uint32     `json:"team_id"`
+	Email       string     `json:"email"`
+	Role        string     `json:"role"`
+	InvitedBy   uint32     `json:"invited_by,omitempty"`
+	Status      string     `json:"status"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
 }
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationDeclined InvitationStatus = "declined"
+	// InvitationRevoked - приглашение заменено новым на тот же адрес
+	InvitationRevoked InvitationStatus = "revoked"
+)
+
+// Invitation - приглашение в команду с ролью Role. Ответить на него можно один раз до ExpiresAt.
+type Invitation struct {
+	ID          uint32
+	TeamID      uint32
+	Email       string
+	Role        string
+	TokenHash   []byte `json:"-"`
+	InvitedBy   uint32
+	Status      InvitationStatus
+	ExpiresAt   time.Time
+	CreatedAt   time.Time
+	RespondedAt time.Time
+}
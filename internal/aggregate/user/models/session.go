@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Session - refresh-токен пользователя. Все токены, выпущенные в рамках
+// одного входа, объединены общим FamilyID.
+type Session struct {
+	ID        uint32
+	UserID    uint32
+	FamilyID  string
+	TokenHash []byte
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	RotatedAt time.Time
+	RevokedAt time.Time
+}
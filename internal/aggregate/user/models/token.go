@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// TokenPurpose - назначение одноразового токена
+type TokenPurpose string
+
+const (
+	TokenPurposePasswordReset     TokenPurpose = "password_reset"
+	TokenPurposeEmailVerification TokenPurpose = "email_verification"
+)
+
+// OneTimeToken - токен из письма. Действует до ExpiresAt и только один раз.
+type OneTimeToken struct {
+	ID        uint32
+	UserID    uint32
+	Purpose   TokenPurpose
+	TokenHash []byte
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    time.Time
+}
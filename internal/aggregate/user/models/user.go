@@ -1,9 +1,18 @@
 package models
 
+import "time"
+
 type User struct {
-	ID           uint32
-	Username     string
-	Email        string
-	PasswordHash []byte
+	ID       uint32
+	Username string
+	Email    string
+	// PasswordHash не сериализуется, чтобы не попадать в журнал аудита
+	PasswordHash []byte `json:"-"`
 	Role         string
+	// FailedLoginAttempts - неудачные попытки входа подряд с момента последней блокировки
+	FailedLoginAttempts int
+	LockedUntil         time.Time
+	EmailVerifiedAt     time.Time
+	// DeactivatedAt - момент деактивации; деактивированный пользователь не может войти
+	DeactivatedAt time.Time
 }
@@ -2,11 +2,15 @@ package transport
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models/dto"
 	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/usecases"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/utils"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/validation"
 )
 
 type AuthHandlers struct {
@@ -24,24 +28,44 @@ type handler = func(w http.ResponseWriter, r *http.Request) error
 func (h *AuthHandlers) RegisterRoutes(mux *http.ServeMux, errorHandler func(handler) http.Handler) {
 	mux.Handle("POST /register", errorHandler(h.registerUser))
 	mux.Handle("POST /login", errorHandler(h.authenticate))
+	mux.Handle("POST /token/refresh", errorHandler(h.refreshToken))
+	mux.Handle("POST /logout", errorHandler(h.logout))
+	mux.Handle("POST /password/forgot", errorHandler(h.forgotPassword))
+	mux.Handle("POST /password/reset", errorHandler(h.resetPassword))
+	mux.Handle("GET /verify-email", errorHandler(h.verifyEmail))
+
+	mux.Handle("GET /me", errorHandler(h.getMe))
+	mux.Handle("PATCH /me", errorHandler(h.updateMe))
+	mux.Handle("POST /me/password", errorHandler(h.changePassword))
+	mux.Handle("POST /me/verify-email", errorHandler(h.resendVerificationEmail))
+
+	mux.Handle("GET /users", errorHandler(h.getUsers))
+	mux.Handle("PATCH /users/{id}", errorHandler(h.updateUser))
+	mux.Handle("POST /users/{id}/deactivate", errorHandler(h.deactivateUser))
+	mux.Handle("PUT /users/{id}/role", errorHandler(h.changeRole))
+
+	mux.Handle("POST /teams/{id}/invitations", errorHandler(h.invite))
+	mux.Handle("GET /teams/{id}/invitations", errorHandler(h.getInvitations))
+	mux.Handle("POST /invitations/accept", errorHandler(h.acceptInvitation))
+	mux.Handle("POST /invitations/decline", errorHandler(h.declineInvitation))
 }
 
 func (h *AuthHandlers) registerUser(w http.ResponseWriter, r *http.Request) error {
 	var regUserReq dto.RegisterUserRequestDTO
-	err := json.NewDecoder(r.Body).Decode(&regUserReq)
+	err := utils.DecodeJSON(r, &regUserReq)
 	if err != nil {
-		return fmt.Errorf("error while decoding request body: %w", err)
+		return err
 	}
-	defer r.Body.Close()
 
-	cmd := usecases.NewCreateUserCommand(regUserReq.Username, regUserReq.Email, regUserReq.Password, regUserReq.Role)
-	token, err := h.usecases.CreateUser(cmd)
+	cmd := usecases.NewCreateUserCommand(regUserReq.Username, regUserReq.Email, regUserReq.Password)
+	tokens, err := h.usecases.CreateUser(r.Context(), cmd)
 	if err != nil {
-		return fmt.Errorf("failed to register user: %v", err)
+		return fmt.Errorf("failed to register user: %w", err)
 	}
 
 	reqUserResp := dto.RegisterUserResponseDTO{
-		AccessToken: token,
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -58,15 +82,25 @@ func (h *AuthHandlers) authenticate(w http.ResponseWriter, r *http.Request) erro
 		return fmt.Errorf("basic auth header is missing or malformed: %w", err)
 	}
 
-	token, err := h.usecases.AuthenticateUser(authUserReq.Email, authUserReq.Password)
+	if err := validation.Struct(authUserReq); err != nil {
+		return err
+	}
+
+	tokens, err := h.usecases.AuthenticateUser(r.Context(), authUserReq.Email, authUserReq.Password)
 
 	if err != nil {
+		// Ограничение частоты отдаётся как 429 с Retry-After, деактивация - как 403,
+		// остальное - как неверные учётные данные
+		if errors.Is(err, common.ErrTooManyRequests) || errors.Is(err, common.ErrAccountDeactivated) {
+			return err
+		}
 		http.Error(w, "invalid username or password", http.StatusUnauthorized)
 		return nil
 	}
 
 	reqUserResp := dto.LoginUserResponseDTO{
-		AccessToken: token,
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -75,3 +109,90 @@ func (h *AuthHandlers) authenticate(w http.ResponseWriter, r *http.Request) erro
 	}
 	return err
 }
+
+func (h *AuthHandlers) refreshToken(w http.ResponseWriter, r *http.Request) error {
+	var refreshReq dto.RefreshTokenRequestDTO
+	err := utils.DecodeJSON(r, &refreshReq)
+	if err != nil {
+		return err
+	}
+
+	tokens, err := h.usecases.RefreshTokens(r.Context(), refreshReq.RefreshToken)
+	if err != nil {
+		return fmt.Errorf("failed to refresh token: %w", err)
+	}
+
+	refreshResp := dto.RefreshTokenResponseDTO{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(refreshResp); err != nil {
+		return fmt.Errorf("failed to encode response to JSON: %w", err)
+	}
+	return nil
+}
+
+func (h *AuthHandlers) logout(w http.ResponseWriter, r *http.Request) error {
+	claims := r.Context().Value(common.ContextKeyClaims).(*common.Claims)
+
+	if err := h.usecases.Logout(r.Context(), claims.SessionID); err != nil {
+		return fmt.Errorf("failed to logout: %w", err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (h *AuthHandlers) forgotPassword(w http.ResponseWriter, r *http.Request) error {
+	var forgotReq dto.ForgotPasswordRequestDTO
+	if err := utils.DecodeJSON(r, &forgotReq); err != nil {
+		return err
+	}
+
+	if err := h.usecases.RequestPasswordReset(r.Context(), forgotReq.Email); err != nil {
+		return fmt.Errorf("failed to request password reset: %w", err)
+	}
+
+	// Ответ одинаков для известных и неизвестных адресов
+	w.WriteHeader(http.StatusAccepted)
+	return nil
+}
+
+func (h *AuthHandlers) resetPassword(w http.ResponseWriter, r *http.Request) error {
+	var resetReq dto.ResetPasswordRequestDTO
+	if err := utils.DecodeJSON(r, &resetReq); err != nil {
+		return err
+	}
+
+	if err := h.usecases.ResetPassword(r.Context(), resetReq.Token, resetReq.Password); err != nil {
+		return fmt.Errorf("failed to reset password: %w", err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (h *AuthHandlers) verifyEmail(w http.ResponseWriter, r *http.Request) error {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		return fmt.Errorf("%w: token is required", common.ErrInvalidInput)
+	}
+
+	if err := h.usecases.VerifyEmail(r.Context(), token); err != nil {
+		return fmt.Errorf("failed to verify email: %w", err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (h *AuthHandlers) resendVerificationEmail(w http.ResponseWriter, r *http.Request) error {
+	if err := h.usecases.ResendVerificationEmail(r.Context()); err != nil {
+		return fmt.Errorf("failed to resend verification email: %w", err)
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	return nil
+}
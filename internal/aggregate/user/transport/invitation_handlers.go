@@ -0,0 +1,118 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models/dto"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/usecases"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/utils"
+)
+
+func (h *AuthHandlers) invite(w http.ResponseWriter, r *http.Request) error {
+	teamID, err := utils.ExtractIDFromPathValue(r, "id")
+	if err != nil {
+		return fmt.Errorf("failed to extract id: %w", err)
+	}
+
+	var inviteReq dto.InviteRequestDTO
+	if err := utils.DecodeJSON(r, &inviteReq); err != nil {
+		return err
+	}
+
+	invitation, err := h.usecases.Invite(r.Context(), usecases.NewInviteCommand(teamID, inviteReq.Email, inviteReq.Role))
+	if err != nil {
+		return fmt.Errorf("failed to invite to team: %w", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(invitationToDTO(invitation)); err != nil {
+		return fmt.Errorf("failed to encode invitation to JSON: %w", err)
+	}
+	return nil
+}
+
+func (h *AuthHandlers) getInvitations(w http.ResponseWriter, r *http.Request) error {
+	teamID, err := utils.ExtractIDFromPathValue(r, "id")
+	if err != nil {
+		return fmt.Errorf("failed to extract id: %w", err)
+	}
+
+	invitations, err := h.usecases.GetInvitations(r.Context(), teamID)
+	if err != nil {
+		return err
+	}
+
+	responseData := make([]dto.InvitationResponseDTO, len(invitations))
+	for i, v := range invitations {
+		responseData[i] = invitationToDTO(v)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(responseData); err != nil {
+		return fmt.Errorf("failed to encode invitations to JSON: %w", err)
+	}
+	return nil
+}
+
+func (h *AuthHandlers) acceptInvitation(w http.ResponseWriter, r *http.Request) error {
+	var acceptReq dto.AcceptInvitationRequestDTO
+	if err := utils.DecodeJSON(r, &acceptReq); err != nil {
+		return err
+	}
+
+	cmd := usecases.NewAcceptInvitationCommand(acceptReq.Token, acceptReq.Username, acceptReq.Password)
+	tokens, err := h.usecases.AcceptInvitation(r.Context(), cmd)
+	if err != nil {
+		return fmt.Errorf("failed to accept invitation: %w", err)
+	}
+
+	// Существующий пользователь входит как обычно, новому сразу выдаются токены
+	if tokens == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+
+	acceptResp := dto.AcceptInvitationResponseDTO{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(acceptResp); err != nil {
+		return fmt.Errorf("failed to encode response to JSON: %w", err)
+	}
+	return nil
+}
+
+func (h *AuthHandlers) declineInvitation(w http.ResponseWriter, r *http.Request) error {
+	var declineReq dto.DeclineInvitationRequestDTO
+	if err := utils.DecodeJSON(r, &declineReq); err != nil {
+		return err
+	}
+
+	if err := h.usecases.DeclineInvitation(r.Context(), declineReq.Token); err != nil {
+		return fmt.Errorf("failed to decline invitation: %w", err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func invitationToDTO(invitation *models.Invitation) dto.InvitationResponseDTO {
+	return dto.InvitationResponseDTO{
+		ID:          invitation.ID,
+		TeamID:      invitation.TeamID,
+		Email:       invitation.Email,
+		Role:        invitation.Role,
+		InvitedBy:   invitation.InvitedBy,
+		Status:      string(invitation.Status),
+		ExpiresAt:   invitation.ExpiresAt,
+		CreatedAt:   invitation.CreatedAt,
+		RespondedAt: timeOrNil(invitation.RespondedAt),
+	}
+}
@@ -0,0 +1,194 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models/dto"
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/usecases"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/utils"
+)
+
+func (h *AuthHandlers) getMe(w http.ResponseWriter, r *http.Request) error {
+	user, err := h.usecases.GetMe(r.Context())
+	if err != nil {
+		return err
+	}
+
+	return writeUser(w, user)
+}
+
+func (h *AuthHandlers) updateMe(w http.ResponseWriter, r *http.Request) error {
+	var updateReq dto.UpdateProfileRequestDTO
+	if err := utils.DecodeJSON(r, &updateReq); err != nil {
+		return err
+	}
+
+	user, err := h.usecases.UpdateMe(r.Context(), usecases.ProfilePatch{
+		Username: updateReq.Username,
+		Email:    updateReq.Email,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	return writeUser(w, user)
+}
+
+func (h *AuthHandlers) changePassword(w http.ResponseWriter, r *http.Request) error {
+	var changeReq dto.ChangePasswordRequestDTO
+	if err := utils.DecodeJSON(r, &changeReq); err != nil {
+		return err
+	}
+
+	tokens, err := h.usecases.ChangePassword(r.Context(), changeReq.CurrentPassword, changeReq.NewPassword)
+	if err != nil {
+		return fmt.Errorf("failed to change password: %w", err)
+	}
+
+	changeResp := dto.ChangePasswordResponseDTO{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(changeResp); err != nil {
+		return fmt.Errorf("failed to encode response to JSON: %w", err)
+	}
+	return nil
+}
+
+func (h *AuthHandlers) getUsers(w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+
+	filter, err := parseUserFilter(query)
+	if err != nil {
+		return err
+	}
+
+	page, err := common.ParsePageRequest(query, "id", usecases.UserSortFields)
+	if err != nil {
+		return err
+	}
+
+	users, err := h.usecases.ListUsers(r.Context(), filter, page)
+	if err != nil {
+		return err
+	}
+
+	responseData := dto.UsersResponseDTO{
+		Items:      make([]dto.UserResponseDTO, len(users.Items)),
+		NextCursor: users.NextCursor,
+		TotalCount: users.TotalCount,
+	}
+	for i, v := range users.Items {
+		responseData.Items[i] = userToDTO(v)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(responseData); err != nil {
+		return fmt.Errorf("failed to encode users to JSON: %w", err)
+	}
+	return nil
+}
+
+func (h *AuthHandlers) updateUser(w http.ResponseWriter, r *http.Request) error {
+	id, err := utils.ExtractIDFromPathValue(r, "id")
+	if err != nil {
+		return fmt.Errorf("failed to extract id: %w", err)
+	}
+
+	var updateReq dto.UpdateProfileRequestDTO
+	if err := utils.DecodeJSON(r, &updateReq); err != nil {
+		return err
+	}
+
+	user, err := h.usecases.UpdateUser(r.Context(), id, usecases.ProfilePatch{
+		Username: updateReq.Username,
+		Email:    updateReq.Email,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return writeUser(w, user)
+}
+
+func (h *AuthHandlers) deactivateUser(w http.ResponseWriter, r *http.Request) error {
+	id, err := utils.ExtractIDFromPathValue(r, "id")
+	if err != nil {
+		return fmt.Errorf("failed to extract id: %w", err)
+	}
+
+	if err := h.usecases.DeactivateUser(r.Context(), id); err != nil {
+		return fmt.Errorf("failed to deactivate user: %w", err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (h *AuthHandlers) changeRole(w http.ResponseWriter, r *http.Request) error {
+	id, err := utils.ExtractIDFromPathValue(r, "id")
+	if err != nil {
+		return fmt.Errorf("failed to extract id: %w", err)
+	}
+
+	var changeReq dto.ChangeRoleRequestDTO
+	if err := utils.DecodeJSON(r, &changeReq); err != nil {
+		return err
+	}
+
+	user, err := h.usecases.ChangeRole(r.Context(), id, changeReq.Role)
+	if err != nil {
+		return fmt.Errorf("failed to change role: %w", err)
+	}
+
+	return writeUser(w, user)
+}
+
+func writeUser(w http.ResponseWriter, user *models.User) error {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(userToDTO(user)); err != nil {
+		return fmt.Errorf("failed to encode user to JSON: %w", err)
+	}
+	return nil
+}
+
+func userToDTO(user *models.User) dto.UserResponseDTO {
+	return dto.UserResponseDTO{
+		ID:              user.ID,
+		Username:        user.Username,
+		Email:           user.Email,
+		Role:            user.Role,
+		EmailVerifiedAt: timeOrNil(user.EmailVerifiedAt),
+		DeactivatedAt:   timeOrNil(user.DeactivatedAt),
+	}
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func parseUserFilter(query url.Values) (usecases.UserFilter, error) {
+	filter := usecases.UserFilter{Role: query.Get("role")}
+
+	if value := query.Get("deactivated"); value != "" {
+		deactivated, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, fmt.Errorf("%w: invalid deactivated: %v", common.ErrInvalidInput, err)
+		}
+		filter.Deactivated = &deactivated
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,206 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/logger"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// RequestPasswordReset отправляет на email ссылку для сброса пароля.
+// Для неизвестного email ничего не делает, чтобы ответ не раскрывал, зарегистрирован ли адрес.
+func (a *AuthUseCases) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := a.repo.GetByEmail(ctx, email)
+
+	switch {
+	case err == nil:
+	case errors.Is(err, common.ErrNotFound):
+		logger.FromContext(ctx).Info("password reset requested for unknown email")
+		return nil
+	default:
+		return fmt.Errorf("failed to get user by email: %w", err)
+	}
+
+	if !user.DeactivatedAt.IsZero() {
+		logger.FromContext(ctx).Info("password reset requested for deactivated user", slog.Uint64("user_id", uint64(user.ID)))
+		return nil
+	}
+
+	token, err := a.issueOneTimeToken(ctx, user.ID, models.TokenPurposePasswordReset, a.settings.ResetTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Hello, %s!\n\n"+
+		"To set a new password, open the link below. It is valid for %s and can be used once.\n\n"+
+		"%s\n\n"+
+		"If you did not request a password reset, ignore this email.\n",
+		user.Username, a.settings.ResetTokenTTL, withToken(a.settings.ResetURL, token))
+
+	// Ошибка отправки не возвращается: ответ для известного адреса не должен отличаться от ответа для неизвестного
+	if err := a.mailer.Send(ctx, user.Email, "Password reset", body); err != nil {
+		logger.FromContext(ctx).Error("failed to send password reset email",
+			slog.Uint64("user_id", uint64(user.ID)),
+			slog.Any("error", err))
+	}
+
+	return nil
+}
+
+// ResetPassword меняет пароль по токену из письма. Все сессии пользователя отзываются,
+// блокировка после неудачных входов снимается.
+func (a *AuthUseCases) ResetPassword(ctx context.Context, token, password string) error {
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	var userID uint32
+	err = a.tx.WithinTx(ctx, func(ctx context.Context) error {
+		consumed, err := a.consumeToken(ctx, models.TokenPurposePasswordReset, token)
+		if err != nil {
+			return err
+		}
+		userID = consumed.UserID
+
+		if err := a.repo.UpdatePassword(ctx, userID, passwordHash); err != nil {
+			return fmt.Errorf("failed to update password of user %d: %w", userID, err)
+		}
+		if err := a.repo.ResetLoginFailures(ctx, userID); err != nil {
+			return fmt.Errorf("failed to reset login failures of user %d: %w", userID, err)
+		}
+		if err := a.sessionRepo.RevokeAllForUser(ctx, userID); err != nil {
+			return fmt.Errorf("failed to revoke sessions of user %d: %w", userID, err)
+		}
+		if err := a.tokenRepo.InvalidateForUser(ctx, userID, models.TokenPurposePasswordReset); err != nil {
+			return fmt.Errorf("failed to invalidate reset tokens of user %d: %w", userID, err)
+		}
+		return a.audit.Record(ctx, auditEntityUser, auditActionPasswordReset, userID, nil, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// VerifyEmail подтверждает email по токену из письма
+func (a *AuthUseCases) VerifyEmail(ctx context.Context, token string) error {
+	var userID uint32
+	err := a.tx.WithinTx(ctx, func(ctx context.Context) error {
+		consumed, err := a.consumeToken(ctx, models.TokenPurposeEmailVerification, token)
+		if err != nil {
+			return err
+		}
+		userID = consumed.UserID
+
+		if err := a.repo.MarkEmailVerified(ctx, userID); err != nil {
+			return fmt.Errorf("failed to mark email of user %d verified: %w", userID, err)
+		}
+		return a.audit.Record(ctx, auditEntityUser, auditActionVerifyEmail, userID, nil, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ResendVerificationEmail повторно отправляет текущему пользователю письмо для подтверждения email.
+// Ранее выданные ссылки перестают действовать.
+func (a *AuthUseCases) ResendVerificationEmail(ctx context.Context) error {
+	claims := ctx.Value(common.ContextKeyClaims).(*common.Claims)
+
+	user, err := a.getUser(ctx, claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	if !user.EmailVerifiedAt.IsZero() {
+		return fmt.Errorf("%w: email is already verified", common.ErrInvalidInput)
+	}
+
+	return a.sendVerificationEmail(ctx, user)
+}
+
+func (a *AuthUseCases) sendVerificationEmail(ctx context.Context, user *models.User) error {
+	token, err := a.issueOneTimeToken(ctx, user.ID, models.TokenPurposeEmailVerification, a.settings.VerificationTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Hello, %s!\n\n"+
+		"Please confirm your email address by opening the link below. It is valid for %s.\n\n"+
+		"%s\n",
+		user.Username, a.settings.VerificationTokenTTL, withToken(a.settings.VerifyURL, token))
+
+	if err := a.mailer.Send(ctx, user.Email, "Confirm your email", body); err != nil {
+		return fmt.Errorf("failed to send verification email: %w", err)
+	}
+
+	return nil
+}
+
+// issueOneTimeToken выпускает новый токен, отменяя ранее выданные с тем же назначением
+func (a *AuthUseCases) issueOneTimeToken(ctx context.Context, userID uint32, purpose models.TokenPurpose, ttl time.Duration) (string, error) {
+	token, err := generateToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate %s token: %w", purpose, err)
+	}
+
+	err = a.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := a.tokenRepo.InvalidateForUser(ctx, userID, purpose); err != nil {
+			return fmt.Errorf("failed to invalidate %s tokens of user %d: %w", purpose, userID, err)
+		}
+
+		_, err := a.tokenRepo.Create(ctx, &models.OneTimeToken{
+			UserID:    userID,
+			Purpose:   purpose,
+			TokenHash: hashToken(token),
+			ExpiresAt: time.Now().Add(ttl),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to save %s token of user %d: %w", purpose, userID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func (a *AuthUseCases) consumeToken(ctx context.Context, purpose models.TokenPurpose, token string) (*models.OneTimeToken, error) {
+	consumed, err := a.tokenRepo.Consume(ctx, purpose, hashToken(token), time.Now())
+
+	switch {
+	case err == nil:
+		return consumed, nil
+	case errors.Is(err, common.ErrNotFound):
+		return nil, fmt.Errorf("%w: %s token is invalid, expired or already used", common.ErrInvalidInput, purpose)
+	default:
+		return nil, fmt.Errorf("failed to consume %s token: %w", purpose, err)
+	}
+}
+
+// withToken добавляет токен к ссылке параметром token
+func withToken(link, token string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return link + "?token=" + url.QueryEscape(token)
+	}
+
+	query := u.Query()
+	query.Set("token", token)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
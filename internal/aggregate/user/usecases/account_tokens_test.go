@@ -0,0 +1,134 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var mailLink = regexp.MustCompile(`https://\S+`)
+
+// tokenFromMail достаёт токен из ссылки в последнем отправленном письме
+func tokenFromMail(t *testing.T, mailer *fakeMailer) string {
+	t.Helper()
+
+	require.NotEmpty(t, mailer.sent)
+	link, err := url.Parse(mailLink.FindString(mailer.sent[len(mailer.sent)-1].body))
+	require.NoError(t, err)
+	return link.Query().Get("token")
+}
+
+func TestResetPasswordRevokesSessions(t *testing.T) {
+	auth := newTestAuth(&models.User{
+		ID:                  1,
+		Email:               "ann@example.com",
+		Role:                common.RoleMember,
+		FailedLoginAttempts: 2,
+		LockedUntil:         time.Now().Add(time.Minute),
+	})
+	ctx := context.Background()
+
+	session, err := auth.startSession(ctx, 1, common.RoleMember)
+	require.NoError(t, err)
+
+	require.NoError(t, auth.RequestPasswordReset(ctx, "ann@example.com"))
+	require.NoError(t, auth.ResetPassword(ctx, tokenFromMail(t, auth.mailer), "new-secret"))
+
+	user := auth.users.users[1]
+	assert.NoError(t, bcrypt.CompareHashAndPassword(user.PasswordHash, []byte("new-secret")))
+	assert.Zero(t, user.FailedLoginAttempts)
+	assert.True(t, user.LockedUntil.IsZero())
+	assert.Equal(t, []string{"user.password_reset"}, auth.audit.actions)
+
+	_, err = auth.ValidateToken(ctx, session.AccessToken)
+	assert.ErrorIs(t, err, common.ErrSessionRevoked)
+	_, err = auth.RefreshTokens(ctx, session.RefreshToken)
+	assert.ErrorIs(t, err, common.ErrSessionRevoked)
+}
+
+func TestResetPasswordTokenIsSingleUse(t *testing.T) {
+	auth := newTestAuth(&models.User{ID: 1, Email: "ann@example.com", Role: common.RoleMember})
+	ctx := context.Background()
+
+	require.NoError(t, auth.RequestPasswordReset(ctx, "ann@example.com"))
+	token := tokenFromMail(t, auth.mailer)
+
+	require.NoError(t, auth.ResetPassword(ctx, token, "new-secret"))
+
+	err := auth.ResetPassword(ctx, token, "other-secret")
+	assert.ErrorIs(t, err, common.ErrInvalidInput)
+	assert.NoError(t, bcrypt.CompareHashAndPassword(auth.users.users[1].PasswordHash, []byte("new-secret")))
+}
+
+func TestResetPasswordNewRequestInvalidatesPreviousToken(t *testing.T) {
+	auth := newTestAuth(&models.User{ID: 1, Email: "ann@example.com", Role: common.RoleMember})
+	ctx := context.Background()
+
+	require.NoError(t, auth.RequestPasswordReset(ctx, "ann@example.com"))
+	first := tokenFromMail(t, auth.mailer)
+	require.NoError(t, auth.RequestPasswordReset(ctx, "ann@example.com"))
+
+	assert.ErrorIs(t, auth.ResetPassword(ctx, first, "new-secret"), common.ErrInvalidInput)
+	assert.NoError(t, auth.ResetPassword(ctx, tokenFromMail(t, auth.mailer), "new-secret"))
+}
+
+func TestResetPasswordExpiredToken(t *testing.T) {
+	auth := newTestAuth(&models.User{ID: 1, Email: "ann@example.com", Role: common.RoleMember})
+	ctx := context.Background()
+
+	require.NoError(t, auth.RequestPasswordReset(ctx, "ann@example.com"))
+	auth.tokens.tokens[0].ExpiresAt = time.Now().Add(-time.Second)
+
+	err := auth.ResetPassword(ctx, tokenFromMail(t, auth.mailer), "new-secret")
+	assert.ErrorIs(t, err, common.ErrInvalidInput)
+	assert.Empty(t, auth.audit.actions)
+}
+
+func TestRequestPasswordResetHidesMailerError(t *testing.T) {
+	auth := newTestAuth(&models.User{ID: 1, Email: "ann@example.com", Role: common.RoleMember})
+	auth.mailer.err = errors.New("smtp is down")
+
+	// Ответ не должен отличаться от ответа для неизвестного адреса
+	assert.NoError(t, auth.RequestPasswordReset(context.Background(), "ann@example.com"))
+	assert.NoError(t, auth.RequestPasswordReset(context.Background(), "unknown@example.com"))
+}
+
+func TestVerifyEmailTokenIsSingleUse(t *testing.T) {
+	auth := newTestAuth(&models.User{ID: 1, Email: "ann@example.com", Role: common.RoleMember})
+	ctx := contextWithClaims(1, common.RoleMember)
+
+	require.NoError(t, auth.ResendVerificationEmail(ctx))
+	token := tokenFromMail(t, auth.mailer)
+
+	require.NoError(t, auth.VerifyEmail(ctx, token))
+	assert.False(t, auth.users.users[1].EmailVerifiedAt.IsZero())
+	assert.ErrorIs(t, auth.VerifyEmail(ctx, token), common.ErrInvalidInput)
+}
+
+func TestResendVerificationEmail(t *testing.T) {
+	auth := newTestAuth(
+		&models.User{ID: 1, Email: "ann@example.com", Role: common.RoleMember},
+		&models.User{ID: 2, Email: "bob@example.com", Role: common.RoleMember, EmailVerifiedAt: time.Now()},
+	)
+
+	// Повторное письмо отменяет ссылку из предыдущего
+	require.NoError(t, auth.ResendVerificationEmail(contextWithClaims(1, common.RoleMember)))
+	first := tokenFromMail(t, auth.mailer)
+	require.NoError(t, auth.ResendVerificationEmail(contextWithClaims(1, common.RoleMember)))
+	assert.Equal(t, "ann@example.com", auth.mailer.sent[1].to)
+	assert.ErrorIs(t, auth.VerifyEmail(context.Background(), first), common.ErrInvalidInput)
+
+	// Подтверждённому адресу письмо не отправляется
+	err := auth.ResendVerificationEmail(contextWithClaims(2, common.RoleMember))
+	assert.ErrorIs(t, err, common.ErrInvalidInput)
+	assert.Len(t, auth.mailer.sent, 2)
+}
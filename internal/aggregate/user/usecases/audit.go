@@ -0,0 +1,24 @@
+package usecases
+
+import "context"
+
+// AuditRecorder записывает событие в журнал аудита; вызывается внутри транзакции изменения
+type AuditRecorder interface {
+	Record(ctx context.Context, entityType, action string, entityID uint32, before, after any) error
+}
+
+const (
+	auditEntityUser       = "user"
+	auditEntityInvitation = "invitation"
+
+	auditActionCreate         = "create"
+	auditActionUpdate         = "update"
+	auditActionChangeRole     = "change_role"
+	auditActionChangePassword = "change_password"
+	auditActionDeactivate     = "deactivate"
+	auditActionLock           = "lock"
+	auditActionPasswordReset  = "password_reset"
+	auditActionVerifyEmail    = "verify_email"
+	auditActionAccept         = "accept"
+	auditActionDecline        = "decline"
+)
@@ -1,124 +1,317 @@
 package usecases
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models"
 	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/logger"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Settings - сроки действия токенов и ссылки, которые попадают в письма
+type Settings struct {
+	RefreshTTL           time.Duration
+	ResetTokenTTL        time.Duration
+	VerificationTokenTTL time.Duration
+	InvitationTTL        time.Duration
+	// ResetURL - страница клиента, на которую ведёт ссылка из письма сброса пароля
+	ResetURL string
+	// VerifyURL - адрес GET /verify-email, доступный пользователю
+	VerifyURL string
+	// InviteURL - страница клиента, на которой приглашение принимают или отклоняют
+	InviteURL string
+}
+
 type AuthUseCases struct {
 	repo         AuthRepository
+	sessionRepo  SessionRepository
+	tokenRepo    TokenRepository
+	inviteRepo   InvitationRepository
 	tokenManager TokenManager
+	tx           Transactor
+	mailer       Mailer
+	audit        AuditRecorder
+	policy       Policy
+	settings     Settings
+
+	accountLimiter RateLimiter
+	lockout        Lockout
 }
 
-func NewAuthUseCases(repo AuthRepository, tokenManager TokenManager) *AuthUseCases {
+func NewAuthUseCases(
+	repo AuthRepository,
+	sessionRepo SessionRepository,
+	tokenRepo TokenRepository,
+	inviteRepo InvitationRepository,
+	tokenManager TokenManager,
+	tx Transactor,
+	mailer Mailer,
+	audit AuditRecorder,
+	policy Policy,
+	settings Settings,
+) *AuthUseCases {
 	return &AuthUseCases{
 		repo:         repo,
+		sessionRepo:  sessionRepo,
+		tokenRepo:    tokenRepo,
+		inviteRepo:   inviteRepo,
 		tokenManager: tokenManager,
+		tx:           tx,
+		mailer:       mailer,
+		audit:        audit,
+		policy:       policy,
+		settings:     settings,
 	}
 }
 
+// TokenPair - access-токен и refresh-токен, выданные пользователю
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+}
+
 type CreateUserCommand struct {
 	username string
 	email    string
 	password string
-	role     string
 }
 
 func NewCreateUserCommand(
 	username string,
 	email string,
 	password string,
-	role string,
 ) *CreateUserCommand {
 	return &CreateUserCommand{
 		username: username,
 		email:    email,
 		password: password,
-		role:     role,
 	}
 }
 
-func (a *AuthUseCases) CreateUser(cmd *CreateUserCommand) (string, error) {
-	_, err := a.repo.GetByUsername(cmd.username)
+// CreateUser регистрирует пользователя с ролью member. Роль повышает только администратор.
+func (a *AuthUseCases) CreateUser(ctx context.Context, cmd *CreateUserCommand) (*TokenPair, error) {
+	user, err := a.createUser(ctx, cmd, common.RoleMember)
+	if err != nil {
+		return nil, err
+	}
 
-	switch {
-	case errors.Is(err, common.ErrNotFound):
-	case err == nil:
-		return "", fmt.Errorf("%w: user with username %q already exists", common.ErrAlreadyExists, cmd.username)
-	default:
-		return "", fmt.Errorf("failed to get user by username %q: %w", cmd.username, err)
+	// Письмо можно запросить повторно через POST /me/verify-email, поэтому ошибка отправки не отменяет регистрацию
+	if err := a.sendVerificationEmail(ctx, user); err != nil {
+		logger.FromContext(ctx).Error("failed to send verification email", slog.Any("error", err))
 	}
 
-	_, err = a.repo.GetByEmail(cmd.email)
+	tokens, err := a.startSession(ctx, user.ID, user.Role)
+	if err != nil {
+		return nil, err
+	}
 
-	switch {
-	case errors.Is(err, common.ErrNotFound):
-	case err == nil:
-		return "", fmt.Errorf("%w: user with email %q already exists", common.ErrAlreadyExists, cmd.email)
-	default:
-		return "", fmt.Errorf("failed to get user by email %q: %w", cmd.email, err)
+	return tokens, nil
+}
+
+func (a *AuthUseCases) createUser(ctx context.Context, cmd *CreateUserCommand, role string) (*models.User, error) {
+	if err := a.checkUsernameAvailable(ctx, cmd.username); err != nil {
+		return nil, err
+	}
+
+	if err := a.checkEmailAvailable(ctx, cmd.email); err != nil {
+		return nil, err
 	}
 
 	passwordHash, err := bcrypt.GenerateFromPassword([]byte(cmd.password), bcrypt.DefaultCost)
 
 	if err != nil {
-		return "", fmt.Errorf("failed to hash password: %w", err)
+		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
 	user := &models.User{
 		Username:     cmd.username,
 		Email:        cmd.email,
 		PasswordHash: passwordHash,
-		Role:         cmd.role,
+		Role:         role,
 	}
 
-	userId, err := a.repo.Create(user)
+	userId, err := a.repo.Create(ctx, user)
 	if err != nil {
-		return "", fmt.Errorf("failed to create user: %w", err)
+		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
+	user.ID = userId
 
-	token, err := a.tokenManager.GenerateToken(userId, cmd.role)
+	return user, nil
+}
+
+func (a *AuthUseCases) AuthenticateUser(ctx context.Context, email string, password string) (*TokenPair, error) {
+	if err := a.checkLoginAllowed(ctx, email); err != nil {
+		return nil, err
+	}
+
+	user, err := a.repo.GetByEmail(ctx, email)
+
+	switch {
+	case err == nil:
+	case errors.Is(err, common.ErrNotFound):
+		return nil, common.ErrInvalidCredentials
+	default:
+		return nil, fmt.Errorf("failed to get user by email %q: %w", email, err)
+	}
+
+	// Заблокированной учётной записи пароль не проверяется, чтобы перебор не нагружал bcrypt
+	if err := checkNotLocked(user); err != nil {
+		return nil, err
+	}
+
+	err = bcrypt.CompareHashAndPassword(user.PasswordHash, []byte(password))
+	if err != nil {
+		if err := a.registerLoginFailure(ctx, user); err != nil {
+			return nil, err
+		}
+		return nil, common.ErrInvalidCredentials
+	}
+
+	// Деактивация сообщается только после проверки пароля, чтобы не раскрывать её посторонним
+	if !user.DeactivatedAt.IsZero() {
+		return nil, fmt.Errorf("user %d: %w", user.ID, common.ErrAccountDeactivated)
+	}
+
+	if err := a.resetLoginFailures(ctx, user); err != nil {
+		return nil, err
+	}
+
+	tokens, err := a.startSession(ctx, user.ID, user.Role)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate token: %w", err)
+		return nil, err
 	}
 
-	return token, nil
+	return tokens, nil
 }
 
-func (a *AuthUseCases) AuthenticateUser(email string, password string) (string, error) {
-	user, err := a.repo.GetByEmail(email)
+// RefreshTokens обменивает refresh-токен на новую пару токенов.
+// Повторное использование уже обменянного токена отзывает всё семейство сессий.
+func (a *AuthUseCases) RefreshTokens(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	session, err := a.sessionRepo.GetByTokenHash(ctx, hashToken(refreshToken))
 
 	switch {
 	case err == nil:
 	case errors.Is(err, common.ErrNotFound):
-		return "", common.ErrInvalidCredentials
+		return nil, common.ErrInvalidToken
 	default:
-		return "", fmt.Errorf("failed to get user by email %q: %w", email, err)
+		return nil, fmt.Errorf("failed to get session: %w", err)
 	}
 
-	err = bcrypt.CompareHashAndPassword(user.PasswordHash, []byte(password))
-	if err != nil {
-		return "", common.ErrInvalidCredentials
+	if !session.RevokedAt.IsZero() {
+		return nil, common.ErrSessionRevoked
+	}
+
+	if !session.RotatedAt.IsZero() {
+		if err := a.sessionRepo.RevokeFamily(ctx, session.FamilyID); err != nil {
+			return nil, fmt.Errorf("failed to revoke session family %q: %w", session.FamilyID, err)
+		}
+		return nil, common.ErrSessionRevoked
 	}
 
-	token, err := a.tokenManager.GenerateToken(user.ID, user.Role)
+	if time.Now().After(session.ExpiresAt) {
+		return nil, common.ErrInvalidToken
+	}
+
+	err = a.sessionRepo.MarkRotated(ctx, session.ID)
+
+	switch {
+	case err == nil:
+	case errors.Is(err, common.ErrNotFound):
+		// Токен успели обменять параллельным запросом
+		if err := a.sessionRepo.RevokeFamily(ctx, session.FamilyID); err != nil {
+			return nil, fmt.Errorf("failed to revoke session family %q: %w", session.FamilyID, err)
+		}
+		return nil, common.ErrSessionRevoked
+	default:
+		return nil, fmt.Errorf("failed to rotate session %d: %w", session.ID, err)
+	}
+
+	user, err := a.repo.GetById(ctx, session.UserID)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate token: %w", err)
+		return nil, fmt.Errorf("failed to get user by id %d: %w", session.UserID, err)
+	}
+
+	if !user.DeactivatedAt.IsZero() {
+		return nil, common.ErrSessionRevoked
+	}
+
+	return a.issueTokens(ctx, user.ID, user.Role, session.FamilyID)
+}
+
+// Logout отзывает сессию, к которой относится access-токен.
+func (a *AuthUseCases) Logout(ctx context.Context, sessionID string) error {
+	if err := a.sessionRepo.RevokeFamily(ctx, sessionID); err != nil {
+		return fmt.Errorf("failed to revoke session %q: %w", sessionID, err)
 	}
 
-	return token, nil
+	return nil
 }
 
-func (a *AuthUseCases) ValidateToken(token string) (uint32, string, error) {
+// ValidateToken проверяет access-токен и то, что его семейство сессий не отозвано.
+// Семейство проверяется запросом к базе на каждый запрос без кэша: выход, смена пароля
+// и деактивация действуют сразу, а не по истечении access-токена. Запрос идёт
+// по индексу idx_sessions_family_id.
+func (a *AuthUseCases) ValidateToken(ctx context.Context, token string) (*common.Claims, error) {
 	claims, err := a.tokenManager.ValidateToken(token)
 	if err != nil {
-		return 0, "", fmt.Errorf("failed to validate token: %w", err)
+		return nil, fmt.Errorf("failed to validate token: %w", err)
+	}
+
+	if claims.SessionID == "" {
+		return nil, common.ErrSessionRevoked
+	}
+
+	active, err := a.sessionRepo.IsFamilyActive(ctx, claims.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check session %q: %w", claims.SessionID, err)
+	}
+
+	if !active {
+		return nil, common.ErrSessionRevoked
+	}
+
+	return claims, nil
+}
+
+func (a *AuthUseCases) startSession(ctx context.Context, userID uint32, role string) (*TokenPair, error) {
+	familyID, err := generateSessionFamilyID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate session id: %w", err)
+	}
+
+	return a.issueTokens(ctx, userID, role, familyID)
+}
+
+func (a *AuthUseCases) issueTokens(ctx context.Context, userID uint32, role string, familyID string) (*TokenPair, error) {
+	refreshToken, err := generateToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	session := &models.Session{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: time.Now().Add(a.settings.RefreshTTL),
+	}
+
+	if _, err := a.sessionRepo.Create(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	accessToken, err := a.tokenManager.GenerateToken(userID, role, familyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
 	}
 
-	return claims.UserID, claims.Role, nil
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
 }
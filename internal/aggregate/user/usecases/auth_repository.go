@@ -1,14 +1,25 @@
 package usecases
 
 import (
+	"context"
+	"time"
+
 	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
 )
 
 type AuthRepository interface {
-	Create(user *models.User) (uint32, error)
-	GetById(id uint32) (*models.User, error)
-	GetByUsername(username string) (*models.User, error)
-	GetByEmail(email string) (*models.User, error)
-	DeleteById(id uint32) error
-	Update(user *models.User) error
+	Create(ctx context.Context, user *models.User) (uint32, error)
+	GetById(ctx context.Context, id uint32) (*models.User, error)
+	GetByUsername(ctx context.Context, username string) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	DeleteById(ctx context.Context, id uint32) error
+	Update(ctx context.Context, user *models.User) error
+	RegisterLoginFailure(ctx context.Context, id uint32, threshold int, lockedUntil time.Time) (time.Time, error)
+	ResetLoginFailures(ctx context.Context, id uint32) error
+	UpdatePassword(ctx context.Context, id uint32, passwordHash []byte) error
+	MarkEmailVerified(ctx context.Context, id uint32) error
+	List(ctx context.Context, filter UserFilter, page common.PageRequest) (*common.Page[*models.User], error)
+	Deactivate(ctx context.Context, id uint32) error
+	AddMembership(ctx context.Context, id uint32, teamID uint32, role string) error
 }
@@ -0,0 +1,76 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshTokensRotates(t *testing.T) {
+	auth := newTestAuth(&models.User{ID: 1, Role: common.RoleMember})
+	ctx := context.Background()
+
+	first, err := auth.startSession(ctx, 1, common.RoleMember)
+	require.NoError(t, err)
+
+	second, err := auth.RefreshTokens(ctx, first.RefreshToken)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.RefreshToken, second.RefreshToken)
+
+	// Новая пара принадлежит тому же семейству, и оно остаётся активным
+	assert.Equal(t, first.AccessToken, second.AccessToken)
+	_, err = auth.ValidateToken(ctx, second.AccessToken)
+	assert.NoError(t, err)
+}
+
+func TestRefreshTokensReuseRevokesFamily(t *testing.T) {
+	auth := newTestAuth(&models.User{ID: 1, Role: common.RoleMember})
+	ctx := context.Background()
+
+	first, err := auth.startSession(ctx, 1, common.RoleMember)
+	require.NoError(t, err)
+	second, err := auth.RefreshTokens(ctx, first.RefreshToken)
+	require.NoError(t, err)
+
+	// Обменянный токен предъявлен повторно - считается украденным
+	_, err = auth.RefreshTokens(ctx, first.RefreshToken)
+	assert.ErrorIs(t, err, common.ErrSessionRevoked)
+
+	// Отозвано всё семейство: ни новый refresh-токен, ни access-токен больше не действуют
+	_, err = auth.RefreshTokens(ctx, second.RefreshToken)
+	assert.ErrorIs(t, err, common.ErrSessionRevoked)
+	_, err = auth.ValidateToken(ctx, second.AccessToken)
+	assert.ErrorIs(t, err, common.ErrSessionRevoked)
+}
+
+func TestRefreshTokensUnknownToken(t *testing.T) {
+	auth := newTestAuth()
+
+	_, err := auth.RefreshTokens(context.Background(), "unknown")
+	assert.ErrorIs(t, err, common.ErrInvalidToken)
+}
+
+func TestLogout(t *testing.T) {
+	auth := newTestAuth(&models.User{ID: 1, Role: common.RoleMember})
+	ctx := context.Background()
+
+	current, err := auth.startSession(ctx, 1, common.RoleMember)
+	require.NoError(t, err)
+	other, err := auth.startSession(ctx, 1, common.RoleMember)
+	require.NoError(t, err)
+
+	require.NoError(t, auth.Logout(ctx, current.AccessToken))
+
+	_, err = auth.ValidateToken(ctx, current.AccessToken)
+	assert.ErrorIs(t, err, common.ErrSessionRevoked)
+	_, err = auth.RefreshTokens(ctx, current.RefreshToken)
+	assert.ErrorIs(t, err, common.ErrSessionRevoked)
+
+	// Сессии на других устройствах не затрагиваются
+	_, err = auth.ValidateToken(ctx, other.AccessToken)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,348 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+)
+
+type fakeAuthRepository struct {
+	users  map[uint32]*models.User
+	nextID uint32
+	// memberships - роль пользователя в команде по паре {teamID, userID}
+	memberships map[[2]uint32]string
+}
+
+func newFakeAuthRepository(users ...*models.User) *fakeAuthRepository {
+	r := &fakeAuthRepository{users: map[uint32]*models.User{}, memberships: map[[2]uint32]string{}}
+	for _, user := range users {
+		r.users[user.ID] = user
+		r.nextID = max(r.nextID, user.ID)
+	}
+	return r
+}
+
+func (r *fakeAuthRepository) Create(_ context.Context, user *models.User) (uint32, error) {
+	r.nextID++
+	stored := *user
+	stored.ID = r.nextID
+	r.users[stored.ID] = &stored
+	return stored.ID, nil
+}
+
+func (r *fakeAuthRepository) GetById(_ context.Context, id uint32) (*models.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, common.ErrNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (r *fakeAuthRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	for _, user := range r.users {
+		if user.Username == username {
+			return r.GetById(ctx, user.ID)
+		}
+	}
+	return nil, common.ErrNotFound
+}
+
+func (r *fakeAuthRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	for _, user := range r.users {
+		if strings.EqualFold(user.Email, email) {
+			return r.GetById(ctx, user.ID)
+		}
+	}
+	return nil, common.ErrNotFound
+}
+
+func (r *fakeAuthRepository) DeleteById(_ context.Context, id uint32) error {
+	delete(r.users, id)
+	return nil
+}
+
+func (r *fakeAuthRepository) Update(_ context.Context, user *models.User) error {
+	if _, ok := r.users[user.ID]; !ok {
+		return common.ErrNotFound
+	}
+	stored := *user
+	r.users[user.ID] = &stored
+	return nil
+}
+
+func (r *fakeAuthRepository) RegisterLoginFailure(_ context.Context, id uint32, threshold int, lockedUntil time.Time) (time.Time, error) {
+	user := r.users[id]
+	user.FailedLoginAttempts++
+	if user.FailedLoginAttempts >= threshold {
+		user.FailedLoginAttempts = 0
+		user.LockedUntil = lockedUntil
+	}
+	return user.LockedUntil, nil
+}
+
+func (r *fakeAuthRepository) ResetLoginFailures(_ context.Context, id uint32) error {
+	user := r.users[id]
+	user.FailedLoginAttempts = 0
+	user.LockedUntil = time.Time{}
+	return nil
+}
+
+func (r *fakeAuthRepository) UpdatePassword(_ context.Context, id uint32, passwordHash []byte) error {
+	r.users[id].PasswordHash = passwordHash
+	return nil
+}
+
+func (r *fakeAuthRepository) MarkEmailVerified(_ context.Context, id uint32) error {
+	r.users[id].EmailVerifiedAt = time.Now()
+	return nil
+}
+
+func (r *fakeAuthRepository) List(_ context.Context, _ UserFilter, _ common.PageRequest) (*common.Page[*models.User], error) {
+	return &common.Page[*models.User]{}, nil
+}
+
+func (r *fakeAuthRepository) Deactivate(_ context.Context, id uint32) error {
+	r.users[id].DeactivatedAt = time.Now()
+	return nil
+}
+
+func (r *fakeAuthRepository) AddMembership(_ context.Context, id uint32, teamID uint32, role string) error {
+	key := [2]uint32{teamID, id}
+	if _, ok := r.memberships[key]; !ok {
+		r.memberships[key] = role
+	}
+	return nil
+}
+
+type fakeSessionRepository struct {
+	sessions []*models.Session
+}
+
+func (r *fakeSessionRepository) Create(_ context.Context, session *models.Session) (uint32, error) {
+	stored := *session
+	stored.ID = uint32(len(r.sessions) + 1)
+	r.sessions = append(r.sessions, &stored)
+	return stored.ID, nil
+}
+
+func (r *fakeSessionRepository) GetByTokenHash(_ context.Context, tokenHash []byte) (*models.Session, error) {
+	for _, session := range r.sessions {
+		if bytes.Equal(session.TokenHash, tokenHash) {
+			copied := *session
+			return &copied, nil
+		}
+	}
+	return nil, common.ErrNotFound
+}
+
+func (r *fakeSessionRepository) MarkRotated(_ context.Context, id uint32) error {
+	for _, session := range r.sessions {
+		if session.ID == id && session.RotatedAt.IsZero() {
+			session.RotatedAt = time.Now()
+			return nil
+		}
+	}
+	return common.ErrNotFound
+}
+
+func (r *fakeSessionRepository) RevokeFamily(_ context.Context, familyID string) error {
+	for _, session := range r.sessions {
+		if session.FamilyID == familyID && session.RevokedAt.IsZero() {
+			session.RevokedAt = time.Now()
+		}
+	}
+	return nil
+}
+
+func (r *fakeSessionRepository) RevokeAllForUser(_ context.Context, userID uint32) error {
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.RevokedAt.IsZero() {
+			session.RevokedAt = time.Now()
+		}
+	}
+	return nil
+}
+
+func (r *fakeSessionRepository) IsFamilyActive(_ context.Context, familyID string) (bool, error) {
+	for _, session := range r.sessions {
+		if session.FamilyID == familyID && session.RevokedAt.IsZero() && session.ExpiresAt.After(time.Now()) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type fakeTokenRepository struct {
+	tokens []*models.OneTimeToken
+}
+
+func (r *fakeTokenRepository) Create(_ context.Context, token *models.OneTimeToken) (uint32, error) {
+	stored := *token
+	stored.ID = uint32(len(r.tokens) + 1)
+	r.tokens = append(r.tokens, &stored)
+	return stored.ID, nil
+}
+
+func (r *fakeTokenRepository) Consume(_ context.Context, purpose models.TokenPurpose, tokenHash []byte, now time.Time) (*models.OneTimeToken, error) {
+	for _, token := range r.tokens {
+		if token.Purpose == purpose && bytes.Equal(token.TokenHash, tokenHash) && token.UsedAt.IsZero() && token.ExpiresAt.After(now) {
+			token.UsedAt = now
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, common.ErrNotFound
+}
+
+func (r *fakeTokenRepository) InvalidateForUser(_ context.Context, userID uint32, purpose models.TokenPurpose) error {
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.Purpose == purpose && token.UsedAt.IsZero() {
+			token.UsedAt = time.Now()
+		}
+	}
+	return nil
+}
+
+type fakeInvitationRepository struct {
+	invitations []*models.Invitation
+	// teams - названия команд, не находящихся в корзине
+	teams map[uint32]string
+}
+
+func (r *fakeInvitationRepository) Create(_ context.Context, invitation *models.Invitation) (uint32, error) {
+	stored := *invitation
+	stored.ID = uint32(len(r.invitations) + 1)
+	r.invitations = append(r.invitations, &stored)
+	return stored.ID, nil
+}
+
+func (r *fakeInvitationRepository) RevokePending(_ context.Context, teamID uint32, email string) error {
+	for _, invitation := range r.invitations {
+		if invitation.TeamID == teamID && strings.EqualFold(invitation.Email, email) && invitation.Status == models.InvitationPending {
+			invitation.Status = models.InvitationRevoked
+		}
+	}
+	return nil
+}
+
+func (r *fakeInvitationRepository) Respond(_ context.Context, tokenHash []byte, status models.InvitationStatus, now time.Time) (*models.Invitation, error) {
+	for _, invitation := range r.invitations {
+		if bytes.Equal(invitation.TokenHash, tokenHash) && invitation.Status == models.InvitationPending && invitation.ExpiresAt.After(now) {
+			invitation.Status = status
+			invitation.RespondedAt = now
+			copied := *invitation
+			return &copied, nil
+		}
+	}
+	return nil, common.ErrNotFound
+}
+
+func (r *fakeInvitationRepository) GetByTeam(context.Context, uint32) ([]*models.Invitation, error) {
+	return r.invitations, nil
+}
+
+func (r *fakeInvitationRepository) GetTeamName(_ context.Context, teamID uint32) (string, error) {
+	name, ok := r.teams[teamID]
+	if !ok {
+		return "", common.ErrNotFound
+	}
+	return name, nil
+}
+
+// fakeTokenManager кладёт ID семейства сессий прямо в access-токен
+type fakeTokenManager struct{}
+
+func (fakeTokenManager) GenerateToken(userID uint32, role string, sessionID string) (string, error) {
+	return sessionID, nil
+}
+
+func (fakeTokenManager) ValidateToken(token string) (*common.Claims, error) {
+	return &common.Claims{SessionID: token}, nil
+}
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type sentMail struct {
+	to, subject, body string
+}
+
+type fakeMailer struct {
+	sent []sentMail
+	err  error
+}
+
+func (m *fakeMailer) Send(_ context.Context, to, subject, body string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, sentMail{to: to, subject: subject, body: body})
+	return nil
+}
+
+type fakeAuditRecorder struct {
+	actions []string
+}
+
+func (r *fakeAuditRecorder) Record(_ context.Context, entityType, action string, _ uint32, _, _ any) error {
+	r.actions = append(r.actions, entityType+"."+action)
+	return nil
+}
+
+type allowAllPolicy struct{}
+
+func (allowAllPolicy) Can(context.Context, common.Permission) (bool, error) {
+	return true, nil
+}
+
+func (allowAllPolicy) Authorize(context.Context, common.Permission) error {
+	return nil
+}
+
+func (allowAllPolicy) AuthorizeTeam(context.Context, common.Permission, uint32) error {
+	return nil
+}
+
+type testAuth struct {
+	*AuthUseCases
+	users    *fakeAuthRepository
+	sessions *fakeSessionRepository
+	tokens   *fakeTokenRepository
+	invites  *fakeInvitationRepository
+	mailer   *fakeMailer
+	audit    *fakeAuditRecorder
+}
+
+func newTestAuth(users ...*models.User) *testAuth {
+	t := &testAuth{
+		users:    newFakeAuthRepository(users...),
+		sessions: &fakeSessionRepository{},
+		tokens:   &fakeTokenRepository{},
+		invites:  &fakeInvitationRepository{teams: map[uint32]string{}},
+		mailer:   &fakeMailer{},
+		audit:    &fakeAuditRecorder{},
+	}
+	t.AuthUseCases = NewAuthUseCases(t.users, t.sessions, t.tokens, t.invites, fakeTokenManager{},
+		fakeTransactor{}, t.mailer, t.audit, allowAllPolicy{}, Settings{
+			RefreshTTL:           time.Hour,
+			ResetTokenTTL:        time.Hour,
+			VerificationTokenTTL: time.Hour,
+			InvitationTTL:        time.Hour,
+			ResetURL:             "https://example.com/reset",
+			VerifyURL:            "https://example.com/verify-email",
+			InviteURL:            "https://example.com/invite",
+		})
+	return t
+}
+
+func contextWithClaims(userID uint32, role string) context.Context {
+	return context.WithValue(context.Background(), common.ContextKeyClaims, &common.Claims{UserID: userID, Role: role})
+}
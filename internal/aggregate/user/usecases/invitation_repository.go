@@ -0,0 +1,16 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models"
+)
+
+type InvitationRepository interface {
+	Create(ctx context.Context, invitation *models.Invitation) (uint32, error)
+	RevokePending(ctx context.Context, teamID uint32, email string) error
+	Respond(ctx context.Context, tokenHash []byte, status models.InvitationStatus, now time.Time) (*models.Invitation, error)
+	GetByTeam(ctx context.Context, teamID uint32) ([]*models.Invitation, error)
+	GetTeamName(ctx context.Context, teamID uint32) (string, error)
+}
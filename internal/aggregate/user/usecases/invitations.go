@@ -0,0 +1,239 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/validation"
+)
+
+// Команда для приглашения в команду
+type InviteCommand struct {
+	teamID uint32
+	email  string
+	role   string
+}
+
+func NewInviteCommand(teamID uint32, email string, role string) *InviteCommand {
+	return &InviteCommand{
+		teamID: teamID,
+		email:  email,
+		role:   role,
+	}
+}
+
+// Invite отправляет на email приглашение в команду. Прежнее приглашение на тот же адрес отзывается.
+func (a *AuthUseCases) Invite(ctx context.Context, cmd *InviteCommand) (*models.Invitation, error) {
+	if err := a.policy.AuthorizeTeam(ctx, common.PermTeamManage, cmd.teamID); err != nil {
+		return nil, err
+	}
+
+	if err := validateRole(cmd.role); err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(common.TeamRoles, cmd.role) {
+		if err := a.policy.Authorize(ctx, common.PermRoleAssign); err != nil {
+			return nil, err
+		}
+	}
+
+	teamName, err := a.inviteRepo.GetTeamName(ctx, cmd.teamID)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return nil, fmt.Errorf("team with id %d is not found: %w", cmd.teamID, err)
+		}
+		return nil, fmt.Errorf("failed to get team with id %d: %w", cmd.teamID, err)
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate invitation token: %w", err)
+	}
+
+	claims := ctx.Value(common.ContextKeyClaims).(*common.Claims)
+	now := time.Now()
+	invitation := &models.Invitation{
+		TeamID:    cmd.teamID,
+		Email:     cmd.email,
+		Role:      cmd.role,
+		TokenHash: hashToken(token),
+		InvitedBy: claims.UserID,
+		Status:    models.InvitationPending,
+		ExpiresAt: now.Add(a.settings.InvitationTTL),
+		CreatedAt: now,
+	}
+
+	body := fmt.Sprintf("Hello!\n\n"+
+		"You have been invited to join the team %q as %s. "+
+		"Open the link below to accept or decline the invitation. It is valid for %s.\n\n"+
+		"%s\n",
+		teamName, cmd.role, a.settings.InvitationTTL, withToken(a.settings.InviteURL, token))
+
+	err = a.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := a.inviteRepo.RevokePending(ctx, cmd.teamID, cmd.email); err != nil {
+			return fmt.Errorf("failed to revoke previous invitations to team %d: %w", cmd.teamID, err)
+		}
+
+		id, err := a.inviteRepo.Create(ctx, invitation)
+		if err != nil {
+			return fmt.Errorf("failed to save invitation to team %d: %w", cmd.teamID, err)
+		}
+		invitation.ID = id
+		if err := a.audit.Record(ctx, auditEntityInvitation, auditActionCreate, invitation.ID, nil, invitation); err != nil {
+			return err
+		}
+
+		// Письмо отправляется до фиксации, чтобы без него приглашение не осталось действующим
+		if err := a.mailer.Send(ctx, cmd.email, "Invitation to "+teamName, body); err != nil {
+			return fmt.Errorf("failed to send invitation email: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+// GetInvitations возвращает приглашения в команду teamID
+func (a *AuthUseCases) GetInvitations(ctx context.Context, teamID uint32) ([]*models.Invitation, error) {
+	if err := a.policy.AuthorizeTeam(ctx, common.PermTeamManage, teamID); err != nil {
+		return nil, err
+	}
+
+	invitations, err := a.inviteRepo.GetByTeam(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invitations to team %d: %w", teamID, err)
+	}
+
+	return invitations, nil
+}
+
+// Команда для принятия приглашения. Имя и пароль нужны, только если учётной записи с email приглашения ещё нет.
+type AcceptInvitationCommand struct {
+	token    string
+	username string
+	password string
+}
+
+func NewAcceptInvitationCommand(token string, username string, password string) *AcceptInvitationCommand {
+	return &AcceptInvitationCommand{
+		token:    token,
+		username: username,
+		password: password,
+	}
+}
+
+// AcceptInvitation добавляет пользователя с email приглашения в команду с ролью из приглашения,
+// прежние команды пользователя сохраняются. Роль того, кто уже состоит в команде, не меняется.
+// Если такого пользователя нет, он создаётся с глобальной ролью member и подтверждённым email
+// и получает пару токенов; для существующего возвращается nil.
+func (a *AuthUseCases) AcceptInvitation(ctx context.Context, cmd *AcceptInvitationCommand) (*TokenPair, error) {
+	var (
+		invitation *models.Invitation
+		user       *models.User
+		created    bool
+	)
+
+	err := a.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		invitation, err = a.respondInvitation(ctx, cmd.token, models.InvitationAccepted)
+		if err != nil {
+			return err
+		}
+
+		// Команду могли удалить после отправки приглашения
+		if _, err := a.inviteRepo.GetTeamName(ctx, invitation.TeamID); err != nil {
+			if errors.Is(err, common.ErrNotFound) {
+				return fmt.Errorf("%w: team with id %d no longer exists", common.ErrInvalidInput, invitation.TeamID)
+			}
+			return fmt.Errorf("failed to get team with id %d: %w", invitation.TeamID, err)
+		}
+
+		user, err = a.repo.GetByEmail(ctx, invitation.Email)
+
+		switch {
+		case err == nil:
+			created = false
+			if !user.DeactivatedAt.IsZero() {
+				return fmt.Errorf("user %d: %w", user.ID, common.ErrAccountDeactivated)
+			}
+		case errors.Is(err, common.ErrNotFound):
+			created = true
+			if err := validateNewAccount(cmd); err != nil {
+				return err
+			}
+
+			user, err = a.createUser(ctx, NewCreateUserCommand(cmd.username, invitation.Email, cmd.password), common.RoleMember)
+			if err != nil {
+				return err
+			}
+
+			// Токен пришёл на этот адрес, поэтому отдельное подтверждение не нужно
+			if err := a.repo.MarkEmailVerified(ctx, user.ID); err != nil {
+				return fmt.Errorf("failed to mark email of user %d verified: %w", user.ID, err)
+			}
+		default:
+			return fmt.Errorf("failed to get user by email: %w", err)
+		}
+
+		if err := a.repo.AddMembership(ctx, user.ID, invitation.TeamID, invitation.Role); err != nil {
+			return fmt.Errorf("failed to add user %d to team %d: %w", user.ID, invitation.TeamID, err)
+		}
+		return a.audit.Record(ctx, auditEntityInvitation, auditActionAccept, invitation.ID, nil, invitation)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !created {
+		return nil, nil
+	}
+
+	return a.startSession(ctx, user.ID, user.Role)
+}
+
+// DeclineInvitation отклоняет приглашение
+func (a *AuthUseCases) DeclineInvitation(ctx context.Context, token string) error {
+	return a.tx.WithinTx(ctx, func(ctx context.Context) error {
+		invitation, err := a.respondInvitation(ctx, token, models.InvitationDeclined)
+		if err != nil {
+			return err
+		}
+
+		return a.audit.Record(ctx, auditEntityInvitation, auditActionDecline, invitation.ID, nil, invitation)
+	})
+}
+
+func (a *AuthUseCases) respondInvitation(ctx context.Context, token string, status models.InvitationStatus) (*models.Invitation, error) {
+	invitation, err := a.inviteRepo.Respond(ctx, hashToken(token), status, time.Now())
+
+	switch {
+	case err == nil:
+		return invitation, nil
+	case errors.Is(err, common.ErrNotFound):
+		return nil, fmt.Errorf("%w: invitation is invalid, expired or already answered", common.ErrInvalidInput)
+	default:
+		return nil, fmt.Errorf("failed to respond to invitation: %w", err)
+	}
+}
+
+func validateNewAccount(cmd *AcceptInvitationCommand) error {
+	var errs validation.Errors
+
+	if cmd.username == "" {
+		errs.Add("name", "is required for a new account")
+	}
+	if cmd.password == "" {
+		errs.Add("password", "is required for a new account")
+	}
+
+	return errs.Err()
+}
@@ -0,0 +1,58 @@
+package usecases
+
+import (
+	"testing"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newInviteTestAuth() *testAuth {
+	auth := newTestAuth(
+		&models.User{ID: 1, Email: "ann@example.com", Role: common.RoleManager},
+		&models.User{ID: 2, Email: "bob@example.com", Role: common.RoleMember},
+	)
+	auth.invites.teams[10] = "Platform"
+	return auth
+}
+
+func TestAcceptInvitationAddsMembership(t *testing.T) {
+	auth := newInviteTestAuth()
+	ctx := contextWithClaims(1, common.RoleManager)
+
+	_, err := auth.Invite(ctx, NewInviteCommand(10, "bob@example.com", common.RoleMember))
+	require.NoError(t, err)
+
+	tokens, err := auth.AcceptInvitation(ctx, NewAcceptInvitationCommand(tokenFromMail(t, auth.mailer), "", ""))
+	require.NoError(t, err)
+	assert.Nil(t, tokens)
+	assert.Equal(t, common.RoleMember, auth.users.memberships[[2]uint32{10, 2}])
+	assert.Equal(t, []string{"invitation.create", "invitation.accept"}, auth.audit.actions)
+}
+
+func TestAcceptInvitationToDeletedTeam(t *testing.T) {
+	auth := newInviteTestAuth()
+	ctx := contextWithClaims(1, common.RoleManager)
+
+	_, err := auth.Invite(ctx, NewInviteCommand(10, "bob@example.com", common.RoleMember))
+	require.NoError(t, err)
+
+	// Команда ушла в корзину, пока приглашение ждало ответа
+	delete(auth.invites.teams, 10)
+
+	_, err = auth.AcceptInvitation(ctx, NewAcceptInvitationCommand(tokenFromMail(t, auth.mailer), "", ""))
+	assert.ErrorIs(t, err, common.ErrInvalidInput)
+	assert.Empty(t, auth.users.memberships)
+}
+
+func TestInviteFailsWhenMailIsNotSent(t *testing.T) {
+	auth := newInviteTestAuth()
+	auth.mailer.err = assert.AnError
+	ctx := contextWithClaims(1, common.RoleManager)
+
+	invitation, err := auth.Invite(ctx, NewInviteCommand(10, "bob@example.com", common.RoleMember))
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, invitation)
+}
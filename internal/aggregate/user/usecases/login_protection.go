@@ -0,0 +1,104 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/logger"
+)
+
+// RateLimiter возвращает 0, если запрос по ключу разрешён, иначе время до следующей попытки
+type RateLimiter interface {
+	Allow(ctx context.Context, key string) (time.Duration, error)
+}
+
+// Lockout - блокировка учётной записи на Duration после Threshold неудачных входов подряд.
+// Нулевой Threshold отключает блокировку.
+type Lockout struct {
+	Threshold int
+	Duration  time.Duration
+}
+
+// EnableLoginProtection ограничивает частоту входа в одну учётную запись и включает блокировку
+func (a *AuthUseCases) EnableLoginProtection(accountLimiter RateLimiter, lockout Lockout) {
+	a.accountLimiter = accountLimiter
+	a.lockout = lockout
+}
+
+// checkLoginAllowed проверяет лимит попыток по email до обращения к базе и bcrypt
+func (a *AuthUseCases) checkLoginAllowed(ctx context.Context, email string) error {
+	if a.accountLimiter == nil {
+		return nil
+	}
+
+	wait, err := a.accountLimiter.Allow(ctx, strings.ToLower(email))
+	if err != nil {
+		// Недоступность хранилища лимитов не должна закрывать вход
+		logger.FromContext(ctx).Error("failed to check login rate limit", slog.Any("error", err))
+		return nil
+	}
+	if wait > 0 {
+		return &common.RetryAfterError{RetryAfter: wait}
+	}
+
+	return nil
+}
+
+func checkNotLocked(user *models.User) error {
+	if wait := time.Until(user.LockedUntil); wait > 0 {
+		return fmt.Errorf("user %d is locked: %w", user.ID, &common.RetryAfterError{RetryAfter: wait})
+	}
+	return nil
+}
+
+// registerLoginFailure учитывает неудачный вход и пишет в аудит, если учётная запись заблокирована
+func (a *AuthUseCases) registerLoginFailure(ctx context.Context, user *models.User) error {
+	if a.lockout.Threshold <= 0 {
+		return nil
+	}
+
+	var lockedUntil time.Time
+	err := a.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		lockedUntil, err = a.repo.RegisterLoginFailure(ctx, user.ID, a.lockout.Threshold, time.Now().Add(a.lockout.Duration))
+		if err != nil {
+			return fmt.Errorf("failed to register login failure of user %d: %w", user.ID, err)
+		}
+
+		if !lockedUntil.After(time.Now()) {
+			return nil
+		}
+
+		after := map[string]any{
+			"failed_login_attempts": a.lockout.Threshold,
+			"locked_until":          lockedUntil,
+		}
+		return a.audit.Record(ctx, auditEntityUser, auditActionLock, user.ID, nil, after)
+	})
+	if err != nil {
+		return err
+	}
+
+	if lockedUntil.After(time.Now()) {
+		logger.FromContext(ctx).Warn("user locked after failed login attempts",
+			slog.Uint64("locked_user_id", uint64(user.ID)),
+			slog.Time("locked_until", lockedUntil))
+	}
+	return nil
+}
+
+func (a *AuthUseCases) resetLoginFailures(ctx context.Context, user *models.User) error {
+	if user.FailedLoginAttempts == 0 && user.LockedUntil.IsZero() {
+		return nil
+	}
+
+	if err := a.repo.ResetLoginFailures(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to reset login failures of user %d: %w", user.ID, err)
+	}
+	return nil
+}
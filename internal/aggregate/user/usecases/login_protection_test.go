@@ -0,0 +1,92 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newLoginTestAuth(t *testing.T, user *models.User) *testAuth {
+	t.Helper()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+	user.PasswordHash = hash
+
+	auth := newTestAuth(user)
+	auth.EnableLoginProtection(nil, Lockout{Threshold: 3, Duration: 15 * time.Minute})
+	return auth
+}
+
+func TestLoginLocksAfterThreshold(t *testing.T) {
+	auth := newLoginTestAuth(t, &models.User{ID: 1, Email: "ann@example.com", Role: common.RoleMember})
+	ctx := context.Background()
+
+	for range 2 {
+		_, err := auth.AuthenticateUser(ctx, "ann@example.com", "wrong")
+		assert.ErrorIs(t, err, common.ErrInvalidCredentials)
+	}
+	assert.True(t, auth.users.users[1].LockedUntil.IsZero())
+	assert.Empty(t, auth.audit.actions)
+
+	_, err := auth.AuthenticateUser(ctx, "ann@example.com", "wrong")
+	assert.ErrorIs(t, err, common.ErrInvalidCredentials)
+
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), auth.users.users[1].LockedUntil, time.Minute)
+	assert.Equal(t, []string{"user.lock"}, auth.audit.actions)
+}
+
+func TestLoginLockedAccountSkipsPasswordCheck(t *testing.T) {
+	auth := newLoginTestAuth(t, &models.User{
+		ID:          1,
+		Email:       "ann@example.com",
+		Role:        common.RoleMember,
+		LockedUntil: time.Now().Add(10 * time.Minute),
+	})
+	ctx := context.Background()
+
+	// Даже верный пароль не принимается до окончания блокировки
+	_, err := auth.AuthenticateUser(ctx, "ann@example.com", "secret")
+	assert.ErrorIs(t, err, common.ErrTooManyRequests)
+
+	var retryErr *common.RetryAfterError
+	require.True(t, errors.As(err, &retryErr))
+	assert.InDelta(t, (10 * time.Minute).Seconds(), retryErr.RetryAfter.Seconds(), 5)
+
+	// Неверный пароль тоже не проверяется и не продлевает блокировку
+	_, err = auth.AuthenticateUser(ctx, "ann@example.com", "wrong")
+	assert.ErrorIs(t, err, common.ErrTooManyRequests)
+	assert.Zero(t, auth.users.users[1].FailedLoginAttempts)
+	assert.Empty(t, auth.audit.actions)
+	assert.Empty(t, auth.sessions.sessions)
+}
+
+func TestLoginSuccessResetsFailures(t *testing.T) {
+	auth := newLoginTestAuth(t, &models.User{
+		ID:                  1,
+		Email:               "ann@example.com",
+		Role:                common.RoleMember,
+		FailedLoginAttempts: 2,
+		LockedUntil:         time.Now().Add(-time.Minute),
+	})
+	ctx := context.Background()
+
+	tokens, err := auth.AuthenticateUser(ctx, "ann@example.com", "secret")
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokens.RefreshToken)
+
+	assert.Zero(t, auth.users.users[1].FailedLoginAttempts)
+	assert.True(t, auth.users.users[1].LockedUntil.IsZero())
+
+	// После сброса счётчик начинается заново
+	_, err = auth.AuthenticateUser(ctx, "ann@example.com", "wrong")
+	assert.ErrorIs(t, err, common.ErrInvalidCredentials)
+	assert.Equal(t, 1, auth.users.users[1].FailedLoginAttempts)
+}
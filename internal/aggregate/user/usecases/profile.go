@@ -0,0 +1,174 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/logger"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ProfilePatch - изменяемые поля профиля. nil означает, что поле не меняется.
+type ProfilePatch struct {
+	Username *string
+	Email    *string
+}
+
+// GetMe возвращает профиль текущего пользователя
+func (a *AuthUseCases) GetMe(ctx context.Context) (*models.User, error) {
+	claims := ctx.Value(common.ContextKeyClaims).(*common.Claims)
+	return a.getUser(ctx, claims.UserID)
+}
+
+// UpdateMe меняет имя и email текущего пользователя
+func (a *AuthUseCases) UpdateMe(ctx context.Context, patch ProfilePatch) (*models.User, error) {
+	claims := ctx.Value(common.ContextKeyClaims).(*common.Claims)
+	return a.updateProfile(ctx, claims.UserID, patch)
+}
+
+// ChangePassword меняет пароль текущего пользователя после проверки текущего.
+// Все сессии отзываются, вызывающему выдаётся новая пара токенов.
+func (a *AuthUseCases) ChangePassword(ctx context.Context, currentPassword, newPassword string) (*TokenPair, error) {
+	claims := ctx.Value(common.ContextKeyClaims).(*common.Claims)
+
+	user, err := a.getUser(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Неверный текущий пароль учитывается как неудачный вход, чтобы украденный токен не позволял перебор
+	if err := checkNotLocked(user); err != nil {
+		return nil, err
+	}
+	if err := bcrypt.CompareHashAndPassword(user.PasswordHash, []byte(currentPassword)); err != nil {
+		if err := a.registerLoginFailure(ctx, user); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: current password is incorrect", common.ErrInvalidInput)
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	err = a.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := a.repo.UpdatePassword(ctx, user.ID, passwordHash); err != nil {
+			return fmt.Errorf("failed to update password of user %d: %w", user.ID, err)
+		}
+		if err := a.repo.ResetLoginFailures(ctx, user.ID); err != nil {
+			return fmt.Errorf("failed to reset login failures of user %d: %w", user.ID, err)
+		}
+		if err := a.sessionRepo.RevokeAllForUser(ctx, user.ID); err != nil {
+			return fmt.Errorf("failed to revoke sessions of user %d: %w", user.ID, err)
+		}
+		if err := a.tokenRepo.InvalidateForUser(ctx, user.ID, models.TokenPurposePasswordReset); err != nil {
+			return fmt.Errorf("failed to invalidate reset tokens of user %d: %w", user.ID, err)
+		}
+		return a.audit.Record(ctx, auditEntityUser, auditActionChangePassword, user.ID, nil, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return a.startSession(ctx, user.ID, user.Role)
+}
+
+// updateProfile применяет patch к пользователю id. При смене email отправляется письмо для его подтверждения.
+func (a *AuthUseCases) updateProfile(ctx context.Context, id uint32, patch ProfilePatch) (*models.User, error) {
+	current, err := a.getUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.Username != nil && *patch.Username == "" {
+		return nil, fmt.Errorf("%w: name cannot be empty", common.ErrInvalidInput)
+	}
+	if patch.Email != nil && *patch.Email == "" {
+		return nil, fmt.Errorf("%w: email cannot be empty", common.ErrInvalidInput)
+	}
+
+	updated := *current
+	if patch.Username != nil {
+		updated.Username = *patch.Username
+	}
+	if patch.Email != nil {
+		updated.Email = *patch.Email
+	}
+
+	if updated.Username != current.Username {
+		if err := a.checkUsernameAvailable(ctx, updated.Username); err != nil {
+			return nil, err
+		}
+	}
+
+	emailChanged := updated.Email != current.Email
+	if emailChanged {
+		if err := a.checkEmailAvailable(ctx, updated.Email); err != nil {
+			return nil, err
+		}
+		updated.EmailVerifiedAt = time.Time{}
+	}
+
+	err = a.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := a.repo.Update(ctx, &updated); err != nil {
+			return fmt.Errorf("failed to update user %d: %w", id, err)
+		}
+		return a.audit.Record(ctx, auditEntityUser, auditActionUpdate, id, current, &updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if emailChanged {
+		if err := a.sendVerificationEmail(ctx, &updated); err != nil {
+			logger.FromContext(ctx).Error("failed to send verification email", slog.Any("error", err))
+		}
+	}
+
+	return &updated, nil
+}
+
+func (a *AuthUseCases) getUser(ctx context.Context, id uint32) (*models.User, error) {
+	user, err := a.repo.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return nil, fmt.Errorf("user with id %d is not found: %w", id, err)
+		}
+		return nil, fmt.Errorf("failed to get user by id %d: %w", id, err)
+	}
+
+	return user, nil
+}
+
+func (a *AuthUseCases) checkUsernameAvailable(ctx context.Context, username string) error {
+	_, err := a.repo.GetByUsername(ctx, username)
+
+	switch {
+	case errors.Is(err, common.ErrNotFound):
+		return nil
+	case err == nil:
+		return fmt.Errorf("%w: user with username %q already exists", common.ErrAlreadyExists, username)
+	default:
+		return fmt.Errorf("failed to get user by username %q: %w", username, err)
+	}
+}
+
+func (a *AuthUseCases) checkEmailAvailable(ctx context.Context, email string) error {
+	_, err := a.repo.GetByEmail(ctx, email)
+
+	switch {
+	case errors.Is(err, common.ErrNotFound):
+		return nil
+	case err == nil:
+		return fmt.Errorf("%w: user with email %q already exists", common.ErrAlreadyExists, email)
+	default:
+		return fmt.Errorf("failed to get user by email %q: %w", email, err)
+	}
+}
@@ -0,0 +1,88 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/validation"
+)
+
+// ChangeRole назначает пользователю роль. Роль хранится в access-токене,
+// поэтому сессии пользователя отзываются и новая роль действует после повторного входа.
+func (a *AuthUseCases) ChangeRole(ctx context.Context, id uint32, role string) (*models.User, error) {
+	if err := a.policy.Authorize(ctx, common.PermRoleAssign); err != nil {
+		return nil, err
+	}
+
+	if err := validateRole(role); err != nil {
+		return nil, err
+	}
+
+	claims := ctx.Value(common.ContextKeyClaims).(*common.Claims)
+	if claims.UserID == id {
+		return nil, fmt.Errorf("%w: users cannot change their own role", common.ErrInvalidInput)
+	}
+
+	current, err := a.getUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if current.Role == role {
+		return current, nil
+	}
+
+	updated := *current
+	updated.Role = role
+
+	err = a.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := a.repo.Update(ctx, &updated); err != nil {
+			return fmt.Errorf("failed to update role of user %d: %w", id, err)
+		}
+		if err := a.sessionRepo.RevokeAllForUser(ctx, id); err != nil {
+			return fmt.Errorf("failed to revoke sessions of user %d: %w", id, err)
+		}
+		return a.audit.Record(ctx, auditEntityUser, auditActionChangeRole, id, current, &updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// CreateAdmin создаёт администратора с подтверждённым email. Вызывается из командной строки
+// для начальной настройки, поэтому права вызывающего не проверяются.
+func (a *AuthUseCases) CreateAdmin(ctx context.Context, cmd *CreateUserCommand) (*models.User, error) {
+	var user *models.User
+	err := a.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = a.createUser(ctx, cmd, common.RoleAdmin)
+		if err != nil {
+			return err
+		}
+
+		if err := a.repo.MarkEmailVerified(ctx, user.ID); err != nil {
+			return fmt.Errorf("failed to mark email of user %d verified: %w", user.ID, err)
+		}
+		return a.audit.Record(ctx, auditEntityUser, auditActionCreate, user.ID, nil, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func validateRole(role string) error {
+	if common.IsValidRole(role) {
+		return nil
+	}
+
+	var errs validation.Errors
+	errs.Add("role", "must be one of: "+strings.Join(common.Roles, ", "))
+	return errs.Err()
+}
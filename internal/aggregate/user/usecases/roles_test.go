@@ -0,0 +1,71 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeRole(t *testing.T) {
+	auth := newTestAuth(
+		&models.User{ID: 1, Role: common.RoleAdmin},
+		&models.User{ID: 2, Role: common.RoleMember},
+	)
+	ctx := contextWithClaims(1, common.RoleAdmin)
+
+	session, err := auth.startSession(context.Background(), 2, common.RoleMember)
+	require.NoError(t, err)
+
+	user, err := auth.ChangeRole(ctx, 2, common.RoleViewer)
+	require.NoError(t, err)
+	assert.Equal(t, common.RoleViewer, user.Role)
+	assert.Equal(t, common.RoleViewer, auth.users.users[2].Role)
+	assert.Equal(t, []string{"user.change_role"}, auth.audit.actions)
+
+	// Старая роль записана в токене, поэтому сессии пользователя отозваны
+	_, err = auth.ValidateToken(ctx, session.AccessToken)
+	assert.ErrorIs(t, err, common.ErrSessionRevoked)
+	_, err = auth.RefreshTokens(ctx, session.RefreshToken)
+	assert.ErrorIs(t, err, common.ErrSessionRevoked)
+}
+
+func TestChangeRoleRejectsOwnRole(t *testing.T) {
+	auth := newTestAuth(&models.User{ID: 1, Role: common.RoleAdmin})
+
+	_, err := auth.ChangeRole(contextWithClaims(1, common.RoleAdmin), 1, common.RoleMember)
+	assert.ErrorIs(t, err, common.ErrInvalidInput)
+	assert.Equal(t, common.RoleAdmin, auth.users.users[1].Role)
+}
+
+func TestChangeRoleUnknownRole(t *testing.T) {
+	auth := newTestAuth(
+		&models.User{ID: 1, Role: common.RoleAdmin},
+		&models.User{ID: 2, Role: common.RoleMember},
+	)
+
+	_, err := auth.ChangeRole(contextWithClaims(1, common.RoleAdmin), 2, "owner")
+	assert.ErrorIs(t, err, common.ErrValidation)
+	assert.Equal(t, common.RoleMember, auth.users.users[2].Role)
+	assert.Empty(t, auth.audit.actions)
+}
+
+func TestCreateAdmin(t *testing.T) {
+	auth := newTestAuth()
+
+	user, err := auth.CreateAdmin(context.Background(), NewCreateUserCommand("root", "root@example.com", "secret-password"))
+	require.NoError(t, err)
+
+	stored := auth.users.users[user.ID]
+	assert.Equal(t, common.RoleAdmin, stored.Role)
+	assert.False(t, stored.EmailVerifiedAt.IsZero())
+	assert.Equal(t, []string{"user.create"}, auth.audit.actions)
+
+	// Повторный запуск с тем же email не создаёт второго администратора
+	_, err = auth.CreateAdmin(context.Background(), NewCreateUserCommand("root2", "root@example.com", "secret-password"))
+	assert.ErrorIs(t, err, common.ErrAlreadyExists)
+	assert.Len(t, auth.users.users, 1)
+}
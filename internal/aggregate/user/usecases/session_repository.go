@@ -0,0 +1,16 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models"
+)
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *models.Session) (uint32, error)
+	GetByTokenHash(ctx context.Context, tokenHash []byte) (*models.Session, error)
+	MarkRotated(ctx context.Context, id uint32) error
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeAllForUser(ctx context.Context, userID uint32) error
+	IsFamilyActive(ctx context.Context, familyID string) (bool, error)
+}
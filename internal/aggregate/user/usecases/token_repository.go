@@ -0,0 +1,14 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models"
+)
+
+type TokenRepository interface {
+	Create(ctx context.Context, token *models.OneTimeToken) (uint32, error)
+	Consume(ctx context.Context, purpose models.TokenPurpose, tokenHash []byte, now time.Time) (*models.OneTimeToken, error)
+	InvalidateForUser(ctx context.Context, userID uint32, purpose models.TokenPurpose) error
+}
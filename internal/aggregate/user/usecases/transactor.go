@@ -0,0 +1,9 @@
+package usecases
+
+import "context"
+
+// Transactor выполняет fn в одной транзакции: вызовы репозиториев с переданным в fn ctx
+// фиксируются или откатываются вместе. При конфликте сериализации fn может быть выполнена повторно.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,81 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lunarKettle/task-management-platform-monolith/internal/aggregate/user/models"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+)
+
+// UserSortFields - поля, по которым можно сортировать список пользователей
+var UserSortFields = []string{"id", "username"}
+
+// UserFilter - условия выборки пользователей. Deactivated == nil - без учёта деактивации.
+type UserFilter struct {
+	Role        string
+	Deactivated *bool
+}
+
+func (a *AuthUseCases) ListUsers(ctx context.Context, filter UserFilter, page common.PageRequest) (*common.Page[*models.User], error) {
+	if err := a.policy.Authorize(ctx, common.PermUserManage); err != nil {
+		return nil, err
+	}
+
+	users, err := a.repo.List(ctx, filter, page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	return users, nil
+}
+
+// UpdateUser меняет имя и email любого пользователя
+func (a *AuthUseCases) UpdateUser(ctx context.Context, id uint32, patch ProfilePatch) (*models.User, error) {
+	if err := a.policy.Authorize(ctx, common.PermUserManage); err != nil {
+		return nil, err
+	}
+
+	return a.updateProfile(ctx, id, patch)
+}
+
+// DeactivateUser запрещает пользователю вход и отзывает все его сессии и одноразовые токены
+func (a *AuthUseCases) DeactivateUser(ctx context.Context, id uint32) error {
+	if err := a.policy.Authorize(ctx, common.PermUserManage); err != nil {
+		return err
+	}
+
+	claims := ctx.Value(common.ContextKeyClaims).(*common.Claims)
+	if claims.UserID == id {
+		return fmt.Errorf("%w: users cannot deactivate themselves", common.ErrInvalidInput)
+	}
+
+	current, err := a.getUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !current.DeactivatedAt.IsZero() {
+		return nil
+	}
+
+	err = a.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := a.repo.Deactivate(ctx, id); err != nil {
+			return fmt.Errorf("failed to deactivate user %d: %w", id, err)
+		}
+		if err := a.sessionRepo.RevokeAllForUser(ctx, id); err != nil {
+			return fmt.Errorf("failed to revoke sessions of user %d: %w", id, err)
+		}
+		for _, purpose := range []models.TokenPurpose{models.TokenPurposePasswordReset, models.TokenPurposeEmailVerification} {
+			if err := a.tokenRepo.InvalidateForUser(ctx, id, purpose); err != nil {
+				return fmt.Errorf("failed to invalidate %s tokens of user %d: %w", purpose, id, err)
+			}
+		}
+		return a.audit.Record(ctx, auditEntityUser, auditActionDeactivate, id, nil, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
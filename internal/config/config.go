@@ -0,0 +1,333 @@
+// Package config собирает настройки приложения из значений по умолчанию, флагов,
+// YAML-файла и переменных окружения. Приоритет: окружение > файл > флаги > умолчания.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	Server    ServerConfig    `yaml:"server"`
+	Database  DatabaseConfig  `yaml:"database"`
+	Auth      AuthConfig      `yaml:"auth"`
+	CORS      CORSConfig      `yaml:"cors"`
+	Log       LogConfig       `yaml:"log"`
+	Features  FeaturesConfig  `yaml:"features"`
+	Tracing   TracingConfig   `yaml:"tracing"`
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+	Mail      MailConfig      `yaml:"mail"`
+	Workflow  WorkflowConfig  `yaml:"workflow"`
+}
+
+type ServerConfig struct {
+	Address           string        `yaml:"address"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout"`
+	// RequestTimeout - дедлайн ctx запроса: ограничивает всю обработку, включая запросы к базе,
+	// bcrypt и отправку писем
+	RequestTimeout time.Duration `yaml:"request_timeout"`
+}
+
+type DatabaseConfig struct {
+	ConnectionString string        `yaml:"connection_string"`
+	MaxOpenConns     int           `yaml:"max_open_conns"`
+	MaxIdleConns     int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime  time.Duration `yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime  time.Duration `yaml:"conn_max_idle_time"`
+	PingTimeout      time.Duration `yaml:"ping_timeout"`
+}
+
+type AuthConfig struct {
+	SecretKey       string        `yaml:"secret_key"`
+	AccessTokenTTL  time.Duration `yaml:"access_token_ttl"`
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl"`
+	// LockoutThreshold неудачных входов подряд блокируют учётную запись на LockoutDuration, 0 - без блокировки
+	LockoutThreshold int           `yaml:"lockout_threshold"`
+	LockoutDuration  time.Duration `yaml:"lockout_duration"`
+	// Сроки действия токенов из писем сброса пароля, подтверждения email и приглашений в команду
+	ResetTokenTTL        time.Duration `yaml:"reset_token_ttl"`
+	VerificationTokenTTL time.Duration `yaml:"verification_token_ttl"`
+	InvitationTTL        time.Duration `yaml:"invitation_ttl"`
+}
+
+// MailConfig - отправка писем. Driver: smtp или file; file складывает письма в Dir для локальной разработки.
+type MailConfig struct {
+	Driver       string `yaml:"driver"`
+	From         string `yaml:"from"`
+	Dir          string `yaml:"dir"`
+	SMTPHost     string `yaml:"smtp_host"`
+	SMTPPort     int    `yaml:"smtp_port"`
+	SMTPUsername string `yaml:"smtp_username"`
+	SMTPPassword string `yaml:"smtp_password"`
+	// ResetURL - страница клиента для ввода нового пароля, VerifyURL - публичный адрес GET /verify-email,
+	// InviteURL - страница клиента для принятия или отклонения приглашения
+	ResetURL  string `yaml:"reset_url"`
+	VerifyURL string `yaml:"verify_url"`
+	InviteURL string `yaml:"invite_url"`
+}
+
+// WorkflowConfig - статусы задач и переходы между ними, задаётся только в файле.
+// Пустой Transitions означает встроенный процесс todo → in_progress → review → done.
+type WorkflowConfig struct {
+	Initial     string              `yaml:"initial"`
+	Transitions map[string][]string `yaml:"transitions"`
+}
+
+type CORSConfig struct {
+	AllowedOrigins []string `yaml:"allowed_origins"`
+}
+
+type LogConfig struct {
+	Level string `yaml:"level"`
+}
+
+// RateLimitConfig - лимиты на /login и /register. Лимит с нулевым числом запросов отключён.
+type RateLimitConfig struct {
+	Enabled         bool          `yaml:"enabled"`
+	IPRequests      int           `yaml:"ip_requests"`
+	IPWindow        time.Duration `yaml:"ip_window"`
+	AccountRequests int           `yaml:"account_requests"`
+	AccountWindow   time.Duration `yaml:"account_window"`
+}
+
+type TracingConfig struct {
+	// Exporter: none, otlp или stdout
+	Exporter    string  `yaml:"exporter"`
+	Endpoint    string  `yaml:"endpoint"`
+	ServiceName string  `yaml:"service_name"`
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+type FeaturesConfig struct {
+	// MigrateStrict запрещает запуск сервера, если схема базы отстаёт от миграций
+	MigrateStrict  bool          `yaml:"migrate_strict"`
+	TrashPurge     bool          `yaml:"trash_purge"`
+	TrashRetention time.Duration `yaml:"trash_retention"`
+}
+
+// minSecretKeyLength - минимальная длина ключа подписи токенов HS256
+const minSecretKeyLength = 16
+
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Address:           ":8080",
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       15 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       60 * time.Second,
+			ShutdownTimeout:   20 * time.Second,
+			RequestTimeout:    10 * time.Second,
+		},
+		Database: DatabaseConfig{
+			MaxOpenConns:    25,
+			MaxIdleConns:    25,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+			PingTimeout:     5 * time.Second,
+		},
+		Auth: AuthConfig{
+			AccessTokenTTL:   time.Hour,
+			RefreshTokenTTL:  30 * 24 * time.Hour,
+			LockoutThreshold: 5,
+			LockoutDuration:  15 * time.Minute,
+
+			ResetTokenTTL:        time.Hour,
+			VerificationTokenTTL: 48 * time.Hour,
+			InvitationTTL:        7 * 24 * time.Hour,
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: []string{"*"},
+		},
+		Log: LogConfig{
+			Level: "info",
+		},
+		Features: FeaturesConfig{
+			TrashPurge:     true,
+			TrashRetention: 30 * 24 * time.Hour,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:         true,
+			IPRequests:      20,
+			IPWindow:        time.Minute,
+			AccountRequests: 10,
+			AccountWindow:   10 * time.Minute,
+		},
+		Mail: MailConfig{
+			Driver:    "file",
+			From:      "no-reply@localhost",
+			Dir:       "mail",
+			SMTPPort:  587,
+			ResetURL:  "http://localhost:3000/password/reset",
+			VerifyURL: "http://localhost:8080/verify-email",
+			InviteURL: "http://localhost:3000/invitations",
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			ServiceName: "task-management-platform",
+			SampleRatio: 1,
+		},
+	}
+}
+
+// Validate возвращает все найденные ошибки конфигурации сразу
+func (c *Config) Validate() error {
+	var errs []error
+	add := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.Server.Address == "" {
+		add("server.address is required")
+	}
+	for name, value := range map[string]time.Duration{
+		"server.read_header_timeout":  c.Server.ReadHeaderTimeout,
+		"server.read_timeout":         c.Server.ReadTimeout,
+		"server.write_timeout":        c.Server.WriteTimeout,
+		"server.idle_timeout":         c.Server.IdleTimeout,
+		"server.shutdown_timeout":     c.Server.ShutdownTimeout,
+		"server.request_timeout":      c.Server.RequestTimeout,
+		"database.ping_timeout":       c.Database.PingTimeout,
+		"auth.access_token_ttl":       c.Auth.AccessTokenTTL,
+		"auth.refresh_token_ttl":      c.Auth.RefreshTokenTTL,
+		"auth.reset_token_ttl":        c.Auth.ResetTokenTTL,
+		"auth.verification_token_ttl": c.Auth.VerificationTokenTTL,
+		"auth.invitation_ttl":         c.Auth.InvitationTTL,
+	} {
+		if value <= 0 {
+			add("%s must be positive", name)
+		}
+	}
+
+	if c.Database.ConnectionString == "" {
+		add("database.connection_string is required")
+	}
+	if c.Database.MaxOpenConns < 0 || c.Database.MaxIdleConns < 0 {
+		add("database pool sizes must not be negative")
+	}
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		add("database.max_idle_conns must not exceed database.max_open_conns")
+	}
+
+	if c.Server.RequestTimeout >= c.Server.WriteTimeout {
+		add("server.request_timeout must be shorter than server.write_timeout")
+	}
+
+	if len(c.Auth.SecretKey) < minSecretKeyLength {
+		add("auth.secret_key must be at least %d characters long", minSecretKeyLength)
+	}
+	if c.Auth.RefreshTokenTTL <= c.Auth.AccessTokenTTL {
+		add("auth.refresh_token_ttl must be longer than auth.access_token_ttl")
+	}
+
+	if c.Auth.LockoutThreshold < 0 {
+		add("auth.lockout_threshold must not be negative")
+	}
+	if c.Auth.LockoutThreshold > 0 && c.Auth.LockoutDuration <= 0 {
+		add("auth.lockout_duration must be positive when lockout is enabled")
+	}
+
+	if c.RateLimit.IPRequests < 0 || c.RateLimit.AccountRequests < 0 {
+		add("rate_limit request counts must not be negative")
+	}
+	if c.RateLimit.Enabled && ((c.RateLimit.IPRequests > 0 && c.RateLimit.IPWindow <= 0) ||
+		(c.RateLimit.AccountRequests > 0 && c.RateLimit.AccountWindow <= 0)) {
+		add("rate_limit windows must be positive")
+	}
+
+	if len(c.CORS.AllowedOrigins) == 0 {
+		add("cors.allowed_origins must not be empty")
+	}
+
+	if _, err := c.Log.SlogLevel(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if c.Features.TrashPurge && c.Features.TrashRetention <= 0 {
+		add("features.trash_retention must be positive when trash purge is enabled")
+	}
+
+	switch c.Mail.Driver {
+	case "file":
+		if c.Mail.Dir == "" {
+			add("mail.dir is required for the file driver")
+		}
+	case "smtp":
+		if c.Mail.SMTPHost == "" || c.Mail.SMTPPort <= 0 {
+			add("mail.smtp_host and mail.smtp_port are required for the smtp driver")
+		}
+	default:
+		add("mail.driver %q must be one of smtp, file", c.Mail.Driver)
+	}
+	if c.Mail.From == "" {
+		add("mail.from is required")
+	}
+	for name, link := range map[string]string{
+		"mail.reset_url":  c.Mail.ResetURL,
+		"mail.verify_url": c.Mail.VerifyURL,
+		"mail.invite_url": c.Mail.InviteURL,
+	} {
+		if u, err := url.Parse(link); err != nil || u.Scheme == "" || u.Host == "" {
+			add("%s must be an absolute URL", name)
+		}
+	}
+
+	c.Workflow.validate(add)
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout":
+	case "otlp":
+		if c.Tracing.Endpoint == "" {
+			add("tracing.endpoint is required for the otlp exporter")
+		}
+	default:
+		add("tracing.exporter %q must be one of none, otlp, stdout", c.Tracing.Exporter)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		add("tracing.sample_ratio must be between 0 and 1")
+	}
+
+	return errors.Join(errs...)
+}
+
+// maxTaskStatusLength - длина столбца tasks.status
+const maxTaskStatusLength = 20
+
+func (w WorkflowConfig) validate(add func(format string, args ...any)) {
+	if len(w.Transitions) == 0 {
+		if w.Initial != "" {
+			add("workflow.transitions are required when workflow.initial is set")
+		}
+		return
+	}
+
+	if w.Initial == "" {
+		add("workflow.initial is required when workflow.transitions are set")
+	} else if _, ok := w.Transitions[w.Initial]; !ok {
+		add("workflow.initial %q must have transitions", w.Initial)
+	}
+
+	for from, targets := range w.Transitions {
+		for _, status := range append([]string{from}, targets...) {
+			if status == "" || len(status) > maxTaskStatusLength {
+				add("workflow status %q must be 1 to %d characters long", status, maxTaskStatusLength)
+			}
+		}
+	}
+}
+
+func (l LogConfig) SlogLevel() (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.ToUpper(l.Level))); err != nil {
+		return 0, fmt.Errorf("log.level %q must be one of debug, info, warn, error", l.Level)
+	}
+	return level, nil
+}
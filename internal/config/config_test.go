@@ -0,0 +1,138 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func envMap(values map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	}
+}
+
+func writeFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeFile(t, `
+server:
+  address: ":9000"
+log:
+  level: debug
+`)
+
+	cfg, command, err := Load(
+		[]string{
+			"--config", path,
+			"--server-address", ":7000",
+			"--log-level", "warn",
+			"--access-token-ttl", "30m",
+			"migrate", "up",
+		},
+		envMap(map[string]string{
+			"CONNECTION_STRING": "dbname=test",
+			"SECRET_KEY":        "0123456789abcdef",
+			"LOG_LEVEL":         "error",
+		}),
+	)
+	require.NoError(t, err)
+
+	// Флаг перекрыт файлом, файл - окружением; незатронутые значения остаются из флагов и умолчаний
+	assert.Equal(t, ":9000", cfg.Server.Address)
+	assert.Equal(t, "error", cfg.Log.Level)
+	assert.Equal(t, 30*time.Minute, cfg.Auth.AccessTokenTTL)
+	assert.Equal(t, 30*24*time.Hour, cfg.Auth.RefreshTokenTTL)
+	assert.Equal(t, []string{"migrate", "up"}, command.Args)
+}
+
+func TestLoadListFromEnv(t *testing.T) {
+	cfg, _, err := Load(nil, envMap(map[string]string{
+		"CONNECTION_STRING":    "dbname=test",
+		"SECRET_KEY":           "0123456789abcdef",
+		"CORS_ALLOWED_ORIGINS": "https://a.example, https://b.example",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://a.example", "https://b.example"}, cfg.CORS.AllowedOrigins)
+}
+
+func TestLoadValidates(t *testing.T) {
+	_, _, err := Load(nil, envMap(map[string]string{
+		"SECRET_KEY":             "short",
+		"LOG_LEVEL":              "verbose",
+		"TRACING_EXPORTER":       "otlp",
+		"SERVER_REQUEST_TIMEOUT": "1m",
+	}))
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "database.connection_string is required")
+	assert.ErrorContains(t, err, "auth.secret_key must be at least 16 characters long")
+	assert.ErrorContains(t, err, `log.level "verbose"`)
+	assert.ErrorContains(t, err, "tracing.endpoint is required")
+	assert.ErrorContains(t, err, "server.request_timeout must be shorter than server.write_timeout")
+}
+
+func TestLoadWorkflowFromFile(t *testing.T) {
+	path := writeFile(t, `
+workflow:
+  initial: open
+  transitions:
+    open: [closed]
+    closed: [open]
+`)
+
+	cfg, _, err := Load([]string{"--config", path}, envMap(map[string]string{
+		"CONNECTION_STRING": "dbname=test",
+		"SECRET_KEY":        "0123456789abcdef",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "open", cfg.Workflow.Initial)
+	assert.Equal(t, map[string][]string{"open": {"closed"}, "closed": {"open"}}, cfg.Workflow.Transitions)
+}
+
+func TestValidateWorkflow(t *testing.T) {
+	cfg := Default()
+	cfg.Database.ConnectionString = "dbname=test"
+	cfg.Auth.SecretKey = "0123456789abcdef"
+	cfg.Workflow = WorkflowConfig{
+		Initial:     "new",
+		Transitions: map[string][]string{"open": {"a_status_name_longer_than_twenty"}},
+	}
+
+	err := cfg.Validate()
+	assert.ErrorContains(t, err, `workflow.initial "new" must have transitions`)
+	assert.ErrorContains(t, err, `workflow status "a_status_name_longer_than_twenty" must be 1 to 20 characters long`)
+}
+
+func TestLoadRejectsUnknownFileKeys(t *testing.T) {
+	path := writeFile(t, "server:\n  adress: \":9000\"\n")
+
+	_, _, err := Load([]string{"--config", path}, envMap(nil))
+	assert.ErrorContains(t, err, "failed to parse config file")
+}
+
+func TestPrintRedactsSecrets(t *testing.T) {
+	cfg := Default()
+	cfg.Database.ConnectionString = "user=admin password=admin"
+	cfg.Auth.SecretKey = "0123456789abcdef"
+	cfg.Mail.SMTPPassword = "smtp-secret"
+
+	var out bytes.Buffer
+	require.NoError(t, cfg.Print(&out))
+
+	assert.NotContains(t, out.String(), "password=admin")
+	assert.NotContains(t, out.String(), "0123456789abcdef")
+	assert.NotContains(t, out.String(), "smtp-secret")
+	assert.Contains(t, out.String(), "secret_key: '[REDACTED]'")
+	assert.Contains(t, out.String(), "access_token_ttl: 1h0m0s")
+	assert.Equal(t, "0123456789abcdef", cfg.Auth.SecretKey)
+}
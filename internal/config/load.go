@@ -0,0 +1,245 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ConfigFileEnv  = "CONFIG_FILE"
+	configFileFlag = "config"
+	redacted       = "[REDACTED]"
+)
+
+// Command - то, что осталось после разбора флагов: аргументы подкоманды и запрос --print-config
+type Command struct {
+	Args        []string
+	PrintConfig bool
+}
+
+// binding связывает флаг с переменной окружения, которая его переопределяет
+type binding struct {
+	flag string
+	env  string
+}
+
+type binder struct {
+	fs       *flag.FlagSet
+	bindings []binding
+}
+
+func (b *binder) add(name, env string) {
+	b.bindings = append(b.bindings, binding{flag: name, env: env})
+}
+
+func (b *binder) string(p *string, name, env, usage string) {
+	b.fs.StringVar(p, name, *p, usage)
+	b.add(name, env)
+}
+
+func (b *binder) int(p *int, name, env, usage string) {
+	b.fs.IntVar(p, name, *p, usage)
+	b.add(name, env)
+}
+
+func (b *binder) bool(p *bool, name, env, usage string) {
+	b.fs.BoolVar(p, name, *p, usage)
+	b.add(name, env)
+}
+
+func (b *binder) float64(p *float64, name, env, usage string) {
+	b.fs.Float64Var(p, name, *p, usage)
+	b.add(name, env)
+}
+
+func (b *binder) duration(p *time.Duration, name, env, usage string) {
+	b.fs.DurationVar(p, name, *p, usage)
+	b.add(name, env)
+}
+
+func (b *binder) list(p *[]string, name, env, usage string) {
+	b.fs.Var((*listValue)(p), name, usage)
+	b.add(name, env)
+}
+
+// listValue - список через запятую
+type listValue []string
+
+func (l *listValue) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, ",")
+}
+
+func (l *listValue) Set(value string) error {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	*l = items
+	return nil
+}
+
+func bind(cfg *Config, fs *flag.FlagSet) *binder {
+	b := &binder{fs: fs}
+
+	b.string(&cfg.Server.Address, "server-address", "SERVER_ADDRESS", "HTTP listen address")
+	b.duration(&cfg.Server.ReadHeaderTimeout, "server-read-header-timeout", "SERVER_READ_HEADER_TIMEOUT", "time to read request headers")
+	b.duration(&cfg.Server.ReadTimeout, "server-read-timeout", "SERVER_READ_TIMEOUT", "time to read the whole request")
+	b.duration(&cfg.Server.WriteTimeout, "server-write-timeout", "SERVER_WRITE_TIMEOUT", "time to write the response")
+	b.duration(&cfg.Server.IdleTimeout, "server-idle-timeout", "SERVER_IDLE_TIMEOUT", "keep-alive idle timeout")
+	b.duration(&cfg.Server.ShutdownTimeout, "server-shutdown-timeout", "SERVER_SHUTDOWN_TIMEOUT", "time to drain active requests on shutdown")
+	b.duration(&cfg.Server.RequestTimeout, "server-request-timeout", "SERVER_REQUEST_TIMEOUT", "deadline for handling one request, including database queries")
+
+	b.string(&cfg.Database.ConnectionString, "db-connection-string", "CONNECTION_STRING", "PostgreSQL connection string")
+	b.int(&cfg.Database.MaxOpenConns, "db-max-open-conns", "DB_MAX_OPEN_CONNS", "maximum open connections, 0 is unlimited")
+	b.int(&cfg.Database.MaxIdleConns, "db-max-idle-conns", "DB_MAX_IDLE_CONNS", "maximum idle connections")
+	b.duration(&cfg.Database.ConnMaxLifetime, "db-conn-max-lifetime", "DB_CONN_MAX_LIFETIME", "maximum connection lifetime")
+	b.duration(&cfg.Database.ConnMaxIdleTime, "db-conn-max-idle-time", "DB_CONN_MAX_IDLE_TIME", "maximum connection idle time")
+	b.duration(&cfg.Database.PingTimeout, "db-ping-timeout", "DB_PING_TIMEOUT", "startup database ping timeout")
+
+	b.string(&cfg.Auth.SecretKey, "secret-key", "SECRET_KEY", "token signing key")
+	b.duration(&cfg.Auth.AccessTokenTTL, "access-token-ttl", "ACCESS_TOKEN_TTL", "access token lifetime")
+	b.duration(&cfg.Auth.RefreshTokenTTL, "refresh-token-ttl", "REFRESH_TOKEN_TTL", "refresh token lifetime")
+	b.int(&cfg.Auth.LockoutThreshold, "lockout-threshold", "LOCKOUT_THRESHOLD", "failed logins in a row that lock the account, 0 disables lockout")
+	b.duration(&cfg.Auth.LockoutDuration, "lockout-duration", "LOCKOUT_DURATION", "how long a locked account stays locked")
+
+	b.duration(&cfg.Auth.ResetTokenTTL, "reset-token-ttl", "RESET_TOKEN_TTL", "password reset link lifetime")
+	b.duration(&cfg.Auth.VerificationTokenTTL, "verification-token-ttl", "VERIFICATION_TOKEN_TTL", "email verification link lifetime")
+	b.duration(&cfg.Auth.InvitationTTL, "invitation-ttl", "INVITATION_TTL", "team invitation lifetime")
+
+	b.string(&cfg.Mail.Driver, "mail-driver", "MAIL_DRIVER", "smtp or file")
+	b.string(&cfg.Mail.From, "mail-from", "MAIL_FROM", "sender address")
+	b.string(&cfg.Mail.Dir, "mail-dir", "MAIL_DIR", "directory for the file driver")
+	b.string(&cfg.Mail.SMTPHost, "smtp-host", "SMTP_HOST", "SMTP server host")
+	b.int(&cfg.Mail.SMTPPort, "smtp-port", "SMTP_PORT", "SMTP server port")
+	b.string(&cfg.Mail.SMTPUsername, "smtp-username", "SMTP_USERNAME", "SMTP username, empty disables authentication")
+	b.string(&cfg.Mail.SMTPPassword, "smtp-password", "SMTP_PASSWORD", "SMTP password")
+	b.string(&cfg.Mail.ResetURL, "mail-reset-url", "MAIL_RESET_URL", "client page that completes a password reset")
+	b.string(&cfg.Mail.VerifyURL, "mail-verify-url", "MAIL_VERIFY_URL", "public URL of GET /verify-email")
+	b.string(&cfg.Mail.InviteURL, "mail-invite-url", "MAIL_INVITE_URL", "client page that accepts or declines a team invitation")
+
+	b.bool(&cfg.RateLimit.Enabled, "rate-limit", "RATE_LIMIT_ENABLED", "limit request rate on /login and /register")
+	b.int(&cfg.RateLimit.IPRequests, "rate-limit-ip-requests", "RATE_LIMIT_IP_REQUESTS", "requests per window from one IP address")
+	b.duration(&cfg.RateLimit.IPWindow, "rate-limit-ip-window", "RATE_LIMIT_IP_WINDOW", "window of the per-IP limit")
+	b.int(&cfg.RateLimit.AccountRequests, "rate-limit-account-requests", "RATE_LIMIT_ACCOUNT_REQUESTS", "login attempts per window for one account")
+	b.duration(&cfg.RateLimit.AccountWindow, "rate-limit-account-window", "RATE_LIMIT_ACCOUNT_WINDOW", "window of the per-account limit")
+
+	b.list(&cfg.CORS.AllowedOrigins, "cors-allowed-origins", "CORS_ALLOWED_ORIGINS", "comma-separated allowed origins, * allows any")
+
+	b.string(&cfg.Log.Level, "log-level", "LOG_LEVEL", "debug, info, warn or error")
+
+	b.bool(&cfg.Features.MigrateStrict, "migrate-strict", "MIGRATE_STRICT", "refuse to start when the schema is behind")
+	b.bool(&cfg.Features.TrashPurge, "trash-purge", "TRASH_PURGE", "purge deleted records periodically")
+	b.duration(&cfg.Features.TrashRetention, "trash-retention", "TRASH_RETENTION", "how long deleted records are kept")
+
+	b.string(&cfg.Tracing.Exporter, "tracing-exporter", "TRACING_EXPORTER", "none, otlp or stdout")
+	b.string(&cfg.Tracing.Endpoint, "tracing-endpoint", "TRACING_ENDPOINT", "OTLP/HTTP collector endpoint, host:port")
+	b.string(&cfg.Tracing.ServiceName, "tracing-service-name", "TRACING_SERVICE_NAME", "service.name resource attribute")
+	b.float64(&cfg.Tracing.SampleRatio, "tracing-sample-ratio", "TRACING_SAMPLE_RATIO", "fraction of traces to sample, from 0 to 1")
+
+	return b
+}
+
+// Load собирает конфигурацию и проверяет её. args - аргументы командной строки без имени программы,
+// lookupEnv - обычно os.LookupEnv.
+func Load(args []string, lookupEnv func(string) (string, bool)) (*Config, *Command, error) {
+	cfg := Default()
+	command := &Command{}
+
+	fs := flag.NewFlagSet("main", flag.ContinueOnError)
+	b := bind(cfg, fs)
+
+	configFile := fs.String(configFileFlag, "", "path to a YAML config file")
+	fs.BoolVar(&command.PrintConfig, "print-config", false, "print the effective config with secrets redacted and exit")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse flags: %w", err)
+	}
+	command.Args = fs.Args()
+
+	path := *configFile
+	if value, ok := lookupEnv(ConfigFileEnv); ok && value != "" {
+		path = value
+	}
+	if path != "" {
+		if err := loadFile(cfg, path); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, binding := range b.bindings {
+		value, ok := lookupEnv(binding.env)
+		if !ok {
+			continue
+		}
+		if err := fs.Lookup(binding.flag).Value.Set(value); err != nil {
+			return nil, nil, fmt.Errorf("invalid %s: %w", binding.env, err)
+		}
+	}
+
+	// Для --print-config проверка выполняется после вывода, чтобы можно было увидеть ошибочные значения
+	if command.PrintConfig {
+		return cfg, command, nil
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return cfg, command, nil
+}
+
+func loadFile(cfg *Config, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Redacted возвращает копию конфигурации, в которой скрыты секреты
+func (c *Config) Redacted() *Config {
+	copied := *c
+	copied.CORS.AllowedOrigins = append([]string(nil), c.CORS.AllowedOrigins...)
+
+	if copied.Database.ConnectionString != "" {
+		copied.Database.ConnectionString = redacted
+	}
+	if copied.Auth.SecretKey != "" {
+		copied.Auth.SecretKey = redacted
+	}
+	if copied.Mail.SMTPPassword != "" {
+		copied.Mail.SMTPPassword = redacted
+	}
+	return &copied
+}
+
+// Print выводит конфигурацию в YAML без секретов
+func (c *Config) Print(w io.Writer) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(c.Redacted()); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	return encoder.Close()
+}
@@ -0,0 +1,211 @@
+// Package database оборачивает database/sql и создаёт span OpenTelemetry на каждый запрос.
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName = "github.com/lunarKettle/task-management-platform-monolith/internal/database"
+	dbSystem   = "postgresql"
+)
+
+// Querier - общие методы DB и Tx, которыми пользуются репозитории. Им же удовлетворяют *sql.DB и *sql.Tx.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+type DB struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *DB {
+	return &DB{db: db}
+}
+
+// SQL возвращает исходный пул соединений
+func (d *DB) SQL() *sql.DB {
+	return d.db
+}
+
+func (d *DB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	ctx, span := startSpan(ctx, query)
+	defer span.End()
+
+	rows, err := d.conn(ctx).QueryContext(ctx, query, args...)
+	recordError(span, err)
+	return rows, err
+}
+
+func (d *DB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, span := startSpan(ctx, query)
+	defer span.End()
+
+	row := d.conn(ctx).QueryRowContext(ctx, query, args...)
+	recordError(span, row.Err())
+	return row
+}
+
+func (d *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := startSpan(ctx, query)
+	defer span.End()
+
+	result, err := d.conn(ctx).ExecContext(ctx, query, args...)
+	recordError(span, err)
+	return result, err
+}
+
+// BeginTx начинает транзакцию. Если ctx уже внутри Transactor.WithinTx, вместо новой
+// транзакции создаётся точка сохранения: Commit и Rollback тогда затрагивают только её,
+// а фиксирует всё Transactor.
+func (d *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	if outer := d.txFromContext(ctx); outer != nil {
+		return outer.savepoint(ctx)
+	}
+
+	spanCtx, span := startSpan(ctx, "BEGIN")
+	defer span.End()
+
+	tx, err := d.db.BeginTx(spanCtx, opts)
+	recordError(span, err)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{tx: tx, db: d.db, ctx: ctx, savepoints: new(int)}, nil
+}
+
+// conn возвращает транзакцию из ctx, если она открыта на этом же пуле, иначе сам пул
+func (d *DB) conn(ctx context.Context) Querier {
+	if tx := d.txFromContext(ctx); tx != nil {
+		return tx.tx
+	}
+	return d.db
+}
+
+func (d *DB) txFromContext(ctx context.Context) *Tx {
+	if tx, ok := ctx.Value(txKey{}).(*Tx); ok && tx.db == d.db {
+		return tx
+	}
+	return nil
+}
+
+// Tx - транзакция, запросы которой также попадают в трассировку.
+// ctx начала транзакции нужен, чтобы COMMIT и ROLLBACK попали в тот же trace.
+type Tx struct {
+	tx  *sql.Tx
+	db  *sql.DB
+	ctx context.Context
+
+	// name - имя точки сохранения для вложенной транзакции, пустое у внешней
+	name       string
+	savepoints *int
+}
+
+func (t *Tx) savepoint(ctx context.Context) (*Tx, error) {
+	*t.savepoints++
+	nested := &Tx{
+		tx:         t.tx,
+		db:         t.db,
+		ctx:        ctx,
+		name:       fmt.Sprintf("sp_%d", *t.savepoints),
+		savepoints: t.savepoints,
+	}
+
+	if _, err := nested.ExecContext(ctx, "SAVEPOINT "+nested.name); err != nil {
+		return nil, err
+	}
+	return nested, nil
+}
+
+func (t *Tx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	ctx, span := startSpan(ctx, query)
+	defer span.End()
+
+	rows, err := t.tx.QueryContext(ctx, query, args...)
+	recordError(span, err)
+	return rows, err
+}
+
+func (t *Tx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, span := startSpan(ctx, query)
+	defer span.End()
+
+	row := t.tx.QueryRowContext(ctx, query, args...)
+	recordError(span, row.Err())
+	return row
+}
+
+func (t *Tx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := startSpan(ctx, query)
+	defer span.End()
+
+	result, err := t.tx.ExecContext(ctx, query, args...)
+	recordError(span, err)
+	return result, err
+}
+
+func (t *Tx) Commit() error {
+	if t.name != "" {
+		_, err := t.ExecContext(t.ctx, "RELEASE SAVEPOINT "+t.name)
+		return err
+	}
+
+	_, span := startSpan(t.ctx, "COMMIT")
+	defer span.End()
+
+	err := t.tx.Commit()
+	recordError(span, err)
+	return err
+}
+
+func (t *Tx) Rollback() error {
+	if t.name != "" {
+		_, err := t.ExecContext(t.ctx, "ROLLBACK TO SAVEPOINT "+t.name)
+		return err
+	}
+
+	_, span := startSpan(t.ctx, "ROLLBACK")
+	defer span.End()
+
+	err := t.tx.Rollback()
+	if err != sql.ErrTxDone {
+		recordError(span, err)
+	}
+	return err
+}
+
+func startSpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, spanName(query),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", dbSystem),
+			attribute.String("db.statement", query),
+		),
+	)
+}
+
+// spanName - первое слово запроса: SELECT, INSERT и т.д.
+func spanName(query string) string {
+	if fields := strings.Fields(query); len(fields) > 0 {
+		return "db " + strings.ToUpper(fields[0])
+	}
+	return "db query"
+}
+
+func recordError(span trace.Span, err error) {
+	if err == nil || err == sql.ErrNoRows {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,142 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func setupTracing(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	return exporter
+}
+
+func attributeValue(span tracetest.SpanStub, key attribute.Key) string {
+	for _, attr := range span.Attributes {
+		if attr.Key == key {
+			return attr.Value.AsString()
+		}
+	}
+	return ""
+}
+
+func TestQuerySpans(t *testing.T) {
+	exporter := setupTracing(t)
+
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	db := New(sqlDB)
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "GET /teams")
+
+	mock.ExpectQuery(`SELECT id FROM teams`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(`SELECT name FROM teams WHERE id = \$1`).
+		WithArgs(2).
+		WillReturnError(sql.ErrNoRows)
+
+	rows, err := db.QueryContext(ctx, "SELECT id FROM teams")
+	require.NoError(t, err)
+	rows.Close()
+
+	var name string
+	err = db.QueryRowContext(ctx, "SELECT name FROM teams WHERE id = $1", 2).Scan(&name)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	parent.End()
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+
+	for _, span := range spans[:2] {
+		assert.Equal(t, "db SELECT", span.Name)
+		assert.Equal(t, "postgresql", attributeValue(span, "db.system"))
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent.SpanID())
+		// Отсутствие строки - не ошибка запроса
+		assert.Equal(t, codes.Unset, span.Status.Code)
+	}
+	assert.Equal(t, "SELECT id FROM teams", attributeValue(spans[0], "db.statement"))
+	assert.Equal(t, "SELECT name FROM teams WHERE id = $1", attributeValue(spans[1], "db.statement"))
+}
+
+func TestTxSpans(t *testing.T) {
+	exporter := setupTracing(t)
+
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	db := New(sqlDB)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE tasks`).WillReturnError(assert.AnError)
+	mock.ExpectRollback()
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "DeleteProject")
+
+	tx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+
+	_, err = tx.ExecContext(ctx, "UPDATE tasks SET deleted_at = NOW()")
+	assert.ErrorIs(t, err, assert.AnError)
+	require.NoError(t, tx.Rollback())
+
+	parent.End()
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 4)
+
+	names := make([]string, 0, len(spans))
+	for _, span := range spans[:3] {
+		names = append(names, span.Name)
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent.SpanID())
+	}
+	assert.Equal(t, []string{"db BEGIN", "db UPDATE", "db ROLLBACK"}, names)
+	assert.Equal(t, codes.Error, spans[1].Status.Code)
+}
+
+func TestQueryDeadline(t *testing.T) {
+	exporter := setupTracing(t)
+
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	db := New(sqlDB)
+
+	mock.ExpectExec(`DELETE FROM tasks`).
+		WillDelayFor(time.Second).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = db.ExecContext(ctx, "DELETE FROM tasks WHERE deleted_at < $1")
+	// Драйвер возвращает собственную ошибку отмены, причину видно по ctx
+	assert.Error(t, err)
+	assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+}
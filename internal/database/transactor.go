@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/logger"
+)
+
+// txKey - ключ контекста, под которым Transactor хранит открытую транзакцию
+type txKey struct{}
+
+const (
+	defaultMaxAttempts = 3
+	retryBaseDelay     = 10 * time.Millisecond
+)
+
+// Коды ошибок PostgreSQL, после которых транзакцию можно безопасно повторить
+const (
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+)
+
+// Transactor выполняет несколько вызовов репозиториев в одной транзакции.
+// Транзакция передаётся через ctx: все DB, созданные поверх того же пула,
+// выполняют запросы в ней, если получают этот ctx.
+type Transactor struct {
+	db          *sql.DB
+	maxAttempts int
+}
+
+func NewTransactor(db *sql.DB) *Transactor {
+	return &Transactor{db: db, maxAttempts: defaultMaxAttempts}
+}
+
+// WithinTx выполняет fn в serializable-транзакции и фиксирует её, если fn не вернула ошибку.
+// При конфликте сериализации или взаимной блокировке fn выполняется заново, поэтому
+// она не должна иметь побочных эффектов вне базы. Вложенный вызов присоединяется к внешней транзакции.
+func (t *Transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if tx, ok := ctx.Value(txKey{}).(*Tx); ok && tx.db == t.db {
+		return fn(ctx)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := t.run(ctx, fn)
+		if err == nil || !IsRetryable(err) || attempt == t.maxAttempts {
+			return err
+		}
+
+		delay := retryBaseDelay*time.Duration(1<<(attempt-1)) + rand.N(retryBaseDelay)
+		logger.FromContext(ctx).Warn("retrying transaction",
+			slog.Int("attempt", attempt),
+			slog.Duration("delay", delay),
+			slog.Any("error", err))
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (t *Transactor) run(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	tx, err := New(t.db).BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	return fn(context.WithValue(ctx, txKey{}, tx))
+}
+
+// IsRetryable сообщает, что транзакция прервана из-за конкурентного доступа и её можно повторить
+func IsRetryable(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == serializationFailure || pqErr.Code == deadlockDetected
+}
@@ -0,0 +1,96 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithinTxRetriesSerializationFailure(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	db := New(sqlDB)
+	transactor := NewTransactor(sqlDB)
+
+	// Первая попытка натыкается на конфликт сериализации, вторая проходит
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE teams`).WillReturnError(&pq.Error{Code: serializationFailure})
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE teams`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	attempts := 0
+	err = transactor.WithinTx(context.Background(), func(ctx context.Context) error {
+		attempts++
+		_, err := db.ExecContext(ctx, "UPDATE teams SET name = $1", "Team")
+		return err
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithinTxDoesNotRetryOtherErrors(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	transactor := NewTransactor(sqlDB)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	attempts := 0
+	err = transactor.WithinTx(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return assert.AnError
+	})
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 1, attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNestedTxUsesSavepoints(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	db := New(sqlDB)
+	transactor := NewTransactor(sqlDB)
+
+	// Транзакции репозитория внутри WithinTx превращаются в точки сохранения
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT sp_1`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO teams`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`RELEASE SAVEPOINT sp_1`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SAVEPOINT sp_2`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`ROLLBACK TO SAVEPOINT sp_2`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err = transactor.WithinTx(context.Background(), func(ctx context.Context) error {
+		tx, err := db.BeginTx(ctx, nil)
+		require.NoError(t, err)
+		_, err = tx.ExecContext(ctx, "INSERT INTO teams (name) VALUES ($1)", "Team")
+		require.NoError(t, err)
+		require.NoError(t, tx.Commit())
+
+		tx, err = db.BeginTx(ctx, nil)
+		require.NoError(t, err)
+		require.NoError(t, tx.Rollback())
+
+		// Вложенный WithinTx присоединяется к внешней транзакции
+		return transactor.WithinTx(ctx, func(context.Context) error { return nil })
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,78 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FileMailer сохраняет каждое письмо в отдельный .eml файл каталога dir
+type FileMailer struct {
+	dir  string
+	from string
+	now  func() time.Time
+
+	mu  sync.Mutex
+	seq int
+}
+
+func NewFileMailer(dir, from string) *FileMailer {
+	return &FileMailer{dir: dir, from: from, now: time.Now}
+}
+
+func (m *FileMailer) Send(_ context.Context, to, subject, body string) error {
+	msg, err := newMessage(to, subject, body)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create mail directory: %w", err)
+	}
+
+	m.mu.Lock()
+	m.seq++
+	seq := m.seq
+	m.mu.Unlock()
+
+	now := m.now()
+	name := fmt.Sprintf("%s-%03d.eml", now.Format("20060102T150405.000000000"), seq)
+	path := filepath.Join(m.dir, name)
+
+	if err := os.WriteFile(path, format(m.from, msg, now), 0o644); err != nil {
+		return fmt.Errorf("failed to write mail file: %w", err)
+	}
+	return nil
+}
+
+// MemoryMailer хранит отправленные письма в памяти
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(_ context.Context, to, subject, body string) error {
+	msg, err := newMessage(to, subject, body)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+// Messages возвращает копию отправленных писем в порядке отправки
+func (m *MemoryMailer) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.messages...)
+}
@@ -0,0 +1,60 @@
+// Package mailer отправляет письма пользователям: через SMTP в рабочем окружении,
+// в файлы или в память - при локальной разработке и в тестах.
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Message - простое текстовое письмо
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer - общий интерфейс всех реализаций
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// format собирает письмо в формате RFC 5322
+func format(from string, msg Message, date time.Time) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// validateHeader не даёт подставить дополнительные заголовки через адрес или тему
+func validateHeader(name, value string) error {
+	if strings.ContainsAny(value, "\r\n") {
+		return fmt.Errorf("mail header %s must not contain line breaks", name)
+	}
+	return nil
+}
+
+func newMessage(to, subject, body string) (Message, error) {
+	if err := validateHeader("To", to); err != nil {
+		return Message{}, err
+	}
+	if err := validateHeader("Subject", subject); err != nil {
+		return Message{}, err
+	}
+	return Message{To: to, Subject: subject, Body: body}, nil
+}
+
+var (
+	_ Mailer = (*SMTPMailer)(nil)
+	_ Mailer = (*FileMailer)(nil)
+	_ Mailer = (*MemoryMailer)(nil)
+)
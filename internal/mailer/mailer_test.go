@@ -0,0 +1,45 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileMailer(t *testing.T) {
+	dir := t.TempDir()
+	mailer := NewFileMailer(dir, "noreply@example.com")
+	mailer.now = func() time.Time { return time.Date(2025, time.January, 12, 10, 0, 0, 0, time.UTC) }
+
+	require.NoError(t, mailer.Send(context.Background(), "alice@example.com", "Reset", "Line 1\nLine 2"))
+	require.NoError(t, mailer.Send(context.Background(), "bob@example.com", "Verify", "Body"))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	content, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+
+	text := string(content)
+	assert.Contains(t, text, "From: noreply@example.com\r\n")
+	assert.Contains(t, text, "To: alice@example.com\r\n")
+	assert.Contains(t, text, "Subject: Reset\r\n")
+	assert.True(t, strings.HasSuffix(text, "\r\n\r\nLine 1\r\nLine 2"))
+}
+
+func TestMemoryMailerRejectsHeaderInjection(t *testing.T) {
+	mailer := NewMemoryMailer()
+
+	err := mailer.Send(context.Background(), "alice@example.com\r\nBcc: eve@example.com", "Reset", "Body")
+	assert.Error(t, err)
+
+	require.NoError(t, mailer.Send(context.Background(), "alice@example.com", "Reset", "Body"))
+	assert.Equal(t, []Message{{To: "alice@example.com", Subject: "Reset", Body: "Body"}}, mailer.Messages())
+}
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+
+	server := httptest.NewServer(m.Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestScrapeExposesRequestMetrics(t *testing.T) {
+	m := New()
+	m.ObserveRequest(http.MethodGet, "GET /projects/{id}", http.StatusOK, 30*time.Millisecond)
+	m.ObserveRequest(http.MethodGet, "GET /projects/{id}", http.StatusNotFound, 10*time.Millisecond)
+	m.ObserveRequest(http.MethodGet, "", http.StatusNotFound, time.Millisecond)
+
+	body := scrape(t, m)
+
+	assert.Contains(t, body, `task_platform_http_requests_total{method="GET",route="GET /projects/{id}",status="200"} 1`)
+	assert.Contains(t, body, `task_platform_http_requests_total{method="GET",route="GET /projects/{id}",status="404"} 1`)
+	assert.Contains(t, body, `task_platform_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, body, `task_platform_http_request_duration_seconds_count{method="GET",route="GET /projects/{id}"} 2`)
+}
+
+func TestScrapeExposesDomainAndPoolMetrics(t *testing.T) {
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	m := New()
+	m.RegisterDB(db, "postgres")
+	m.ProjectCreated()
+	m.TaskCreated()
+	m.TaskCreated()
+	m.TaskCompleted()
+
+	body := scrape(t, m)
+
+	assert.Contains(t, body, "task_platform_projects_created_total 1")
+	assert.Contains(t, body, "task_platform_tasks_created_total 2")
+	assert.Contains(t, body, "task_platform_tasks_completed_total 1")
+	assert.Contains(t, body, `go_sql_open_connections{db_name="postgres"}`)
+}
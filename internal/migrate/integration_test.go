@@ -0,0 +1,56 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/lunarKettle/task-management-platform-monolith/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/lib/pq"
+)
+
+// TestMigrationsRoundTrip применяет и откатывает все миграции на одноразовой базе.
+// Запуск: TEST_DATABASE_URL="postgres://..." go test ./internal/migrate/
+func TestMigrationsRoundTrip(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	defer db.Close()
+
+	list, err := Load(migrations.FS)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	migrator := NewMigrator(db, list)
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, applied, len(list))
+
+	pending, err := migrator.Pending(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	_, err = migrator.Redo(ctx)
+	require.NoError(t, err)
+
+	reverted, err := migrator.Down(ctx, len(list))
+	require.NoError(t, err)
+	assert.Len(t, reverted, len(list))
+
+	// Повторное применение проверяет, что откаты не оставили следов
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, applied, len(list))
+
+	_, err = migrator.Down(ctx, len(list))
+	require.NoError(t, err)
+}
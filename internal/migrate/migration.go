@@ -0,0 +1,87 @@
+package migrate
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+const (
+	upSuffix   = ".up.pgsql"
+	downSuffix = ".down.pgsql"
+)
+
+// Migration - пара скриптов применения и отката одной версии схемы
+type Migration struct {
+	Version string
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Load читает миграции из fsys и сортирует их по версии.
+// Каждой версии должны соответствовать ровно один up- и один down-файл.
+func Load(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "*.pgsql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	byVersion := make(map[string]*Migration)
+	for _, file := range files {
+		var (
+			stem string
+			up   bool
+		)
+
+		switch {
+		case strings.HasSuffix(file, upSuffix):
+			stem, up = strings.TrimSuffix(file, upSuffix), true
+		case strings.HasSuffix(file, downSuffix):
+			stem = strings.TrimSuffix(file, downSuffix)
+		default:
+			return nil, fmt.Errorf("migration %s must end with %s or %s", file, upSuffix, downSuffix)
+		}
+
+		version, name, ok := strings.Cut(stem, "_")
+		if !ok || version == "" || name == "" {
+			return nil, fmt.Errorf("migration %s must be named <version>_<name>", file)
+		}
+
+		content, err := fs.ReadFile(fsys, path.Clean(file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", file, err)
+		}
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		}
+		if migration.Name != name {
+			return nil, fmt.Errorf("version %s is used by both %s and %s", version, migration.Name, name)
+		}
+
+		if up {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %s_%s must have both up and down scripts", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
@@ -0,0 +1,54 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/lunarKettle/task-management-platform-monolith/migrations"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadSortsByVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"20240102_add_column.up.pgsql":     {Data: []byte("ALTER TABLE a ADD COLUMN b INT;")},
+		"20240102_add_column.down.pgsql":   {Data: []byte("ALTER TABLE a DROP COLUMN b;")},
+		"20240101_create_table.up.pgsql":   {Data: []byte("CREATE TABLE a (id INT);")},
+		"20240101_create_table.down.pgsql": {Data: []byte("DROP TABLE a;")},
+	}
+
+	list, err := Load(fsys)
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, "20240101", list[0].Version)
+	assert.Equal(t, "create_table", list[0].Name)
+	assert.Equal(t, "DROP TABLE a;", list[0].Down)
+	assert.Equal(t, "20240102", list[1].Version)
+}
+
+func TestLoadRequiresDownScript(t *testing.T) {
+	fsys := fstest.MapFS{
+		"20240101_create_table.up.pgsql": {Data: []byte("CREATE TABLE a (id INT);")},
+	}
+
+	_, err := Load(fsys)
+	assert.ErrorContains(t, err, "must have both up and down scripts")
+}
+
+func TestLoadRejectsDuplicateVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"20240101_create_a.up.pgsql":   {Data: []byte("CREATE TABLE a (id INT);")},
+		"20240101_create_a.down.pgsql": {Data: []byte("DROP TABLE a;")},
+		"20240101_create_b.up.pgsql":   {Data: []byte("CREATE TABLE b (id INT);")},
+		"20240101_create_b.down.pgsql": {Data: []byte("DROP TABLE b;")},
+	}
+
+	_, err := Load(fsys)
+	assert.ErrorContains(t, err, "version 20240101 is used by both")
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	// Каждая встроенная миграция должна загружаться и иметь откат
+	list, err := Load(migrations.FS)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, list)
+}
@@ -0,0 +1,333 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// lockID - ключ advisory-блокировки, чтобы два процесса не применяли миграции одновременно
+const lockID = 7_355_608
+
+const createTableQuery = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version VARCHAR(32) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    applied_at TIMESTAMP NOT NULL DEFAULT NOW()
+)`
+
+// Status - состояние миграции в базе. AppliedAt пустое, если миграция не применена.
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *sql.DB, migrations []Migration) *Migrator {
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}
+}
+
+// Up применяет все неприменённые миграции по возрастанию версии
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := versions[migration.Version]; ok {
+				continue
+			}
+
+			if err := m.apply(ctx, conn, migration); err != nil {
+				return err
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down откатывает steps последних применённых миграций
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("steps must be positive, got %d", steps)
+	}
+
+	var reverted []Migration
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		latest, err := m.latestApplied(ctx, conn, steps)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range latest {
+			if err := m.revert(ctx, conn, migration); err != nil {
+				return err
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Redo откатывает и заново применяет последнюю миграцию
+func (m *Migrator) Redo(ctx context.Context) (*Migration, error) {
+	var redone *Migration
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		latest, err := m.latestApplied(ctx, conn, 1)
+		if err != nil {
+			return err
+		}
+		if len(latest) == 0 {
+			return fmt.Errorf("no applied migrations to redo")
+		}
+
+		migration := latest[0]
+		if err := m.revert(ctx, conn, migration); err != nil {
+			return err
+		}
+		if err := m.apply(ctx, conn, migration); err != nil {
+			return err
+		}
+
+		redone = &migration
+		return nil
+	})
+
+	return redone, err
+}
+
+// Baseline отмечает применёнными миграции до version включительно, не выполняя их.
+// Нужен для базы, схема которой создана без мигратора, например из дампа.
+func (m *Migrator) Baseline(ctx context.Context, version string) ([]Migration, error) {
+	last := slices.IndexFunc(m.migrations, func(migration Migration) bool {
+		return migration.Version == version
+	})
+	if last < 0 {
+		return nil, fmt.Errorf("unknown migration version %s", version)
+	}
+
+	var recorded []Migration
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		return inTx(ctx, conn, func(tx *sql.Tx) error {
+			for _, migration := range m.migrations[:last+1] {
+				if _, ok := versions[migration.Version]; ok {
+					continue
+				}
+
+				if err := record(ctx, tx, migration); err != nil {
+					return err
+				}
+				recorded = append(recorded, migration)
+			}
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return recorded, nil
+}
+
+// Status возвращает состояние каждой известной миграции
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+
+	err := m.withConn(ctx, func(conn *sql.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		statuses = make([]Status, len(m.migrations))
+		for i, migration := range m.migrations {
+			appliedAt, ok := versions[migration.Version]
+			statuses[i] = Status{
+				Migration: migration,
+				Applied:   ok,
+				AppliedAt: appliedAt,
+			}
+		}
+		return nil
+	})
+
+	return statuses, err
+}
+
+// Pending возвращает миграции, которые ещё не применены
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, status := range statuses {
+		if !status.Applied {
+			pending = append(pending, status.Migration)
+		}
+	}
+	return pending, nil
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	return inTx(ctx, conn, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+			return fmt.Errorf("failed to apply migration %s_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		return record(ctx, tx, migration)
+	})
+}
+
+func record(ctx context.Context, tx *sql.Tx, migration Migration) error {
+	_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, migration.Version, migration.Name)
+	if err != nil {
+		return fmt.Errorf("failed to record migration %s: %w", migration.Version, err)
+	}
+	return nil
+}
+
+func (m *Migrator) revert(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	return inTx(ctx, conn, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+			return fmt.Errorf("failed to revert migration %s_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		_, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+		if err != nil {
+			return fmt.Errorf("failed to unrecord migration %s: %w", migration.Version, err)
+		}
+		return nil
+	})
+}
+
+// latestApplied возвращает до limit применённых миграций, начиная с последней
+func (m *Migrator) latestApplied(ctx context.Context, conn *sql.Conn, limit int) ([]Migration, error) {
+	versions, err := appliedVersions(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]struct{}, len(m.migrations))
+	var latest []Migration
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		known[migration.Version] = struct{}{}
+
+		if _, ok := versions[migration.Version]; ok && len(latest) < limit {
+			latest = append(latest, migration)
+		}
+	}
+
+	// Откат версии, о которой бинарный файл не знает, невозможен
+	for version := range versions {
+		if _, ok := known[version]; !ok {
+			return nil, fmt.Errorf("applied migration %s is missing from this build", version)
+		}
+	}
+
+	return latest, nil
+}
+
+func (m *Migrator) withConn(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, createTableQuery); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	return fn(conn)
+}
+
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	return m.withConn(ctx, func(conn *sql.Conn) error {
+		if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockID); err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		err := fn(conn)
+
+		if _, unlockErr := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, lockID); unlockErr != nil {
+			// Блокировка живёт до конца сессии, поэтому соединение закрывается, а не возвращается в пул
+			conn.Raw(func(any) error { return driver.ErrBadConn })
+			err = errors.Join(err, fmt.Errorf("failed to release migration lock: %w", unlockErr))
+		}
+
+		return err
+	})
+}
+
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[string]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make(map[string]time.Time)
+	for rows.Next() {
+		var (
+			version   string
+			appliedAt time.Time
+		)
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		versions[version] = appliedAt
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate applied migrations: %w", err)
+	}
+
+	return versions, nil
+}
+
+func inTx(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration: %w", err)
+	}
+	return nil
+}
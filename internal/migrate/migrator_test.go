@@ -0,0 +1,186 @@
+package migrate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var testMigrations = []Migration{
+	{Version: "20240101", Name: "create_table", Up: "CREATE TABLE a (id INT);", Down: "DROP TABLE a;"},
+	{Version: "20240102", Name: "add_column", Up: "ALTER TABLE a ADD COLUMN b INT;", Down: "ALTER TABLE a DROP COLUMN b;"},
+}
+
+func expectLock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS schema_migrations`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SELECT pg_advisory_lock\(\$1\)`).WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(`SELECT pg_advisory_unlock\(\$1\)`).WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func TestUpAppliesPendingMigrations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow("20240101", time.Now()))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`ALTER TABLE a ADD COLUMN b INT;`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO schema_migrations`).
+		WithArgs("20240102", "add_column").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectUnlock(mock)
+
+	applied, err := NewMigrator(db, testMigrations).Up(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, applied, 1)
+	assert.Equal(t, "20240102", applied[0].Version)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpRollsBackFailedMigration(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`CREATE TABLE a`).WillReturnError(assert.AnError)
+	mock.ExpectRollback()
+	expectUnlock(mock)
+
+	applied, err := NewMigrator(db, testMigrations).Up(context.Background())
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Empty(t, applied)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDownRevertsLatestMigration(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).
+			AddRow("20240101", time.Now()).
+			AddRow("20240102", time.Now()))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`ALTER TABLE a DROP COLUMN b;`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM schema_migrations WHERE version = \$1`).
+		WithArgs("20240102").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectUnlock(mock)
+
+	reverted, err := NewMigrator(db, testMigrations).Down(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, reverted, 1)
+	assert.Equal(t, "20240102", reverted[0].Version)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDownRefusesUnknownVersion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow("20991231", time.Now()))
+	expectUnlock(mock)
+
+	_, err = NewMigrator(db, testMigrations).Down(context.Background(), 1)
+	assert.ErrorContains(t, err, "applied migration 20991231 is missing from this build")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPending(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS schema_migrations`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow("20240101", time.Now()))
+
+	pending, err := NewMigrator(db, testMigrations).Pending(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	assert.Equal(t, "add_column", pending[0].Name)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBaselineRecordsWithoutRunning(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO schema_migrations`).
+		WithArgs("20240101", "create_table").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectUnlock(mock)
+
+	recorded, err := NewMigrator(db, testMigrations).Baseline(context.Background(), "20240101")
+	assert.NoError(t, err)
+	assert.Len(t, recorded, 1)
+	assert.Equal(t, "20240101", recorded[0].Version)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBaselineRejectsUnknownVersion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	_, err = NewMigrator(db, testMigrations).Baseline(context.Background(), "20991231")
+	assert.ErrorContains(t, err, "unknown migration version 20991231")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFailedUnlockClosesConnection(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).
+			AddRow("20240101", time.Now()).
+			AddRow("20240102", time.Now()))
+	mock.ExpectExec(`SELECT pg_advisory_unlock\(\$1\)`).WithArgs(lockID).WillReturnError(assert.AnError)
+	mock.ExpectClose()
+
+	applied, err := NewMigrator(db, testMigrations).Up(context.Background())
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Empty(t, applied)
+	assert.Zero(t, db.Stats().OpenConnections)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
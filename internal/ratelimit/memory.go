@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval - как часто MemoryStore удаляет заполнившиеся корзины
+const sweepInterval = time.Minute
+
+type bucket struct {
+	// fullAt - момент, когда корзина снова заполнится. Хранить время вместо
+	// числа токенов удобнее: не нужно пересчитывать остаток при каждом обращении.
+	fullAt time.Time
+}
+
+// MemoryStore хранит корзины в памяти процесса
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	interval := limit.interval()
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{fullAt: now}
+		s.buckets[key] = b
+	}
+	if b.fullAt.Before(now) {
+		b.fullAt = now
+	}
+
+	// Корзина вмещает Requests токенов: запрос разрешён, пока до заполнения
+	// остаётся меньше, чем восстанавливаются все токены
+	fullAt := b.fullAt.Add(interval)
+	if wait := fullAt.Sub(now) - limit.Per; wait > 0 {
+		return wait, nil
+	}
+
+	b.fullAt = fullAt
+	return 0, nil
+}
+
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if !b.fullAt.After(now) {
+			delete(s.buckets, key)
+		}
+	}
+}
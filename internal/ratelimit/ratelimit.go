@@ -0,0 +1,52 @@
+// Package ratelimit ограничивает частоту запросов алгоритмом token bucket.
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit - не больше Requests запросов за Per. Токены восстанавливаются равномерно,
+// поэтому после паузы можно снова сделать до Requests запросов подряд.
+type Limit struct {
+	Requests int
+	Per      time.Duration
+}
+
+func (l Limit) enabled() bool {
+	return l.Requests > 0 && l.Per > 0
+}
+
+// interval - время восстановления одного токена
+func (l Limit) interval() time.Duration {
+	return l.Per / time.Duration(l.Requests)
+}
+
+// Store хранит состояние ограничителей. Take забирает токен по ключу и возвращает 0,
+// если запрос разрешён, иначе - сколько ждать до следующей попытки.
+// MemoryStore подходит для одного экземпляра приложения; при нескольких экземплярах
+// нужна общая реализация, например поверх Redis.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (time.Duration, error)
+}
+
+// Limiter применяет одно ограничение к разным ключам: IP-адресам, учётным записям и т.д.
+type Limiter struct {
+	store Store
+	name  string
+	limit Limit
+}
+
+// New создаёт ограничитель. name отделяет его ключи от ключей других ограничителей в том же Store.
+func New(store Store, name string, limit Limit) *Limiter {
+	return &Limiter{store: store, name: name, limit: limit}
+}
+
+// Allow возвращает 0, если запрос по ключу разрешён, иначе время до следующей попытки.
+// Ограничитель с нулевым лимитом пропускает все запросы.
+func (l *Limiter) Allow(ctx context.Context, key string) (time.Duration, error) {
+	if !l.limit.enabled() {
+		return 0, nil
+	}
+	return l.store.Take(ctx, l.name+":"+key, l.limit)
+}
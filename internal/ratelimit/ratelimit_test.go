@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore(now *time.Time) *MemoryStore {
+	store := NewMemoryStore()
+	store.now = func() time.Time { return *now }
+	return store
+}
+
+func TestLimiterAllowsBurstThenThrottles(t *testing.T) {
+	now := time.Date(2025, time.January, 10, 12, 0, 0, 0, time.UTC)
+	limiter := New(newTestStore(&now), "login", Limit{Requests: 3, Per: time.Minute})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		wait, err := limiter.Allow(ctx, "10.0.0.1")
+		require.NoError(t, err)
+		assert.Zero(t, wait)
+	}
+
+	wait, err := limiter.Allow(ctx, "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, 20*time.Second, wait)
+
+	// Другой ключ ограничивается отдельно
+	wait, err = limiter.Allow(ctx, "10.0.0.2")
+	require.NoError(t, err)
+	assert.Zero(t, wait)
+
+	// Через 20 секунд восстанавливается один токен
+	now = now.Add(20 * time.Second)
+	wait, err = limiter.Allow(ctx, "10.0.0.1")
+	require.NoError(t, err)
+	assert.Zero(t, wait)
+
+	wait, err = limiter.Allow(ctx, "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, 20*time.Second, wait)
+}
+
+func TestLimiterSeparatesNames(t *testing.T) {
+	now := time.Now()
+	store := newTestStore(&now)
+	limit := Limit{Requests: 1, Per: time.Minute}
+
+	login := New(store, "login", limit)
+	register := New(store, "register", limit)
+
+	wait, _ := login.Allow(context.Background(), "10.0.0.1")
+	assert.Zero(t, wait)
+	wait, _ = register.Allow(context.Background(), "10.0.0.1")
+	assert.Zero(t, wait)
+	wait, _ = login.Allow(context.Background(), "10.0.0.1")
+	assert.Equal(t, time.Minute, wait)
+}
+
+func TestDisabledLimiter(t *testing.T) {
+	limiter := New(NewMemoryStore(), "login", Limit{})
+
+	for i := 0; i < 100; i++ {
+		wait, err := limiter.Allow(context.Background(), "10.0.0.1")
+		require.NoError(t, err)
+		assert.Zero(t, wait)
+	}
+}
+
+func TestMemoryStoreSweepsFullBuckets(t *testing.T) {
+	now := time.Now()
+	store := newTestStore(&now)
+	limiter := New(store, "login", Limit{Requests: 2, Per: time.Second})
+
+	limiter.Allow(context.Background(), "10.0.0.1")
+	assert.Len(t, store.buckets, 1)
+
+	now = now.Add(2 * sweepInterval)
+	limiter.Allow(context.Background(), "10.0.0.2")
+	assert.Len(t, store.buckets, 1)
+	assert.Contains(t, store.buckets, "login:10.0.0.2")
+}
@@ -3,16 +3,24 @@ package server
 import (
 	"context"
 	"errors"
-	"fmt"
+	"log/slog"
 	"net/http"
 	"strings"
 
 	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/logger"
 )
 
 var noAuthPaths = map[string]struct{}{
-	"/login":    {},
-	"/register": {},
+	"/login":           {},
+	"/register":        {},
+	"/token/refresh":   {},
+	"/password/forgot": {},
+	"/password/reset":  {},
+	"/verify-email":    {},
+
+	"/invitations/accept":  {},
+	"/invitations/decline": {},
 }
 
 func authMiddleware(next http.Handler, tokenParser tokenParser) http.Handler {
@@ -30,7 +38,7 @@ func authMiddleware(next http.Handler, tokenParser tokenParser) http.Handler {
 
 		token := strings.TrimPrefix(tokenString, "Bearer ")
 
-		claims, err := tokenParser(token)
+		claims, err := tokenParser(r.Context(), token)
 
 		if err != nil {
 			if errors.Is(err, common.ErrInvalidToken) {
@@ -39,6 +47,12 @@ func authMiddleware(next http.Handler, tokenParser tokenParser) http.Handler {
 					Error: common.ErrInvalidToken.Error(),
 				}
 				WriteHTTPError(w, httpError)
+			} else if errors.Is(err, common.ErrSessionRevoked) {
+				httpError := &HTTPError{
+					Code:  http.StatusUnauthorized,
+					Error: common.ErrSessionRevoked.Error(),
+				}
+				WriteHTTPError(w, httpError)
 			} else {
 				httpError := &HTTPError{
 					Code:  http.StatusUnauthorized,
@@ -46,11 +60,14 @@ func authMiddleware(next http.Handler, tokenParser tokenParser) http.Handler {
 				}
 				WriteHTTPError(w, httpError)
 			}
-			fmt.Print(err)
+			logger.FromContext(r.Context()).Info("authentication failed", slog.Any("error", err))
 			return
 		}
 
+		requestInfoFromContext(r.Context()).userID = claims.UserID
+
 		ctx := context.WithValue(r.Context(), common.ContextKeyClaims, claims)
+		ctx = logger.With(ctx, "user_id", claims.UserID)
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
@@ -0,0 +1,23 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// deadlineMiddleware ограничивает время жизни ctx всей обработки запроса. Репозитории выполняют запросы
+// через *Context-методы, поэтому по истечении дедлайна или при обрыве соединения клиентом
+// PostgreSQL отменяет текущий запрос.
+func deadlineMiddleware(next http.Handler, timeout time.Duration) http.Handler {
+	if timeout <= 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -1,23 +1,28 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
-	"log"
+	"log/slog"
 	"net/http"
 
 	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/logger"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/validation"
 )
 
 type handler = func(http.ResponseWriter, *http.Request) error
 
 func errorHandling(handler handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestInfoFromContext(r.Context()).pattern = r.Pattern
+
 		if err := handler(w, r); err != nil {
-			log.Printf("Error: %v", err)
 			var (
 				code         int
 				errorMessage string
+				description  any
 			)
 
 			switch {
@@ -45,31 +50,87 @@ func errorHandling(handler handler) http.Handler {
 				errorMessage = common.ErrTokenNotValid.Error()
 				code = http.StatusUnauthorized
 
+			case errors.Is(err, common.ErrSessionRevoked):
+				errorMessage = common.ErrSessionRevoked.Error()
+				code = http.StatusUnauthorized
+
 			case errors.Is(err, common.ErrForbidden):
 				errorMessage = common.ErrForbidden.Error()
 				code = http.StatusForbidden
 
+			case errors.Is(err, common.ErrAccountDeactivated):
+				errorMessage = common.ErrAccountDeactivated.Error()
+				code = http.StatusForbidden
+
+			case errors.Is(err, common.ErrTooManyRequests):
+				errorMessage = common.ErrTooManyRequests.Error()
+				code = http.StatusTooManyRequests
+				var retryErr *common.RetryAfterError
+				if errors.As(err, &retryErr) {
+					setRetryAfter(w, retryErr.RetryAfter)
+				}
+
+			case errors.Is(err, common.ErrValidation):
+				errorMessage = common.ErrValidation.Error()
+				description = err.Error()
+				var fieldErrors validation.Errors
+				if errors.As(err, &fieldErrors) {
+					description = fieldErrors
+				}
+				code = http.StatusUnprocessableEntity
+
+			case errors.Is(err, common.ErrInvalidInput):
+				errorMessage = common.ErrInvalidInput.Error()
+				description = err.Error()
+				code = http.StatusBadRequest
+
+			case errors.Is(err, common.ErrInvalidTransition):
+				errorMessage = common.ErrInvalidTransition.Error()
+				description = err.Error()
+				code = http.StatusConflict
+
+			case errors.Is(err, common.ErrPreconditionFailed):
+				errorMessage = common.ErrPreconditionFailed.Error()
+				code = http.StatusPreconditionFailed
+
+			case errors.Is(err, context.DeadlineExceeded) || errors.Is(r.Context().Err(), context.DeadlineExceeded):
+				errorMessage = "request timed out"
+				code = http.StatusServiceUnavailable
+
+			case errors.Is(r.Context().Err(), context.Canceled):
+				// Клиент уже закрыл соединение, ответ никто не прочитает
+				errorMessage = "request canceled"
+				code = statusClientClosedRequest
+
 			default:
 				errorMessage = err.Error()
 				code = http.StatusInternalServerError
 				http.Error(w, "internal server error", http.StatusInternalServerError)
 			}
 
-			log.Printf("Error: %v", err)
+			level := slog.LevelInfo
+			if code >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			logger.FromContext(r.Context()).Log(r.Context(), level, "request failed", slog.Int("status", code), slog.Any("error", err))
 
 			httpError := &HTTPError{
-				Code:  code,
-				Error: errorMessage,
+				Code:        code,
+				Error:       errorMessage,
+				Description: description,
 			}
 			WriteHTTPError(w, httpError)
 		}
 	})
 }
 
+// statusClientClosedRequest - нестандартный код nginx для запросов, оборванных клиентом
+const statusClientClosedRequest = 499
+
 type HTTPError struct {
 	Code        int    `json:"code"`
 	Error       string `json:"error"`
-	Description string `json:"description,omitempty"`
+	Description any    `json:"description,omitempty"`
 }
 
 func WriteHTTPError(w http.ResponseWriter, err *HTTPError) {
@@ -0,0 +1,24 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorHandlingRetryAfter(t *testing.T) {
+	handler := errorHandling(func(http.ResponseWriter, *http.Request) error {
+		return fmt.Errorf("user 1 is locked: %w", &common.RetryAfterError{RetryAfter: 90*time.Second + time.Millisecond})
+	})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/login", nil))
+
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	assert.Equal(t, "91", recorder.Header().Get("Retry-After"))
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/logger"
+)
+
+const readinessTimeout = 2 * time.Second
+
+type readinessCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+type healthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// healthz отвечает, пока процесс жив
+func (s *HTTPServer) healthz(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, http.StatusOK, &healthResponse{Status: "ok"})
+}
+
+// readyz проверяет зависимости; во время остановки сервер перестаёт быть готовым
+func (s *HTTPServer) readyz(w http.ResponseWriter, r *http.Request) {
+	if s.shutdown.Load() {
+		writeHealth(w, http.StatusServiceUnavailable, &healthResponse{Status: "shutting down"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	response := &healthResponse{Status: "ok", Checks: make(map[string]string, len(s.checks))}
+	code := http.StatusOK
+
+	for _, c := range s.checks {
+		if err := c.check(ctx); err != nil {
+			// Подробности ошибки только в логе: /readyz доступен без аутентификации
+			logger.FromContext(ctx).Warn("readiness check failed", slog.String("check", c.name), slog.Any("error", err))
+			response.Checks[c.name] = "unavailable"
+			response.Status = "unavailable"
+			code = http.StatusServiceUnavailable
+			continue
+		}
+		response.Checks[c.name] = "ok"
+	}
+
+	writeHealth(w, code, response)
+}
+
+func writeHealth(w http.ResponseWriter, code int, response *healthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(response)
+}
@@ -1,22 +1,54 @@
 package server
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	"net/http"
 	"time"
+
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/logger"
 )
 
-// loggingMiddleware логирует входящие HTTP-запросы
+type requestInfoKey struct{}
+
+// requestInfo заполняют внутренние обработчики, а читает loggingMiddleware после ответа:
+// r.WithContext создаёт копию запроса, поэтому иначе эти данные наружу не попадут
+type requestInfo struct {
+	userID  uint32
+	pattern string
+}
+
+func requestInfoFromContext(ctx context.Context) *requestInfo {
+	if info, ok := ctx.Value(requestInfoKey{}).(*requestInfo); ok {
+		return info
+	}
+	return &requestInfo{}
+}
+
+// loggingMiddleware пишет в журнал итог каждого запроса
 func loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
+		info := &requestInfo{}
+		ctx := context.WithValue(r.Context(), requestInfoKey{}, info)
+
 		ww := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
 
-		next.ServeHTTP(ww, r)
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		attrs := []any{
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.String("route", info.pattern),
+			slog.Int("status", ww.statusCode),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+		}
+		if info.userID != 0 {
+			attrs = append(attrs, slog.Uint64("user_id", uint64(info.userID)))
+		}
 
-		duration := time.Since(start)
-		log.Printf("Request %s %s processed in %v - %d %s", r.Method, r.URL.Path, duration, ww.statusCode, http.StatusText(ww.statusCode))
+		logger.FromContext(ctx).Info("request completed", attrs...)
 	})
 }
 
@@ -0,0 +1,26 @@
+package server
+
+import (
+	"net/http"
+	"time"
+)
+
+// RequestMetrics принимает итог каждого запроса и отдаёт собранные метрики
+type RequestMetrics interface {
+	ObserveRequest(method, route string, status int, duration time.Duration)
+	Handler() http.Handler
+}
+
+// metricsMiddleware должен стоять внутри loggingMiddleware: шаблон маршрута
+// попадает в requestInfo, который создаёт loggingMiddleware
+func metricsMiddleware(next http.Handler, metrics RequestMetrics) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		ww := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(ww, r)
+
+		info := requestInfoFromContext(r.Context())
+		metrics.ObserveRequest(r.Method, info.pattern, ww.statusCode, time.Since(start))
+	})
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/logger"
+)
+
+// RateLimiter возвращает 0, если запрос по ключу разрешён, иначе время до следующей попытки
+type RateLimiter interface {
+	Allow(ctx context.Context, key string) (time.Duration, error)
+}
+
+// rateLimitMiddleware ограничивает число запросов к paths с одного IP-адреса.
+// Каждый путь считается отдельно.
+func rateLimitMiddleware(next http.Handler, limiter RateLimiter, paths map[string]struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := paths[r.URL.Path]; !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		wait, err := limiter.Allow(r.Context(), r.URL.Path+":"+clientIP(r))
+		if err != nil {
+			// Недоступность хранилища лимитов не должна останавливать сервис
+			logger.FromContext(r.Context()).Error("failed to check rate limit", slog.Any("error", err))
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if wait > 0 {
+			requestInfoFromContext(r.Context()).pattern = r.Method + " " + r.URL.Path
+			logger.FromContext(r.Context()).Info("rate limit exceeded", slog.String("ip", clientIP(r)))
+			writeTooManyRequests(w, wait)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientIP - адрес, с которого пришло соединение. Заголовок X-Forwarded-For
+// не учитывается: без доверенного прокси его может подделать сам клиент.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func writeTooManyRequests(w http.ResponseWriter, wait time.Duration) {
+	setRetryAfter(w, wait)
+	WriteHTTPError(w, &HTTPError{
+		Code:  http.StatusTooManyRequests,
+		Error: common.ErrTooManyRequests.Error(),
+	})
+}
+
+// setRetryAfter округляет ожидание вверх до целых секунд
+func setRetryAfter(w http.ResponseWriter, wait time.Duration) {
+	seconds := int(math.Ceil(wait.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+}
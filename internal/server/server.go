@@ -1,22 +1,50 @@
 package server
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
+	"sync/atomic"
+	"time"
 
 	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/logger"
 )
 
-type tokenParser = func(string) (*common.Claims, error)
+type tokenParser = func(context.Context, string) (*common.Claims, error)
+
+// Timeouts - таймауты http.Server и время на завершение активных запросов при остановке
+type Timeouts struct {
+	ReadHeader time.Duration
+	Read       time.Duration
+	Write      time.Duration
+	Idle       time.Duration
+	Shutdown   time.Duration
+	// Request - дедлайн ctx запроса на всю обработку, а не на отдельный запрос к базе; 0 - без ограничения
+	Request time.Duration
+}
 
 type HTTPServer struct {
 	address     string
 	tokenParser tokenParser
+	timeouts    Timeouts
+	origins     []string
+	checks      []readinessCheck
+	metrics     RequestMetrics
+	limiter     RateLimiter
+	limitPaths  map[string]struct{}
+	shutdown    atomic.Bool
 }
 
-func NewServer(addr string, tokenParser tokenParser) *HTTPServer {
+func NewServer(addr string, tokenParser tokenParser, timeouts Timeouts, allowedOrigins []string) *HTTPServer {
 	return &HTTPServer{
 		address:     addr,
 		tokenParser: tokenParser,
+		timeouts:    timeouts,
+		origins:     allowedOrigins,
 	}
 }
 
@@ -24,18 +52,95 @@ func (s *HTTPServer) Address() string {
 	return s.address
 }
 
-func (s *HTTPServer) Start(handlers ...Handler) error {
+// AddReadinessCheck добавляет проверку, от которой зависит ответ /readyz
+func (s *HTTPServer) AddReadinessCheck(name string, check func(ctx context.Context) error) {
+	s.checks = append(s.checks, readinessCheck{name: name, check: check})
+}
+
+// EnableMetrics включает сбор метрик запросов и маршрут /metrics
+func (s *HTTPServer) EnableMetrics(metrics RequestMetrics) {
+	s.metrics = metrics
+}
+
+// EnableRateLimit ограничивает число запросов к paths с одного IP-адреса
+func (s *HTTPServer) EnableRateLimit(limiter RateLimiter, paths ...string) {
+	s.limiter = limiter
+	s.limitPaths = make(map[string]struct{}, len(paths))
+	for _, path := range paths {
+		s.limitPaths[path] = struct{}{}
+	}
+}
+
+// Start обслуживает запросы до отмены ctx, после чего дожидается завершения активных запросов
+func (s *HTTPServer) Start(ctx context.Context, handlers ...Handler) error {
 	mux := http.NewServeMux()
 
 	for _, handler := range handlers {
 		handler.RegisterRoutes(mux, errorHandling)
 	}
 
-	authMux := authMiddleware(mux, s.tokenParser)
+	var authMux http.Handler = authMiddleware(mux, s.tokenParser)
+	if s.limiter != nil {
+		authMux = rateLimitMiddleware(authMux, s.limiter, s.limitPaths)
+	}
+	// Дедлайн действует и на проверку сессии в authMiddleware
+	authMux = deadlineMiddleware(authMux, s.timeouts.Request)
+	if s.metrics != nil {
+		authMux = metricsMiddleware(authMux, s.metrics)
+	}
+
+	authAndLoggingMux := loggingMiddleware(tracingMiddleware(authMux))
 
-	authAndLoggingMux := loggingMiddleware(authMux)
+	requestIDMux := requestIDMiddleware(authAndLoggingMux)
 
-	finalMux := corsMiddleware(authAndLoggingMux)
+	apiMux := corsMiddleware(requestIDMux, s.origins)
 
-	return http.ListenAndServe(s.address, finalMux)
+	// Пробы и метрики не требуют аутентификации и не попадают в журнал запросов
+	rootMux := http.NewServeMux()
+	rootMux.HandleFunc("GET /healthz", s.healthz)
+	rootMux.HandleFunc("GET /readyz", s.readyz)
+	if s.metrics != nil {
+		rootMux.Handle("GET /metrics", s.metrics.Handler())
+	}
+	rootMux.Handle("/", apiMux)
+
+	// Запросы наследуют логгер из ctx, но не его отмену: при остановке активные запросы дорабатывают
+	baseCtx := context.WithoutCancel(ctx)
+
+	httpServer := &http.Server{
+		Addr:              s.address,
+		Handler:           rootMux,
+		BaseContext:       func(net.Listener) context.Context { return baseCtx },
+		ErrorLog:          slog.NewLogLogger(logger.FromContext(ctx).Handler(), slog.LevelWarn),
+		ReadHeaderTimeout: s.timeouts.ReadHeader,
+		ReadTimeout:       s.timeouts.Read,
+		WriteTimeout:      s.timeouts.Write,
+		IdleTimeout:       s.timeouts.Idle,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("server stopped: %w", err)
+	case <-ctx.Done():
+	}
+
+	s.shutdown.Store(true)
+	logger.FromContext(ctx).Info("shutting down server", slog.Duration("drain_timeout", s.timeouts.Shutdown))
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.timeouts.Shutdown)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down server: %w", err)
+	}
+
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server stopped: %w", err)
+	}
+	return nil
 }
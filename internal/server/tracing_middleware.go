@@ -0,0 +1,53 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/logger"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/lunarKettle/task-management-platform-monolith/internal/server"
+
+// tracingMiddleware открывает корневой span запроса, продолжая trace из заголовка traceparent.
+// Как и metricsMiddleware, должен стоять внутри loggingMiddleware, чтобы знать шаблон маршрута.
+func tracingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		ctx, span := otel.Tracer(tracerName).Start(ctx, r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("url.path", r.URL.Path),
+			),
+		)
+		defer span.End()
+
+		if spanContext := span.SpanContext(); spanContext.IsValid() {
+			ctx = logger.With(ctx, "trace_id", spanContext.TraceID().String())
+		}
+
+		ww := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		info := requestInfoFromContext(ctx)
+		if info.pattern != "" {
+			span.SetName(info.pattern)
+			span.SetAttributes(attribute.String("http.route", info.pattern))
+		}
+		if info.userID != 0 {
+			span.SetAttributes(attribute.Int64("enduser.id", int64(info.userID)))
+		}
+		span.SetAttributes(attribute.Int("http.response.status_code", ww.statusCode))
+		if ww.statusCode >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, fmt.Sprintf("status %d", ww.statusCode))
+		}
+	})
+}
@@ -0,0 +1,9 @@
+// Package migrations содержит SQL-миграции, встроенные в бинарный файл.
+package migrations
+
+import "embed"
+
+// FS - пары файлов <версия>_<название>.up.pgsql и <версия>_<название>.down.pgsql
+//
+//go:embed *.pgsql
+var FS embed.FS
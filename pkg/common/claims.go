@@ -3,11 +3,14 @@ package common
 import "github.com/golang-jwt/jwt/v5"
 
 const (
-	ContextKeyClaims = "userClaims"
+	ContextKeyClaims    = "userClaims"
+	ContextKeyRequestID = "requestID"
+	ContextKeyLogger    = "logger"
 )
 
 type Claims struct {
-	UserID uint32 `json:"sub"`
-	Role   string `json:"role"`
+	UserID    uint32 `json:"sub"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid"`
 	jwt.RegisteredClaims
 }
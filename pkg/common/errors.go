@@ -1,6 +1,9 @@
 package common
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
 	ErrNotFound                = errors.New("not found")
@@ -10,4 +13,24 @@ var (
 	ErrUnexpectedSigningMethod = errors.New("unexpected signing method")
 	ErrTokenNotValid           = errors.New("token is not valid")
 	ErrForbidden               = errors.New("access forbidden")
+	ErrSessionRevoked          = errors.New("session revoked")
+	ErrInvalidInput            = errors.New("invalid input")
+	ErrInvalidTransition       = errors.New("invalid status transition")
+	ErrPreconditionFailed      = errors.New("precondition failed")
+	ErrValidation              = errors.New("validation failed")
+	ErrTooManyRequests         = errors.New("too many requests")
+	ErrAccountDeactivated      = errors.New("account is deactivated")
 )
+
+// RetryAfterError сообщает, через сколько можно повторить запрос. Сводится к ErrTooManyRequests.
+type RetryAfterError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return "too many requests, retry after " + e.RetryAfter.Round(time.Second).String()
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return ErrTooManyRequests
+}
@@ -0,0 +1,103 @@
+package common
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+// PageRequest - параметры keyset-пагинации. Limit == 0 означает выборку без ограничения.
+type PageRequest struct {
+	Limit  int
+	Sort   string
+	Desc   bool
+	Cursor *Cursor
+}
+
+// SortKey возвращает сортировку в виде параметра запроса: "name" или "-name"
+func (p PageRequest) SortKey() string {
+	if p.Desc {
+		return "-" + p.Sort
+	}
+	return p.Sort
+}
+
+// ParsePageRequest разбирает параметры limit, sort и cursor. Сортировка по убыванию задаётся префиксом "-",
+// defaultSort в том же виде применяется, если sort не передан. Курсор принимается только для той же сортировки.
+func ParsePageRequest(query url.Values, defaultSort string, sortFields []string) (PageRequest, error) {
+	page := PageRequest{Limit: DefaultPageLimit}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > MaxPageLimit {
+			return page, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidInput, MaxPageLimit)
+		}
+		page.Limit = limit
+	}
+
+	sort := defaultSort
+	if value := query.Get("sort"); value != "" {
+		sort = value
+	}
+	page.Desc = strings.HasPrefix(sort, "-")
+	page.Sort = strings.TrimPrefix(sort, "-")
+
+	if !slices.Contains(sortFields, page.Sort) {
+		return page, fmt.Errorf("%w: unknown sort field %q", ErrInvalidInput, page.Sort)
+	}
+
+	if value := query.Get("cursor"); value != "" {
+		cursor, err := DecodeCursor(value)
+		if err != nil {
+			return page, err
+		}
+
+		if cursor.Sort != page.SortKey() {
+			return page, fmt.Errorf("%w: cursor does not match sort %q", ErrInvalidInput, page.SortKey())
+		}
+		page.Cursor = cursor
+	}
+
+	return page, nil
+}
+
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+	TotalCount int
+}
+
+// Cursor указывает на последнюю запись страницы: значение поля сортировки и ID
+type Cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    uint32 `json:"id"`
+}
+
+func EncodeCursor(cursor Cursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(value string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidInput)
+	}
+
+	cursor := &Cursor{}
+	if err := json.Unmarshal(data, cursor); err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidInput)
+	}
+
+	return cursor, nil
+}
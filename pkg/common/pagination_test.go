@@ -0,0 +1,41 @@
+package common
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePageRequest(t *testing.T) {
+	fields := []string{"id", "username"}
+
+	page, err := ParsePageRequest(url.Values{}, "id", fields)
+	require.NoError(t, err)
+	assert.Equal(t, PageRequest{Limit: DefaultPageLimit, Sort: "id"}, page)
+
+	cursor := EncodeCursor(Cursor{Sort: "-username", Value: "bob", ID: 2})
+	page, err = ParsePageRequest(url.Values{"sort": {"-username"}, "limit": {"10"}, "cursor": {cursor}}, "id", fields)
+	require.NoError(t, err)
+	assert.Equal(t, "username", page.Sort)
+	assert.True(t, page.Desc)
+	assert.Equal(t, 10, page.Limit)
+	assert.Equal(t, "bob", page.Cursor.Value)
+}
+
+func TestParsePageRequestRejectsInvalidParams(t *testing.T) {
+	fields := []string{"id", "username"}
+
+	// Неизвестное поле сортировки не подменяется сортировкой по id
+	_, err := ParsePageRequest(url.Values{"sort": {"-created_at"}}, "id", fields)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	_, err = ParsePageRequest(url.Values{"limit": {"0"}}, "id", fields)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	// Курсор от другой сортировки дал бы пропуски и повторы
+	cursor := EncodeCursor(Cursor{Sort: "id", Value: "2", ID: 2})
+	_, err = ParsePageRequest(url.Values{"sort": {"username"}, "cursor": {cursor}}, "id", fields)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
@@ -0,0 +1,45 @@
+package common
+
+import "slices"
+
+const (
+	RoleAdmin   = "admin"
+	RoleManager = "manager"
+	RoleMember  = "member"
+	RoleViewer  = "viewer"
+)
+
+// Roles - все роли, которые можно назначить пользователю
+var Roles = []string{RoleAdmin, RoleManager, RoleMember, RoleViewer}
+
+// TeamRoles - роли, которые может выдать участнику команды пользователь без права role:assign
+var TeamRoles = []string{RoleMember, RoleViewer}
+
+func IsValidRole(role string) bool {
+	return slices.Contains(Roles, role)
+}
+
+type Permission string
+
+const (
+	PermProjectRead  Permission = "project:read"
+	PermProjectWrite Permission = "project:write"
+	PermTeamRead     Permission = "team:read"
+	PermTeamWrite    Permission = "team:write"
+	PermTeamManage   Permission = "team:manage"
+	PermTaskRead     Permission = "task:read"
+	PermTaskWrite    Permission = "task:write"
+	PermTaskAssign   Permission = "task:assign"
+	PermMemberRead   Permission = "member:read"
+	PermRoleAssign   Permission = "role:assign"
+	PermUserManage   Permission = "user:manage"
+
+	PermCommentWrite    Permission = "comment:write"
+	PermCommentModerate Permission = "comment:moderate"
+
+	PermAuditRead    Permission = "audit:read"
+	PermTrashRestore Permission = "trash:restore"
+
+	// PermAllTeams снимает ограничение "только своя команда" с остальных прав роли
+	PermAllTeams Permission = "scope:all_teams"
+)
@@ -0,0 +1,32 @@
+// Package logger создаёт JSON-логгер на основе log/slog и передаёт его через context.
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+)
+
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+// WithContext сохраняет логгер в контексте
+func WithContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, common.ContextKeyLogger, logger)
+}
+
+// FromContext возвращает логгер из контекста, а если его нет - slog.Default()
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(common.ContextKeyLogger).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// With добавляет атрибуты к логгеру из контекста
+func With(ctx context.Context, args ...any) context.Context {
+	return WithContext(ctx, FromContext(ctx).With(args...))
+}
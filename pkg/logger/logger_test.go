@@ -0,0 +1,31 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextLoggerCarriesAttributes(t *testing.T) {
+	var out bytes.Buffer
+	ctx := WithContext(context.Background(), New(&out, slog.LevelInfo))
+	ctx = With(ctx, "request_id", "abc")
+
+	FromContext(ctx).Info("request completed", "status", 200)
+	FromContext(ctx).Debug("skipped below the level")
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+	assert.Equal(t, "request completed", entry["msg"])
+	assert.Equal(t, "abc", entry["request_id"])
+	assert.Equal(t, float64(200), entry["status"])
+}
+
+func TestFromContextFallsBackToDefault(t *testing.T) {
+	assert.Same(t, slog.Default(), FromContext(context.Background()))
+}
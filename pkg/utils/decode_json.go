@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/validation"
+)
+
+// DecodeJSON читает тело запроса в dst и проверяет его по тегам validate
+func DecodeJSON(r *http.Request, dst any) error {
+	defer r.Body.Close()
+
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		return fmt.Errorf("%w: malformed request body: %v", common.ErrInvalidInput, err)
+	}
+
+	return validation.Struct(dst)
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"net/http"
 	"regexp"
 	"strconv"
 )
@@ -25,3 +26,18 @@ func ExtractIDFromPath(path string) (uint32, error) {
 
 	return uint32(parsedUint), nil
 }
+
+// ExtractIDFromPathValue получает ID из именованного сегмента шаблона маршрута, например {id}
+func ExtractIDFromPathValue(r *http.Request, name string) (uint32, error) {
+	value := r.PathValue(name)
+	if value == "" {
+		return 0, fmt.Errorf("path value %q is missing", name)
+	}
+
+	parsedUint, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid id format: %w", err)
+	}
+
+	return uint32(parsedUint), nil
+}
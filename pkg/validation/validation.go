@@ -0,0 +1,281 @@
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+)
+
+// FieldError - ошибка проверки одного поля, Field совпадает с именем поля в JSON
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Errors - список ошибок проверки, оборачивает common.ErrValidation
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	parts := make([]string, len(e))
+	for i, fieldErr := range e {
+		parts[i] = fieldErr.Field + ": " + fieldErr.Message
+	}
+	return fmt.Sprintf("%s: %s", common.ErrValidation, strings.Join(parts, "; "))
+}
+
+func (e Errors) Unwrap() error {
+	return common.ErrValidation
+}
+
+func (e *Errors) Add(field, message string) {
+	*e = append(*e, FieldError{Field: field, Message: message})
+}
+
+// Err возвращает nil, если ошибок нет
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Struct проверяет поля структуры по тегам validate.
+// Поддерживаются правила required, omitempty, email, min, max и oneof;
+// вложенные структуры и срезы структур проверяются рекурсивно.
+// Как и в go-playground/validator, пустое значение пропускается только с omitempty,
+// без него остальные правила применяются и к нулевым значениям.
+// Nil-указатель означает отсутствующее поле и проверяется только правилом required.
+func Struct(v any) error {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("validation: expected struct, got %s", value.Kind())
+	}
+
+	var errs Errors
+	if err := validateStruct(value, "", &errs); err != nil {
+		return err
+	}
+	return errs.Err()
+}
+
+func validateStruct(value reflect.Value, prefix string, errs *Errors) error {
+	structType := value.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := prefix + fieldName(field)
+		fieldValue := value.Field(i)
+
+		if tag := field.Tag.Get("validate"); tag != "" && tag != "-" {
+			if err := validateField(fieldValue, name, tag, errs); err != nil {
+				return fmt.Errorf("validation: field %s: %w", field.Name, err)
+			}
+		}
+
+		if err := validateNested(fieldValue, name, errs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateNested(value reflect.Value, name string, errs *Errors) error {
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		if value.Type().PkgPath() == "time" {
+			return nil
+		}
+		return validateStruct(value, name+".", errs)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			elem := value.Index(i)
+			for elem.Kind() == reflect.Pointer && !elem.IsNil() {
+				elem = elem.Elem()
+			}
+			if elem.Kind() != reflect.Struct {
+				continue
+			}
+			if err := validateStruct(elem, fmt.Sprintf("%s[%d].", name, i), errs); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateField(value reflect.Value, name, tag string, errs *Errors) error {
+	rules := strings.Split(tag, ",")
+
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			if hasRule(rules, "required") {
+				errs.Add(name, "is required")
+			}
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	if isEmpty(value) {
+		if hasRule(rules, "required") {
+			errs.Add(name, "is required")
+			return nil
+		}
+		if hasRule(rules, "omitempty") {
+			return nil
+		}
+	}
+
+	for _, rule := range rules {
+		key, param, _ := strings.Cut(rule, "=")
+
+		var (
+			message string
+			err     error
+		)
+
+		switch key {
+		case "required", "omitempty":
+			continue
+		case "email":
+			message = checkEmail(value)
+		case "min":
+			message, err = checkBound(value, param, true)
+		case "max":
+			message, err = checkBound(value, param, false)
+		case "oneof":
+			message = checkOneOf(value, param)
+		default:
+			err = fmt.Errorf("unknown rule %q", key)
+		}
+
+		if err != nil {
+			return err
+		}
+		if message != "" {
+			errs.Add(name, message)
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func fieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+func hasRule(rules []string, rule string) bool {
+	for _, r := range rules {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmpty(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(value.String()) == ""
+	case reflect.Slice, reflect.Map:
+		return value.Len() == 0
+	default:
+		return value.IsZero()
+	}
+}
+
+func checkEmail(value reflect.Value) string {
+	if value.Kind() != reflect.String {
+		return "must be a string"
+	}
+
+	address, err := mail.ParseAddress(value.String())
+	if err != nil || address.Address != value.String() {
+		return "must be a valid email address"
+	}
+	return ""
+}
+
+// checkBound проверяет длину строк и срезов или значение чисел
+func checkBound(value reflect.Value, param string, isMin bool) (string, error) {
+	bound, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid bound %q: %w", param, err)
+	}
+
+	var (
+		actual float64
+		unit   string
+	)
+
+	switch value.Kind() {
+	case reflect.String:
+		actual, unit = float64(utf8.RuneCountInString(value.String())), " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		actual, unit = float64(value.Len()), " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		actual = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		actual = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		actual = value.Float()
+	default:
+		return "", fmt.Errorf("bound rules are not supported for %s", value.Kind())
+	}
+
+	if isMin && actual < bound {
+		if unit != "" {
+			return fmt.Sprintf("must be at least %s%s long", param, unit), nil
+		}
+		return fmt.Sprintf("must be greater than or equal to %s", param), nil
+	}
+	if !isMin && actual > bound {
+		if unit != "" {
+			return fmt.Sprintf("must be at most %s%s long", param, unit), nil
+		}
+		return fmt.Sprintf("must be less than or equal to %s", param), nil
+	}
+	return "", nil
+}
+
+func checkOneOf(value reflect.Value, param string) string {
+	options := strings.Fields(param)
+	actual := fmt.Sprint(value.Interface())
+
+	for _, option := range options {
+		if actual == option {
+			return ""
+		}
+	}
+	return "must be one of: " + strings.Join(options, ", ")
+}
@@ -0,0 +1,85 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lunarKettle/task-management-platform-monolith/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type member struct {
+	ID uint32 `json:"id" validate:"required"`
+}
+
+type request struct {
+	Email    string   `json:"email" validate:"required,email"`
+	Password string   `json:"password" validate:"required,min=8"`
+	Status   string   `json:"status" validate:"omitempty,oneof=active closed"`
+	Budget   float64  `json:"budget" validate:"min=0"`
+	Members  []member `json:"members"`
+}
+
+func TestStructValid(t *testing.T) {
+	err := Struct(&request{
+		Email:    "user@example.com",
+		Password: "secret123",
+		Status:   "active",
+		Members:  []member{{ID: 1}},
+	})
+	assert.NoError(t, err)
+}
+
+func TestStructReportsEveryField(t *testing.T) {
+	err := Struct(&request{
+		Email:    "not-an-email",
+		Password: "short",
+		Status:   "archived",
+		Budget:   -1,
+		Members:  []member{{ID: 1}, {}},
+	})
+
+	assert.True(t, errors.Is(err, common.ErrValidation))
+
+	var errs Errors
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, Errors{
+		{Field: "email", Message: "must be a valid email address"},
+		{Field: "password", Message: "must be at least 8 characters long"},
+		{Field: "status", Message: "must be one of: active, closed"},
+		{Field: "budget", Message: "must be greater than or equal to 0"},
+		{Field: "members[1].id", Message: "is required"},
+	}, errs)
+}
+
+func TestStructRequired(t *testing.T) {
+	var errs Errors
+	err := Struct(&request{Email: "  "})
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, Errors{
+		{Field: "email", Message: "is required"},
+		{Field: "password", Message: "is required"},
+	}, errs)
+}
+
+type bounds struct {
+	Count  int    `json:"count" validate:"min=1"`
+	Email  string `json:"email" validate:"email"`
+	Note   string `json:"note" validate:"omitempty,min=3"`
+	Status string `json:"status" validate:"max=15"`
+}
+
+func TestStructChecksZeroValuesWithoutOmitempty(t *testing.T) {
+	var errs Errors
+	err := Struct(&bounds{})
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, Errors{
+		{Field: "count", Message: "must be greater than or equal to 1"},
+		{Field: "email", Message: "must be a valid email address"},
+	}, errs)
+
+	// С omitempty пустое значение пропускается, заполненное проверяется
+	err = Struct(&bounds{Count: 1, Email: "user@example.com", Note: "ab"})
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, Errors{{Field: "note", Message: "must be at least 3 characters long"}}, errs)
+}